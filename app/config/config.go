@@ -0,0 +1,246 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config represents the structure of the git-ninja configuration file
+type Config struct {
+	Ranking     RankingConfig     `json:"ranking"`
+	Reports     ReportsConfig     `json:"reports"`
+	Issues      IssuesConfig      `json:"issues"`
+	Jira        JiraConfig        `json:"jira"`
+	Linear      LinearConfig      `json:"linear"`
+	Forge       ForgeConfig       `json:"forge"`
+	GitHub      GitHubConfig      `json:"github"`
+	GitLab      GitLabConfig      `json:"gitlab"`
+	Gitea       GiteaConfig       `json:"gitea"`
+	Cache       CacheConfig       `json:"cache"`
+	Credentials CredentialsConfig `json:"credentials"`
+}
+
+// RankingConfig controls how branch listings are ordered
+type RankingConfig struct {
+	// Sort is the name of the ranking strategy, e.g. "recency" or "frecency"
+	Sort string `json:"sort"`
+	// HalfLife is a duration string such as "72h" used by decaying strategies
+	HalfLife string `json:"half_life"`
+}
+
+// ReportsConfig controls how time spent on branches is calculated
+type ReportsConfig struct {
+	// IdleLimit is a duration string such as "30m"; gaps in activity longer than this are not counted
+	IdleLimit string `json:"idle_limit"`
+}
+
+// IssuesConfig controls the issue tracker that branches are linked to
+type IssuesConfig struct {
+	// Tracker is the name of the issue tracker, "jira" or "linear"
+	Tracker string `json:"tracker"`
+	// Transitions controls the automatic status changes of issues linked to branches
+	Transitions TransitionsConfig `json:"transitions"`
+}
+
+// CredentialsConfig configures the external sources of integration credentials
+type CredentialsConfig struct {
+	// Helper is a command speaking git's credential helper protocol, e.g. "osxkeychain" or "!pass-helper"
+	Helper string `json:"helper"`
+}
+
+// CacheConfig controls how long data fetched from integrations such as JIRA is cached; the cache's defaults are used
+// when the durations are empty
+type CacheConfig struct {
+	// TTL is a duration string such as "5m"; cached data is fetched again once it is older than this
+	TTL string `json:"ttl"`
+	// MaxStale is a duration string such as "24h"; expired data is used for this long when it cannot be fetched again
+	MaxStale string `json:"max_stale"`
+}
+
+// JiraConfig controls the JIRA integration
+type JiraConfig struct {
+	// BaseURL is the URL of a Jira Cloud site or a Jira Server/Data Center instance
+	BaseURL string `json:"base_url"`
+	// Auth is "basic" (email and API token) or "bearer" (Personal Access Token)
+	Auth string `json:"auth"`
+	// APIVersion is the REST API version, "3" for Jira Cloud or "2" for Server/Data Center
+	APIVersion string `json:"api_version"`
+	// Queries maps profile names such as "mine" or "team" to JQL queries
+	Queries map[string]string `json:"queries"`
+	// DefaultQuery is the name of the profile used when none is specified
+	DefaultQuery string `json:"default_query"`
+	// IssueKeyPattern is a regular expression matching issue keys in branch names and commit messages
+	IssueKeyPattern string `json:"issue_key_pattern"`
+}
+
+// LinearConfig controls the Linear integration
+type LinearConfig struct {
+	// APIURL is the GraphQL endpoint, only changed to use a stand-in server
+	APIURL string `json:"api_url"`
+	// Teams limits the issue identifiers matched in branch names to those of the listed team keys, e.g. ["ENG"]
+	Teams []string `json:"teams"`
+	// CurrentCycle limits the active issues to those in their team's active cycle
+	CurrentCycle bool `json:"current_cycle"`
+}
+
+// ForgeConfig controls the forge hosting the repository, such as GitHub, GitLab or Gitea, which pull requests are
+// fetched from
+type ForgeConfig struct {
+	// Provider is the name of the forge, e.g. "github", "gitlab" or "gitea"; it is detected from the remote's host when empty
+	Provider string `json:"provider"`
+	// Remote is the name of the git remote whose repository is used
+	Remote string `json:"remote"`
+}
+
+// GitHubConfig controls the GitHub integration
+type GitHubConfig struct {
+	// BaseURL is the URL of a GitHub Enterprise Server instance, e.g. "https://github.example.com"
+	BaseURL string `json:"base_url"`
+}
+
+// GitLabConfig controls the GitLab integration
+type GitLabConfig struct {
+	// BaseURL is the URL of a self-managed GitLab instance, e.g. "https://gitlab.example.com"
+	BaseURL string `json:"base_url"`
+}
+
+// GiteaConfig controls the Gitea integration, which also serves Forgejo instances
+type GiteaConfig struct {
+	// BaseURL is the URL of the instance, e.g. "https://git.example.com"
+	BaseURL string `json:"base_url"`
+}
+
+// TransitionsConfig names the workflow transitions applied to the issue linked to a branch
+type TransitionsConfig struct {
+	// Enabled turns on the automatic transitions; they are off by default
+	Enabled bool `json:"enabled"`
+	// Start is applied when a branch is created or checked out for the first time
+	Start string `json:"start"`
+	// Review is applied when a branch is pushed with branch:current --push
+	Review string `json:"review"`
+}
+
+const DefaultHalfLife = 7 * 24 * time.Hour
+const DefaultIdleLimit = time.Hour
+
+var (
+	loaded     *Config
+	loadedErr  error
+	loadedOnce sync.Once
+)
+
+// Default returns a configuration populated with default values.
+func Default() *Config {
+	return &Config{
+		Ranking: RankingConfig{
+			HalfLife: DefaultHalfLife.String(),
+		},
+		Reports: ReportsConfig{
+			IdleLimit: DefaultIdleLimit.String(),
+		},
+		Issues: IssuesConfig{
+			Tracker: "jira",
+			Transitions: TransitionsConfig{
+				Start:  "In Progress",
+				Review: "In Review",
+			},
+		},
+		Jira: JiraConfig{
+			// the "mine" profile defaults to the JQL of the jira package, which is resolved by the commands
+			Queries: map[string]string{
+				"team": `sprint in openSprints() AND statusCategory IN ("To Do", "In Progress") ORDER BY updated DESC`,
+			},
+			DefaultQuery: "mine",
+		},
+		Linear: LinearConfig{
+			CurrentCycle: true,
+		},
+		Forge: ForgeConfig{
+			Remote: "origin",
+		},
+	}
+}
+
+// Load reads the configuration file, returning the defaults if it does not exist.
+func Load(fileName string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return Default(), fmt.Errorf("failed to parse config file %s: %v", fileName, err)
+	}
+
+	return cfg, nil
+}
+
+// Get returns the configuration loaded from the default location, reading it only once.
+// Invalid configuration files are reported on stderr and the defaults are used instead.
+func Get() *Config {
+	loadedOnce.Do(func() {
+		loaded, loadedErr = Load(ConfigFileName())
+		if loadedErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", loadedErr)
+		}
+	})
+
+	return loaded
+}
+
+// HalfLifeDuration returns the configured half-life, or the default if it is missing or invalid.
+func (r RankingConfig) HalfLifeDuration() time.Duration {
+	d, err := time.ParseDuration(r.HalfLife)
+	if err != nil || d <= 0 {
+		return DefaultHalfLife
+	}
+
+	return d
+}
+
+// IdleLimitDuration returns the configured idle limit, or the default if it is missing or invalid.
+func (r ReportsConfig) IdleLimitDuration() time.Duration {
+	d, err := time.ParseDuration(r.IdleLimit)
+	if err != nil || d <= 0 {
+		return DefaultIdleLimit
+	}
+
+	return d
+}
+
+// QueryNames returns the names of the configured query profiles, sorted.
+func (j JiraConfig) QueryNames() []string {
+	result := make([]string, 0, len(j.Queries))
+	for name := range j.Queries {
+		result = append(result, name)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// Query returns the JQL of the named query profile, or of the default profile if name is empty.
+func (j JiraConfig) Query(name string) (string, error) {
+	if name == "" {
+		name = j.DefaultQuery
+	}
+
+	jql, ok := j.Queries[name]
+	if !ok || strings.TrimSpace(jql) == "" {
+		return "", fmt.Errorf("unknown JIRA query profile '%s', expected one of: %s", name, strings.Join(j.QueryNames(), ", "))
+	}
+
+	return jql, nil
+}
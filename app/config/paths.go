@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const appDirName = "git-ninja"
+
+// xdgDir returns the directory named by the given XDG environment variable, falling back
+// to the provided path relative to the user's home directory.
+func xdgDir(envName string, homeRelative ...string) string {
+	if dir := os.Getenv(envName); dir != "" && filepath.IsAbs(dir) {
+		return filepath.Join(dir, appDirName)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = os.Getenv("HOME")
+	}
+
+	return filepath.Join(append(append([]string{home}, homeRelative...), appDirName)...)
+}
+
+// ConfigDir returns the directory containing git-ninja's configuration files.
+func ConfigDir() string {
+	return xdgDir("XDG_CONFIG_HOME", ".config")
+}
+
+// ConfigFileName returns the path of the configuration file, which can be overridden
+// using the GIT_NINJA_CONFIG environment variable.
+func ConfigFileName() string {
+	if fn := os.Getenv("GIT_NINJA_CONFIG"); fn != "" {
+		return fn
+	}
+
+	return filepath.Join(ConfigDir(), "config.json")
+}
+
+// CredentialsFileName returns the path of the file holding integration credentials.
+func CredentialsFileName() string {
+	return filepath.Join(ConfigDir(), "credentials.json")
+}
+
+// DataDir returns the directory where git-ninja stores persistent data, such as usage history.
+func DataDir() string {
+	return xdgDir("XDG_DATA_HOME", ".local", "share")
+}
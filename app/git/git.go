@@ -1,16 +1,15 @@
 package git
 
 import (
-	"regexp"
 	"strings"
 	"time"
 
 	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/reflog"
 	"github.com/permafrost-dev/git-ninja/app/utils"
 )
 
 type BranchInfo struct {
-	Rank            int64
 	Name            string
 	CheckoutCount   int
 	CommitCount     int
@@ -25,56 +24,6 @@ type BranchCheckoutInfo struct {
 	Timestamp    time.Time
 }
 
-func (b *BranchInfo) UpdateCheckoutCount() {
-	logitems, _ := GetRefLogItemsForBranch(b.Name)
-
-	b.CommitCount = 0
-	for _, item := range logitems {
-		if item.Action == "commit" {
-			b.CommitCount++
-		}
-	}
-}
-
-func (b *BranchInfo) UpdateScore() {
-	ageInHours := time.Since(b.CheckedOutLast).Hours() * -1
-	decay := 1000 - ageInHours // the older the checkout, the less it counts
-
-	b.Score = (float64)(b.CheckoutCount*b.CommitCount) * decay
-
-	if b.CommitCount == 0 {
-		b.Score = b.Score / 3.5
-	}
-}
-
-func (b *BranchInfo) Update() {
-	b.UpdateCheckoutCount()
-	b.UpdateScore()
-}
-
-func GetBranchInfoFromReflogLine(pattern *regexp.Regexp, reflogLine string, minMatchCount int) *BranchCheckoutInfo {
-	matches := pattern.FindStringSubmatch(reflogLine)
-
-	if matches == nil || len(matches) < minMatchCount {
-		return nil
-	}
-
-	return &BranchCheckoutInfo{
-		BranchName:   strings.TrimSpace(matches[3]),
-		RelativeTime: strings.TrimSpace(matches[4]),
-		Timestamp:    utils.ParseTimestampIntoTime(matches[1]),
-	}
-}
-
-func GetGitReflogLines(prettyFmt string) ([]string, error) {
-	output, err := helpers.RunCommandBuffered("git", "reflog", "show", "--pretty=format:'"+prettyFmt+"'", "--date=relative")
-	if err != nil {
-		return make([]string, 0), err
-	}
-
-	return strings.Split(output, "\n"), nil
-}
-
 func GetBranchReflogLines(name string, prettyFmt string) ([]string, error) {
 	output, err := helpers.RunCommandBuffered("git", "reflog", "--pretty=format:"+prettyFmt, name)
 	if err != nil {
@@ -84,16 +33,6 @@ func GetBranchReflogLines(name string, prettyFmt string) ([]string, error) {
 	return strings.Split(output, "\n"), nil
 }
 
-func SliceContainsBranchCommitData(slice []*BranchCheckoutInfo, info *BranchCheckoutInfo) bool {
-	for _, item := range slice {
-		if item.BranchName == info.BranchName {
-			return true
-		}
-	}
-
-	return false
-}
-
 type RefLogItem struct {
 	BranchName  string
 	CommitHash  string
@@ -102,6 +41,8 @@ type RefLogItem struct {
 	Timestamp   time.Time
 	Action      string
 	Message     string
+	// Detached reports whether the entry checked out a commit rather than a branch, detaching HEAD
+	Detached bool
 }
 
 func GetRefLogItemsForBranch(branchName string) ([]*RefLogItem, error) {
@@ -140,3 +81,89 @@ func GetRefLogItemsForBranch(branchName string) ([]*RefLogItem, error) {
 
 	return result, nil
 }
+
+// GetHeadRefLogItems returns the entries of the HEAD reflog, newest first. Unlike the entries returned by
+// GetRefLogItemsForBranch, the timestamps are the times the reflog entries were written. For checkouts,
+// BranchName is the name of the branch that was checked out; for all other entries it is empty.
+func GetHeadRefLogItems() ([]*RefLogItem, error) {
+	return GetHeadRefLogItemsInDir("")
+}
+
+// GetHeadRefLogItemsInDir returns the entries of the HEAD reflog of the repository at repoPath, or of the
+// current repository if repoPath is empty.
+func GetHeadRefLogItemsInDir(repoPath string) ([]*RefLogItem, error) {
+	return getTimedRefLogItems(repoPath, "HEAD")
+}
+
+// GetPushTimes returns the times that the remote-tracking branch was updated by a push, newest first.
+func GetPushTimes(remoteName string, branchName string) ([]time.Time, error) {
+	items, err := getTimedRefLogItems("", "refs/remotes/"+remoteName+"/"+branchName)
+	if err != nil {
+		return make([]time.Time, 0), err
+	}
+
+	result := make([]time.Time, 0)
+	for _, item := range items {
+		if item.Message == "update by push" {
+			result = append(result, item.Timestamp)
+		}
+	}
+
+	return result, nil
+}
+
+// getTimedRefLogItems returns the reflog entries of a ref, newest first, with the times the entries were written.
+// The reflog files are read through the repository's reflog cache.
+func getTimedRefLogItems(repoPath string, ref string) ([]*RefLogItem, error) {
+	repo, err := reflog.Open(repoPath)
+	if err != nil {
+		return make([]*RefLogItem, 0), err
+	}
+	defer repo.Close()
+
+	var entries []reflog.Entry
+	if ref == "HEAD" {
+		entries, err = repo.HeadEntries()
+	} else {
+		entries, err = repo.RefEntries(ref)
+	}
+
+	if err != nil {
+		return make([]*RefLogItem, 0), err
+	}
+
+	result := make([]*RefLogItem, 0, len(entries))
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+
+		result = append(result, &RefLogItem{
+			BranchName:  entry.CheckedOutBranch(),
+			CommitHash:  entry.NewHash,
+			AuthorName:  entry.Name,
+			AuthorEmail: entry.Email,
+			Timestamp:   entry.Timestamp,
+			Action:      entry.Action(),
+			Message:     entry.Message,
+			Detached:    entry.DetachedHEAD(),
+		})
+	}
+
+	return result, nil
+}
+
+const rebaseFinishedPrefix = "rebase (finish): returning to refs/heads/"
+
+// SwitchedToBranch returns the name of the branch that HEAD points to after this HEAD reflog entry if the entry
+// switched branches, i.e. a checkout or a finished rebase, or an empty string otherwise.
+func (r *RefLogItem) SwitchedToBranch() string {
+	if r.BranchName != "" {
+		return r.BranchName
+	}
+
+	if strings.HasPrefix(r.Message, rebaseFinishedPrefix) {
+		return strings.TrimPrefix(r.Message, rebaseFinishedPrefix)
+	}
+
+	return ""
+}
@@ -0,0 +1,308 @@
+package git
+
+import (
+	"container/heap"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	g "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	object "github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// maxGraphWalk limits the number of commits visited when counting commits ahead of or behind a fork point
+const maxGraphWalk = 5000
+
+// GraphBranch is a branch in a BranchGraph
+type GraphBranch struct {
+	Name     string
+	Tip      plumbing.Hash
+	ForkedAt plumbing.Hash
+	Ahead    int
+	// Truncated reports whether counting the commits ahead stopped at maxGraphWalk, making Ahead approximate: it
+	// misses commits beyond the limit, and can include commits reachable from the default branch beyond it
+	Truncated bool
+	Parent    *GraphBranch
+	Children  []*GraphBranch
+
+	commits map[plumbing.Hash]bool
+}
+
+// ForkPoint is a commit on the default branch that one or more branches forked from
+type ForkPoint struct {
+	Hash   plumbing.Hash
+	Behind int
+	// Truncated reports whether counting the commits behind stopped at maxGraphWalk, making Behind approximate like
+	// GraphBranch.Ahead
+	Truncated bool
+	Branches  []*GraphBranch
+}
+
+// BranchGraph describes where a set of branches forked from the default branch and how they are stacked
+type BranchGraph struct {
+	DefaultBranch string
+	DefaultTip    plumbing.Hash
+	ForkPoints    []*ForkPoint
+}
+
+// commitQueue is a queue of commits ordered from the most recently committed
+type commitQueue []*object.Commit
+
+func (q commitQueue) Len() int           { return len(q) }
+func (q commitQueue) Less(i, j int) bool { return q[i].Committer.When.After(q[j].Committer.When) }
+func (q commitQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *commitQueue) Push(x any)        { *q = append(*q, x.(*object.Commit)) }
+func (q *commitQueue) Pop() any {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+// Flags marking the side a commit was reached from while walking the history
+const (
+	reachedFromTip = 1 << iota
+	reachedFromExcluded
+)
+
+// walkCommits returns the commits reachable from tip that aren't reachable from exclude, like
+// `git rev-list exclude..tip`. Both histories are walked together from the most recent commits, until only
+// commits reachable from exclude are left to visit. The walk stops after maxGraphWalk commits, in which case the
+// result is approximate and truncated is true.
+func walkCommits(tip, exclude *object.Commit) (commits map[plumbing.Hash]bool, truncated bool, err error) {
+	flags := map[plumbing.Hash]int{tip.Hash: reachedFromTip}
+	flags[exclude.Hash] |= reachedFromExcluded
+
+	queue := &commitQueue{tip, exclude}
+	heap.Init(queue)
+
+	// walking ends once every queued commit is known to be reachable from exclude
+	interesting := func() bool {
+		for _, c := range *queue {
+			if flags[c.Hash]&reachedFromExcluded == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	for visited := 0; queue.Len() > 0 && interesting(); visited++ {
+		if visited >= maxGraphWalk {
+			truncated = true
+			break
+		}
+
+		c := heap.Pop(queue).(*object.Commit)
+		marks := flags[c.Hash]
+
+		err := c.Parents().ForEach(func(parent *object.Commit) error {
+			previous := flags[parent.Hash]
+			if previous|marks == previous {
+				return nil
+			}
+
+			// a commit already queued is queued again, to carry the new mark to its own parents
+			flags[parent.Hash] = previous | marks
+			heap.Push(queue, parent)
+
+			return nil
+		})
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	commits = make(map[plumbing.Hash]bool)
+	for hash, marks := range flags {
+		if marks == reachedFromTip {
+			commits[hash] = true
+		}
+	}
+
+	return commits, truncated, nil
+}
+
+func resolveBranchCommit(repo *g.Repository, name string) (*object.Commit, error) {
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName(name), true)
+	if err != nil {
+		ref, err = repo.Reference(plumbing.ReferenceName("refs/remotes/"+name), true)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve branch %s: %v", name, err)
+	}
+
+	return repo.CommitObject(ref.Hash())
+}
+
+// BuildBranchGraph computes the fork point of each branch from the default branch using merge-base
+// calculations, the number of commits each branch carries, and which branches are stacked on others.
+func BuildBranchGraph(repoPath string, defaultBranch string, branches []string) (*BranchGraph, error) {
+	repo, err := g.PlainOpenWithOptions(repoPath, &g.PlainOpenOptions{DetectDotGit: true, EnableDotGitCommonDir: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	defaultTip, err := resolveBranchCommit(repo, defaultBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &BranchGraph{DefaultBranch: defaultBranch, DefaultTip: defaultTip.Hash}
+	forkPoints := make(map[plumbing.Hash]*ForkPoint)
+	nodes := make([]*GraphBranch, 0, len(branches))
+
+	for _, name := range branches {
+		tip, err := resolveBranchCommit(repo, name)
+		if err != nil {
+			continue
+		}
+
+		bases, err := tip.MergeBase(defaultTip)
+		if err != nil || len(bases) == 0 {
+			continue
+		}
+		base := bases[0]
+
+		forkPoint, ok := forkPoints[base.Hash]
+		if !ok {
+			// commits on the default branch since the fork point
+			behind, truncated, err := walkCommits(defaultTip, base)
+			if err != nil {
+				return nil, err
+			}
+
+			forkPoint = &ForkPoint{Hash: base.Hash, Behind: len(behind), Truncated: truncated}
+			forkPoint.Branches = make([]*GraphBranch, 0)
+			forkPoints[base.Hash] = forkPoint
+		}
+
+		// the branch's own commits are those the default branch can't reach, including through merges
+		commits, truncated, err := walkCommits(tip, defaultTip)
+		if err != nil {
+			return nil, err
+		}
+
+		node := &GraphBranch{Name: name, Tip: tip.Hash, ForkedAt: base.Hash, Ahead: len(commits), Truncated: truncated, commits: commits}
+		nodes = append(nodes, node)
+		forkPoint.Branches = append(forkPoint.Branches, node)
+	}
+
+	linkStackedBranches(nodes)
+
+	for _, forkPoint := range forkPoints {
+		roots := make([]*GraphBranch, 0, len(forkPoint.Branches))
+		for _, node := range forkPoint.Branches {
+			if node.Parent == nil {
+				roots = append(roots, node)
+			}
+		}
+		forkPoint.Branches = roots
+		graph.ForkPoints = append(graph.ForkPoints, forkPoint)
+	}
+
+	sort.Slice(graph.ForkPoints, func(i, j int) bool {
+		return graph.ForkPoints[i].Behind < graph.ForkPoints[j].Behind
+	})
+
+	return graph, nil
+}
+
+// linkStackedBranches makes each branch a child of the closest branch whose tip is one of its own commits.
+func linkStackedBranches(nodes []*GraphBranch) {
+	for _, node := range nodes {
+		for _, other := range nodes {
+			if other == node || other.Ahead == 0 || other.Tip == node.Tip || !node.commits[other.Tip] {
+				continue
+			}
+
+			// prefer the branch closest to this one, i.e. the one with the most commits
+			if node.Parent == nil || other.Ahead > node.Parent.Ahead {
+				node.Parent = other
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		if node.Parent != nil {
+			node.Ahead -= node.Parent.Ahead
+			node.Truncated = node.Truncated || node.Parent.Truncated
+			node.Parent.Children = append(node.Parent.Children, node)
+		}
+	}
+}
+
+// GraphStyle holds the characters used to draw a BranchGraph
+type GraphStyle struct {
+	Branch, LastBranch, Pipe, Space string
+}
+
+var UnicodeGraphStyle = GraphStyle{Branch: "├── ", LastBranch: "└── ", Pipe: "│   ", Space: "    "}
+var ASCIIGraphStyle = GraphStyle{Branch: "|-- ", LastBranch: "`-- ", Pipe: "|   ", Space: "    "}
+
+func shortHash(hash plumbing.Hash) string {
+	return hash.String()[:7]
+}
+
+// Render draws the graph as a tree. The label function returns extra text displayed after each branch.
+func (bg *BranchGraph) Render(w io.Writer, style GraphStyle, label func(*GraphBranch) string) {
+	fmt.Fprintf(w, "\033[37;1m%s\033[0m \033[2m%s\033[0m\n", bg.DefaultBranch, shortHash(bg.DefaultTip))
+
+	for i, forkPoint := range bg.ForkPoints {
+		last := i == len(bg.ForkPoints)-1
+		connector, indent := style.Branch, style.Pipe
+		if last {
+			connector, indent = style.LastBranch, style.Space
+		}
+
+		behind := "at tip"
+		if forkPoint.Behind > 0 {
+			behind = countLabel(forkPoint.Behind, forkPoint.Truncated) + " behind"
+		}
+
+		fmt.Fprintf(w, "%s\033[2mforked at %s (%s)\033[0m\n", connector, shortHash(forkPoint.Hash), behind)
+		renderGraphBranches(w, forkPoint.Branches, indent, style, label)
+	}
+}
+
+// countLabel formats a number of commits, noting when counting stopped at the walk limit and the count is approximate.
+func countLabel(count int, truncated bool) string {
+	if truncated {
+		return fmt.Sprintf("~%d", count)
+	}
+
+	return fmt.Sprintf("%d", count)
+}
+
+func renderGraphBranches(w io.Writer, branches []*GraphBranch, prefix string, style GraphStyle, label func(*GraphBranch) string) {
+	sort.SliceStable(branches, func(i, j int) bool {
+		return branches[i].Name < branches[j].Name
+	})
+
+	for i, branch := range branches {
+		connector, indent := style.Branch, style.Pipe
+		if i == len(branches)-1 {
+			connector, indent = style.LastBranch, style.Space
+		}
+
+		fmt.Fprintf(w, "%s%s\033[37;1m%s\033[0m \033[32m+%s\033[0m%s\n", prefix, connector, branch.Name, countLabel(branch.Ahead, branch.Truncated), label(branch))
+		renderGraphBranches(w, branch.Children, prefix+indent, style, label)
+	}
+}
+
+// StackedOn returns the names of the branches this branch is stacked on, nearest first.
+func (b *GraphBranch) StackedOn() []string {
+	result := make([]string, 0)
+
+	for parent := b.Parent; parent != nil; parent = parent.Parent {
+		result = append(result, parent.Name)
+	}
+
+	return result
+}
+
+// String returns a short description of the branch, for debugging.
+func (b *GraphBranch) String() string {
+	return fmt.Sprintf("%s (+%s, forked at %s, stacked on %s)", b.Name, countLabel(b.Ahead, b.Truncated), shortHash(b.ForkedAt), strings.Join(b.StackedOn(), ", "))
+}
@@ -0,0 +1,153 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testRepo is a repository created with the git command, whose commits are dated one minute apart
+type testRepo struct {
+	t       *testing.T
+	dir     string
+	commits int
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+
+	r := &testRepo{t: t, dir: t.TempDir()}
+	r.git("init", "--quiet", "--initial-branch=main")
+
+	return r
+}
+
+func (r *testRepo) git(args ...string) string {
+	r.t.Helper()
+
+	date := fmt.Sprintf("2024-01-01T09:%02d:00Z", r.commits)
+	cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = r.dir
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date, "GIT_CONFIG_GLOBAL=/dev/null")
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+
+	return strings.TrimSpace(string(output))
+}
+
+// commit adds n empty commits to the current branch.
+func (r *testRepo) commit(n int) {
+	r.t.Helper()
+
+	for i := 0; i < n; i++ {
+		r.commits++
+		r.git("commit", "--quiet", "--allow-empty", "-m", "commit "+strconv.Itoa(r.commits))
+	}
+}
+
+// merge merges a branch into the current branch with a merge commit.
+func (r *testRepo) merge(branch string) {
+	r.t.Helper()
+
+	r.commits++
+	r.git("merge", "--quiet", "--no-ff", "-m", "merge "+branch, branch)
+}
+
+// count returns the number of commits in a revision range, according to git.
+func (r *testRepo) count(revisions string) int {
+	r.t.Helper()
+
+	count, _ := strconv.Atoi(r.git("rev-list", "--count", revisions))
+	return count
+}
+
+func findGraphBranch(graph *BranchGraph, name string) (*ForkPoint, *GraphBranch) {
+	var find func(branches []*GraphBranch) *GraphBranch
+	find = func(branches []*GraphBranch) *GraphBranch {
+		for _, branch := range branches {
+			if branch.Name == name {
+				return branch
+			}
+			if found := find(branch.Children); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+
+	for _, forkPoint := range graph.ForkPoints {
+		if branch := find(forkPoint.Branches); branch != nil {
+			return forkPoint, branch
+		}
+	}
+
+	return nil, nil
+}
+
+func TestBuildBranchGraphCountsLikeRevList(t *testing.T) {
+	r := newTestRepo(t)
+	r.commit(3)
+
+	// a branch that merged the default branch back in after forking
+	r.git("checkout", "--quiet", "-b", "feat")
+	r.commit(2)
+	r.git("checkout", "--quiet", "main")
+	r.commit(10)
+	r.git("checkout", "--quiet", "feat")
+	r.merge("main")
+	r.commit(1)
+
+	// a branch already merged into the default branch
+	r.git("checkout", "--quiet", "-b", "done", "main~4")
+	r.commit(2)
+	r.git("checkout", "--quiet", "main")
+	r.merge("done")
+	r.commit(1)
+
+	// a branch stacked on another
+	r.git("checkout", "--quiet", "-b", "base")
+	r.commit(2)
+	r.git("checkout", "--quiet", "-b", "stacked")
+	r.commit(3)
+
+	graph, err := BuildBranchGraph(r.dir, "main", []string{"feat", "done", "base", "stacked"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		branch, parent string
+		ahead, behind  int
+	}{
+		{"feat", "", r.count("main..feat"), r.count("feat..main")},
+		{"done", "", 0, r.count("done..main")},
+		{"base", "", r.count("main..base"), 0},
+		{"stacked", "base", r.count("base..stacked"), 0},
+	}
+
+	for _, test := range tests {
+		forkPoint, branch := findGraphBranch(graph, test.branch)
+		if branch == nil {
+			t.Errorf("%s: not in the graph", test.branch)
+			continue
+		}
+
+		if branch.Ahead != test.ahead || branch.Truncated {
+			t.Errorf("%s: expected %d commits ahead, got %d (truncated: %v)", test.branch, test.ahead, branch.Ahead, branch.Truncated)
+		}
+
+		if forkPoint.Behind != test.behind || forkPoint.Truncated {
+			t.Errorf("%s: expected its fork point %d commits behind, got %d (truncated: %v)", test.branch, test.behind, forkPoint.Behind, forkPoint.Truncated)
+		}
+
+		if parent := strings.Join(branch.StackedOn(), ","); parent != test.parent {
+			t.Errorf("%s: expected to be stacked on %q, got %q", test.branch, test.parent, parent)
+		}
+	}
+}
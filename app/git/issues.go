@@ -0,0 +1,180 @@
+package git
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/utils"
+)
+
+// Places where issue keys are found
+const (
+	IssueSourceLocal  = "local"
+	IssueSourceRemote = "remote"
+	IssueSourceReflog = "reflog"
+	IssueSourceCommit = "commit"
+)
+
+// IssueReference is an issue key found in a branch name, the checkout history, or a commit message
+type IssueReference struct {
+	Key    string `json:"key"`
+	Source string `json:"source"`
+	// Branch is the branch named with the key, or the branch through which a commit was found
+	Branch    string    `json:"branch"`
+	Commit    string    `json:"commit,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// maxIssueCommits limits the number of commits scanned for issue keys
+const maxIssueCommits = 5000
+
+// FindIssueReferences finds the issue keys in the names of local and remote branches, in the names of branches
+// that were checked out but no longer exist, and in commit messages. When key is not empty, only references to
+// that issue are returned. extractKeys returns the issue keys found in a string.
+func FindIssueReferences(repoPath string, key string, extractKeys func(string) []string) ([]IssueReference, error) {
+	matching := func(s string) []string {
+		keys := extractKeys(s)
+		if key == "" {
+			return keys
+		}
+
+		for _, k := range keys {
+			if k == key {
+				return []string{k}
+			}
+		}
+
+		return nil
+	}
+
+	result, err := FindRefIssueReferences(repoPath, matching)
+	if err != nil {
+		return nil, err
+	}
+
+	localBranches := make(map[string]bool)
+	for _, ref := range result {
+		if ref.Source == IssueSourceLocal {
+			localBranches[ref.Branch] = true
+		}
+	}
+
+	// branches that were checked out but have since been deleted
+	items, _ := GetHeadRefLogItemsInDir(repoPath)
+	seen := make(map[string]bool)
+
+	for _, item := range items {
+		if item.BranchName == "" || seen[item.BranchName] || localBranches[item.BranchName] {
+			continue
+		}
+		seen[item.BranchName] = true
+
+		for _, k := range matching(item.BranchName) {
+			result = append(result, IssueReference{Key: k, Source: IssueSourceReflog, Branch: item.BranchName, Commit: item.CommitHash, Timestamp: item.Timestamp})
+		}
+	}
+
+	args := []string{"log", "--all", "--source", "--format=%H%x09%S%x09%ct%x09%s", "-n", strconv.Itoa(maxIssueCommits)}
+	if key != "" {
+		args = append(args, "--fixed-strings", "--grep="+key)
+	}
+
+	commits, err := FindCommitIssueReferences(repoPath, args, matching)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(result, commits...), nil
+}
+
+// FindRefIssueReferences finds the issue keys in the names of local and remote branches.
+func FindRefIssueReferences(repoPath string, extractKeys func(string) []string) ([]IssueReference, error) {
+	output, err := utils.RunCommand("git", helpers.GitArgs(repoPath, "for-each-ref", "--format=%(refname)|%(committerdate:unix)", "refs/heads", "refs/remotes")...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]IssueReference, 0)
+
+	for _, line := range strings.Split(output, "\n") {
+		ref, timestamp, found := strings.Cut(strings.TrimSpace(line), "|")
+		if !found || strings.HasSuffix(ref, "/HEAD") {
+			continue
+		}
+
+		source, name := IssueSourceLocal, strings.TrimPrefix(ref, "refs/heads/")
+		if strings.HasPrefix(ref, "refs/remotes/") {
+			source, name = IssueSourceRemote, strings.TrimPrefix(ref, "refs/remotes/")
+		}
+
+		for _, k := range extractKeys(name) {
+			result = append(result, IssueReference{Key: k, Source: source, Branch: name, Timestamp: utils.ParseTimestampIntoTime(timestamp)})
+		}
+	}
+
+	return result, nil
+}
+
+// FindBranchIssueReferences finds the issue keys in a branch's name and in the messages of the commits on the
+// branch that are not on base.
+func FindBranchIssueReferences(repoPath string, base string, branch string, extractKeys func(string) []string) ([]IssueReference, error) {
+	result := make([]IssueReference, 0)
+
+	tipTimestamp, _ := utils.RunCommand("git", helpers.GitArgs(repoPath, "log", "-1", "--format=%ct", branch, "--")...)
+
+	for _, k := range extractKeys(branch) {
+		result = append(result, IssueReference{Key: k, Source: IssueSourceLocal, Branch: branch, Timestamp: utils.ParseTimestampIntoTime(tipTimestamp)})
+	}
+
+	revisions := branch
+	if base != "" && base != branch {
+		revisions = base + ".." + branch
+	}
+
+	commits, err := FindCommitIssueReferences(repoPath, []string{"log", "--format=%H%x09%x09%ct%x09%s", "-n", strconv.Itoa(maxIssueCommits), revisions, "--"}, extractKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range commits {
+		commits[i].Branch = branch
+	}
+
+	return append(result, commits...), nil
+}
+
+// FindCommitIssueReferences runs git log with the given arguments, which must produce lines of the form
+// "<hash>\t<branch>\t<unix-timestamp>\t<subject>", and returns the issue keys found in the subjects.
+func FindCommitIssueReferences(repoPath string, logArgs []string, extractKeys func(string) []string) ([]IssueReference, error) {
+	output, err := utils.RunCommand("git", helpers.GitArgs(repoPath, logArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]IssueReference, 0)
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\t", 4)
+		if len(fields) != 4 {
+			continue
+		}
+
+		branch := strings.TrimPrefix(strings.TrimPrefix(fields[1], "refs/heads/"), "refs/remotes/")
+
+		for _, k := range extractKeys(fields[3]) {
+			result = append(result, IssueReference{
+				Key:       k,
+				Source:    IssueSourceCommit,
+				Branch:    branch,
+				Commit:    fields[0],
+				Message:   fields[3],
+				Timestamp: utils.ParseTimestampIntoTime(fields[2]),
+			})
+		}
+	}
+
+	return result, nil
+}
@@ -3,6 +3,7 @@ package helpers
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -71,12 +72,34 @@ func GetCurrentBranchName() (string, error) {
 	result = strings.TrimSpace(result)
 
 	if strings.Contains(result, " ") {
-		return "", fmt.Errorf(result)
+		return "", errors.New(result)
 	}
 
 	return result, err
 }
 
+// GetRepositoryRoot returns the absolute path of the top-level directory of the current repository.
+func GetRepositoryRoot() (string, error) {
+	return utils.RunCommand("git", "rev-parse", "--show-toplevel")
+}
+
+// GetDefaultBranchName returns the default branch of the repository, as reported by the remote's HEAD,
+// falling back to "main" or "master" when they exist locally.
+func GetDefaultBranchName(remote string) (string, error) {
+	result, err := utils.RunCommand("git", "symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err == nil && strings.TrimSpace(result) != "" {
+		return strings.TrimPrefix(strings.TrimSpace(result), remote+"/"), nil
+	}
+
+	for _, name := range []string{"main", "master"} {
+		if exists, _ := BranchExists(name); exists {
+			return name, nil
+		}
+	}
+
+	return "", errors.New("could not determine the default branch")
+}
+
 func BranchExists(name string) (bool, error) {
 	var out bytes.Buffer
 
@@ -105,9 +128,24 @@ func BranchExists(name string) (bool, error) {
 	return false, nil
 }
 
+// GitArgs prefixes git arguments with "-C repoPath" when repoPath is not empty, so the command
+// runs against that repository instead of the current one.
+func GitArgs(repoPath string, args ...string) []string {
+	if repoPath == "" {
+		return args
+	}
+
+	return append([]string{"-C", repoPath}, args...)
+}
+
 // getAvailableBranches fetches and returns a map of all available branches in the repository
 func GetAvailableBranchesMap() (map[string]bool, error) {
-	cmd := exec.Command("git", "branch", "--list")
+	return GetAvailableBranchesMapInDir("")
+}
+
+// GetAvailableBranchesMapInDir returns a map of all available branches in the repository at repoPath.
+func GetAvailableBranchesMapInDir(repoPath string) (map[string]bool, error) {
+	cmd := exec.Command("git", GitArgs(repoPath, "branch", "--list")...)
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	err := cmd.Run()
@@ -127,3 +165,95 @@ func GetAvailableBranchesMap() (map[string]bool, error) {
 
 	return branchMap, nil
 }
+
+// GetUpstreamBranchName returns the upstream of a branch, e.g. "origin/feature", or an empty string if it has none.
+func GetUpstreamBranchName(branch string) string {
+	result, err := utils.RunCommand("git", "rev-parse", "--abbrev-ref", "--symbolic-full-name", branch+"@{upstream}")
+	if err != nil {
+		return ""
+	}
+
+	return result
+}
+
+// GetAheadBehind returns the number of commits on branch that are not on base, and the number on base that are not on branch.
+func GetAheadBehind(base string, branch string) (ahead int, behind int, err error) {
+	result, err := utils.RunCommand("git", "rev-list", "--left-right", "--count", base+"..."+branch)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if _, err := fmt.Sscanf(result, "%d %d", &behind, &ahead); err != nil {
+		return 0, 0, fmt.Errorf("unexpected output of git rev-list: %s", result)
+	}
+
+	return ahead, behind, nil
+}
+
+// GetBranchTip returns the hash of the commit a branch points to.
+func GetBranchTip(branch string) (string, error) {
+	return utils.RunCommand("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+}
+
+// IsAncestor reports whether commit is an ancestor of, or the same as, descendant. It is false when either is unknown.
+func IsAncestor(commit string, descendant string) bool {
+	return exec.Command("git", "merge-base", "--is-ancestor", commit, descendant).Run() == nil
+}
+
+// GetFirstParentCommits returns the hashes of the commits made on branch itself, i.e. those on its first-parent
+// history, excluding the commits of branches merged into it.
+func GetFirstParentCommits(branch string) (map[string]bool, error) {
+	result, err := utils.RunCommand("git", "rev-list", "--first-parent", branch)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make(map[string]bool)
+	for _, line := range strings.Split(result, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			commits[line] = true
+		}
+	}
+
+	return commits, nil
+}
+
+// GetMergedBranchNames returns the local branches whose tips are reachable from target, including target itself.
+func GetMergedBranchNames(target string) ([]string, error) {
+	result, err := utils.RunCommand("git", "branch", "--merged", target, "--format=%(refname:short)")
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0)
+	for _, line := range strings.Split(result, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			names = append(names, line)
+		}
+	}
+
+	return names, nil
+}
+
+// GetCommitSubjects returns the subjects of the commits on branch that are not on base, i.e. those since their
+// merge base, oldest first.
+func GetCommitSubjects(base string, branch string) ([]string, error) {
+	result, err := utils.RunCommand("git", "log", "--reverse", "--no-merges", "--format=%s", base+".."+branch)
+	if err != nil {
+		return nil, err
+	}
+
+	subjects := make([]string, 0)
+	for _, line := range strings.Split(result, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			subjects = append(subjects, line)
+		}
+	}
+
+	return subjects, nil
+}
+
+// RemoteBranchExists reports whether the remote-tracking branch of a branch on remote exists, e.g. "origin/feature".
+func RemoteBranchExists(remote string, branch string) bool {
+	return exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/remotes/"+remote+"/"+branch).Run() == nil
+}
@@ -0,0 +1,84 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Format is the output format of a report or listing
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatCSV   Format = "csv"
+	FormatJSON  Format = "json"
+)
+
+// Formats is the list of supported output formats, for use in flag descriptions
+var Formats = []string{string(FormatTable), string(FormatCSV), string(FormatJSON)}
+
+// ParseFormat validates a format name.
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(name)) {
+	case FormatTable:
+		return FormatTable, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	}
+
+	return "", fmt.Errorf("unknown output format '%s', expected one of: %s", name, strings.Join(Formats, ", "))
+}
+
+// Table is a set of rows with column headers
+type Table struct {
+	Headers []string
+	Rows    [][]string
+}
+
+// AddRow appends a row to the table.
+func (t *Table) AddRow(values ...string) {
+	t.Rows = append(t.Rows, values)
+}
+
+// WriteText writes the table with aligned columns.
+func (t *Table) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "  "+strings.Join(t.Headers, "\t"))
+	for _, row := range t.Rows {
+		fmt.Fprintln(tw, "  "+strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// WriteCSV writes the table as CSV, including a header row.
+func (t *Table) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(t.Headers); err != nil {
+		return err
+	}
+
+	if err := cw.WriteAll(t.Rows); err != nil {
+		return err
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// WriteJSON writes value as indented JSON.
+func WriteJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
@@ -0,0 +1,127 @@
+package ranking
+
+import (
+	"math"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/app/git"
+)
+
+// decay returns a weight between 0 and 1 that halves every halfLife.
+func decay(age time.Duration, halfLife time.Duration) float64 {
+	if age < 0 {
+		age = 0
+	}
+
+	return math.Pow(0.5, age.Hours()/halfLife.Hours())
+}
+
+// RecencyRanker ranks branches by how recently they were checked out
+type RecencyRanker struct{}
+
+func (r *RecencyRanker) Name() string {
+	return "recency"
+}
+
+func (r *RecencyRanker) Score(b *git.BranchInfo, now time.Time) Score {
+	ageInHours := now.Sub(b.CheckedOutLast).Hours()
+
+	return Score{
+		Total:      -ageInHours,
+		Components: []Component{{Name: "age_hours", Value: ageInHours}},
+	}
+}
+
+// FrequencyRanker ranks branches by the number of times they were checked out
+type FrequencyRanker struct{}
+
+func (r *FrequencyRanker) Name() string {
+	return "frequency"
+}
+
+func (r *FrequencyRanker) Score(b *git.BranchInfo, now time.Time) Score {
+	return Score{
+		Total:      float64(b.CheckoutCount),
+		Components: []Component{{Name: "checkouts", Value: float64(b.CheckoutCount)}},
+	}
+}
+
+// FrecencyRanker combines frequency and recency in the style of Mozilla's frecency algorithm:
+// each checkout contributes 100 points, decayed exponentially by its age.
+type FrecencyRanker struct {
+	HalfLife time.Duration
+}
+
+func (r *FrecencyRanker) Name() string {
+	return "frecency"
+}
+
+func (r *FrecencyRanker) Score(b *git.BranchInfo, now time.Time) Score {
+	total := 0.0
+	for _, checkout := range b.CheckoutHistory {
+		total += 100 * decay(now.Sub(checkout.Timestamp), r.HalfLife)
+	}
+
+	// branches without a recorded history still count their last checkout
+	if len(b.CheckoutHistory) == 0 && !b.CheckedOutLast.IsZero() {
+		total = 100 * decay(now.Sub(b.CheckedOutLast), r.HalfLife)
+	}
+
+	return Score{
+		Total: total,
+		Components: []Component{
+			{Name: "visits", Value: float64(max(len(b.CheckoutHistory), b.CheckoutCount))},
+			{Name: "half_life_hours", Value: r.HalfLife.Hours()},
+		},
+	}
+}
+
+// CommitWeightedRanker ranks branches by checkouts multiplied by commits, decayed by the age
+// of the last checkout. Branches without commits are penalized.
+type CommitWeightedRanker struct {
+	HalfLife time.Duration
+}
+
+func (r *CommitWeightedRanker) Name() string {
+	return "commits"
+}
+
+func (r *CommitWeightedRanker) Score(b *git.BranchInfo, now time.Time) Score {
+	weight := float64(b.CheckoutCount * b.CommitCount)
+	if b.CommitCount == 0 {
+		weight = float64(b.CheckoutCount) / 3.5
+	}
+
+	recency := decay(now.Sub(b.CheckedOutLast), r.HalfLife)
+
+	return Score{
+		Total: weight * recency * 100,
+		Components: []Component{
+			{Name: "checkouts", Value: float64(b.CheckoutCount)},
+			{Name: "commits", Value: float64(b.CommitCount)},
+			{Name: "decay", Value: recency},
+		},
+	}
+}
+
+// boostedRanker adds an extra score component on top of another ranker
+type boostedRanker struct {
+	Ranker
+	name  string
+	boost func(b *git.BranchInfo) float64
+}
+
+// WithBoost wraps a ranker, adding the value returned by boost to every score as a named component.
+func WithBoost(r Ranker, name string, boost func(b *git.BranchInfo) float64) Ranker {
+	return &boostedRanker{Ranker: r, name: name, boost: boost}
+}
+
+func (r *boostedRanker) Score(b *git.BranchInfo, now time.Time) Score {
+	score := r.Ranker.Score(b, now)
+	value := r.boost(b)
+
+	score.Total += value
+	score.Components = append(score.Components, Component{Name: r.name, Value: value})
+
+	return score
+}
@@ -0,0 +1,105 @@
+package ranking
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/app/git"
+)
+
+// Component is a single named contribution to a branch's score
+type Component struct {
+	Name  string
+	Value float64
+}
+
+// Score is the result of ranking a branch; higher totals rank first
+type Score struct {
+	Total      float64
+	Components []Component
+}
+
+// Ranker assigns a score to a branch based on its checkout and commit history
+type Ranker interface {
+	Name() string
+	Score(b *git.BranchInfo, now time.Time) Score
+}
+
+// Ranked pairs a branch with its computed score
+type Ranked struct {
+	Branch git.BranchInfo
+	Score  Score
+}
+
+var constructors = map[string]func(cfg config.RankingConfig) Ranker{
+	"recency":   func(cfg config.RankingConfig) Ranker { return &RecencyRanker{} },
+	"frequency": func(cfg config.RankingConfig) Ranker { return &FrequencyRanker{} },
+	"frecency":  func(cfg config.RankingConfig) Ranker { return &FrecencyRanker{HalfLife: cfg.HalfLifeDuration()} },
+	"commits":   func(cfg config.RankingConfig) Ranker { return &CommitWeightedRanker{HalfLife: cfg.HalfLifeDuration()} },
+}
+
+// Names returns the names of all available ranking strategies.
+func Names() []string {
+	names := make([]string, 0, len(constructors))
+	for name := range constructors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// New returns the ranker with the given name. An empty name selects the configured strategy,
+// falling back to defaultName when none is configured.
+func New(name string, defaultName string, cfg config.RankingConfig) (Ranker, error) {
+	if name == "" {
+		name = cfg.Sort
+	}
+	if name == "" {
+		name = defaultName
+	}
+
+	constructor, ok := constructors[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort strategy '%s', expected one of: %s", name, strings.Join(Names(), ", "))
+	}
+
+	return constructor(cfg), nil
+}
+
+// Rank scores each branch and returns them ordered from highest to lowest score.
+// Ties are broken by the most recent checkout, then by name.
+func Rank(branches []git.BranchInfo, r Ranker, now time.Time) []Ranked {
+	result := make([]Ranked, 0, len(branches))
+
+	for i := range branches {
+		score := r.Score(&branches[i], now)
+		branches[i].Score = score.Total
+		result = append(result, Ranked{Branch: branches[i], Score: score})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Score.Total != result[j].Score.Total {
+			return result[i].Score.Total > result[j].Score.Total
+		}
+		if !result[i].Branch.CheckedOutLast.Equal(result[j].Branch.CheckedOutLast) {
+			return result[i].Branch.CheckedOutLast.After(result[j].Branch.CheckedOutLast)
+		}
+		return result[i].Branch.Name < result[j].Branch.Name
+	})
+
+	return result
+}
+
+// Explain formats the components of a score for display.
+func (s Score) Explain() string {
+	parts := make([]string, 0, len(s.Components))
+	for _, c := range s.Components {
+		parts = append(parts, fmt.Sprintf("%s=%.4g", c.Name, c.Value))
+	}
+
+	return fmt.Sprintf("score %.2f: %s", s.Total, strings.Join(parts, ", "))
+}
@@ -0,0 +1,72 @@
+package reflog
+
+import "time"
+
+// BranchStats holds the checkout and commit history of a branch
+type BranchStats struct {
+	Name          string
+	Checkouts     []time.Time
+	Commits       []time.Time
+	LastCheckout  time.Time
+	CheckoutCount int
+	CommitCount   int
+}
+
+// Analyze computes checkout counts from the HEAD reflog and commit counts from the branch reflogs
+// in a single pass over the entries.
+func Analyze(head []Entry, branches map[string][]Entry) map[string]*BranchStats {
+	result := make(map[string]*BranchStats)
+
+	get := func(name string) *BranchStats {
+		stats, ok := result[name]
+		if !ok {
+			stats = &BranchStats{Name: name}
+			result[name] = stats
+		}
+		return stats
+	}
+
+	for _, entry := range head {
+		name := entry.CheckedOutBranch()
+		if name == "" {
+			continue
+		}
+
+		stats := get(name)
+		stats.CheckoutCount++
+		stats.Checkouts = append(stats.Checkouts, entry.Timestamp)
+
+		if entry.Timestamp.After(stats.LastCheckout) {
+			stats.LastCheckout = entry.Timestamp
+		}
+	}
+
+	for name, entries := range branches {
+		for _, entry := range entries {
+			if !entry.IsCommit() {
+				continue
+			}
+
+			stats := get(name)
+			stats.CommitCount++
+			stats.Commits = append(stats.Commits, entry.Timestamp)
+		}
+	}
+
+	return result
+}
+
+// AnalyzeRepository reads the HEAD and branch reflogs of the repository and analyzes them.
+func AnalyzeRepository(r *Repository) (map[string]*BranchStats, error) {
+	head, err := r.HeadEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	branches, err := r.BranchEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	return Analyze(head, branches), nil
+}
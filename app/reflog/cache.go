@@ -0,0 +1,242 @@
+package reflog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// tailSize is the number of bytes before the end of a cached log that are compared to detect
+// logs that were rewritten rather than appended to, e.g. by `git reflog expire`.
+const tailSize = 64
+
+const cacheFileName = "reflog-cache.gob"
+
+// CachedLog holds the parsed entries of a single reflog file
+type CachedLog struct {
+	Size    int64
+	ModTime time.Time
+	Tail    []byte
+	Entries []Entry
+}
+
+// Cache stores parsed reflog entries keyed by the log file's path relative to the repository's common dir.
+// Logs that only had lines appended since they were cached are parsed from the previous end of the file.
+type Cache struct {
+	Logs map[string]*CachedLog
+
+	dir   string
+	dirty bool
+}
+
+// CacheStatus describes a cached log and whether it is up to date
+type CacheStatus struct {
+	Name    string
+	Entries int
+	Size    int64
+	Fresh   bool
+}
+
+// CacheDir returns the directory of the reflog cache of a repository.
+func CacheDir(commonDir string) string {
+	return filepath.Join(commonDir, "ninja")
+}
+
+// OpenCache loads the reflog cache stored in dir. A missing or unreadable cache results in an empty cache.
+func OpenCache(dir string) *Cache {
+	cache := &Cache{Logs: make(map[string]*CachedLog), dir: dir}
+
+	file, err := os.Open(cache.FileName())
+	if err != nil {
+		return cache
+	}
+	defer file.Close()
+
+	if err := gob.NewDecoder(bufio.NewReader(file)).Decode(&cache.Logs); err != nil {
+		// start over if the cache was written by an incompatible version
+		cache.Logs = make(map[string]*CachedLog)
+		cache.dirty = true
+	}
+
+	return cache
+}
+
+// FileName returns the path of the cache file.
+func (c *Cache) FileName() string {
+	return filepath.Join(c.dir, cacheFileName)
+}
+
+// Save writes the cache to disk if it has changed, replacing the existing file atomically.
+func (c *Cache) Save() error {
+	if !c.dirty {
+		return nil
+	}
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %v", err)
+	}
+
+	tempFile, err := os.CreateTemp(c.dir, ".reflog-cache-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	writer := bufio.NewWriter(tempFile)
+	if err := gob.NewEncoder(writer).Encode(c.Logs); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to encode cache: %v", err)
+	}
+
+	if err := writer.Flush(); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to write cache file: %v", err)
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("failed to write cache file: %v", err)
+	}
+
+	if err := os.Rename(tempFile.Name(), c.FileName()); err != nil {
+		return fmt.Errorf("failed to write cache file: %v", err)
+	}
+
+	c.dirty = false
+
+	return nil
+}
+
+// Clear removes the cache file and empties the cache.
+func (c *Cache) Clear() error {
+	c.Logs = make(map[string]*CachedLog)
+	c.dirty = false
+
+	err := os.Remove(c.FileName())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// PruneMissing removes cached logs whose files no longer exist in baseDir, e.g. the logs of deleted branches.
+func (c *Cache) PruneMissing(baseDir string) {
+	for key := range c.Logs {
+		if _, err := os.Stat(filepath.Join(baseDir, key)); errors.Is(err, os.ErrNotExist) {
+			delete(c.Logs, key)
+			c.dirty = true
+		}
+	}
+}
+
+// Status returns the state of each cached log, using baseDir to locate the log files.
+func (c *Cache) Status(baseDir string) []CacheStatus {
+	result := make([]CacheStatus, 0, len(c.Logs))
+
+	for key, log := range c.Logs {
+		fresh := false
+		if info, err := os.Stat(filepath.Join(baseDir, key)); err == nil {
+			fresh = info.Size() == log.Size && info.ModTime().Equal(log.ModTime)
+		}
+
+		result = append(result, CacheStatus{Name: key, Entries: len(log.Entries), Size: log.Size, Fresh: fresh})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// ReadFile returns the entries of the reflog file, using the cached entries identified by key when the
+// file is unchanged and only parsing the lines appended to it since it was cached.
+func (c *Cache) ReadFile(key string, fileName string) ([]Entry, error) {
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, ok := c.Logs[key]
+	if ok && cached.Size == info.Size() && cached.ModTime.Equal(info.ModTime()) {
+		return cached.Entries, nil
+	}
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries := make([]Entry, 0)
+	offset := int64(0)
+
+	if ok && cached.Size < info.Size() && tailMatches(file, cached) {
+		entries = append(entries, cached.Entries...)
+		offset = cached.Size
+	}
+
+	appended, size, err := parseFrom(file, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	entries = append(entries, appended...)
+
+	c.Logs[key] = &CachedLog{Size: size, ModTime: info.ModTime(), Tail: readTail(file, size), Entries: entries}
+	c.dirty = true
+
+	return entries, nil
+}
+
+// parseFrom parses the complete lines of the file starting at offset, and returns the offset just
+// past the last complete line so that partially written lines are parsed again next time.
+func parseFrom(file *os.File, offset int64) ([]Entry, int64, error) {
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]Entry, 0)
+	reader := bufio.NewReader(file)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		offset += int64(len(line))
+
+		if entry, ok := ParseLine(string(bytes.TrimRight(line, "\r\n"))); ok {
+			result = append(result, entry)
+		}
+	}
+
+	return result, offset, nil
+}
+
+func readTail(file *os.File, size int64) []byte {
+	start := max(size-tailSize, 0)
+	tail := make([]byte, size-start)
+
+	if _, err := file.ReadAt(tail, start); err != nil {
+		return nil
+	}
+
+	return tail
+}
+
+// tailMatches reports whether the bytes before the cached end of the log are unchanged.
+func tailMatches(file *os.File, cached *CachedLog) bool {
+	return cached.Tail != nil && bytes.Equal(readTail(file, cached.Size), cached.Tail)
+}
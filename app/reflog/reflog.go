@@ -0,0 +1,347 @@
+package reflog
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/utils"
+)
+
+// Entry is a single line of a reflog file
+type Entry struct {
+	OldHash   string
+	NewHash   string
+	Name      string
+	Email     string
+	Timestamp time.Time
+	Message   string
+}
+
+// Action returns the part of the message before the first colon, e.g. "checkout" or "commit (amend)".
+func (e Entry) Action() string {
+	return strings.Split(e.Message, ":")[0]
+}
+
+// IsCommit reports whether the entry was written by creating a commit.
+func (e Entry) IsCommit() bool {
+	return strings.HasPrefix(e.Message, "commit")
+}
+
+// CheckedOutBranch returns the branch that was checked out by a HEAD reflog entry, or an empty string, including
+// when the entry detached HEAD at a commit.
+func (e Entry) CheckedOutBranch() string {
+	if e.DetachedHEAD() {
+		return ""
+	}
+
+	return e.checkoutTarget()
+}
+
+// DetachedHEAD reports whether a HEAD reflog entry checked out a commit by its hash, detaching HEAD.
+func (e Entry) DetachedHEAD() bool {
+	target := strings.ToLower(e.checkoutTarget())
+
+	// git accepts hashes abbreviated to 4 characters
+	return len(target) >= 4 && strings.HasPrefix(e.NewHash, target)
+}
+
+// checkoutTarget returns what a checkout entry of the HEAD reflog moved to, e.g. a branch, or an empty string.
+func (e Entry) checkoutTarget() string {
+	if !strings.HasPrefix(e.Message, "checkout: moving from ") {
+		return ""
+	}
+
+	fields := strings.Fields(e.Message)
+	if len(fields) != 6 || fields[4] != "to" {
+		return ""
+	}
+
+	return fields[5]
+}
+
+// ParseLine parses a reflog line in git's on-disk format:
+//
+//	<old-hash> <new-hash> <name> <<email>> <unix-timestamp> <timezone>\t<message>
+func ParseLine(line string) (Entry, bool) {
+	header, message, _ := strings.Cut(line, "\t")
+
+	oldHash, rest, ok := strings.Cut(header, " ")
+	if !ok {
+		return Entry{}, false
+	}
+
+	newHash, identity, ok := strings.Cut(rest, " ")
+	if !ok {
+		return Entry{}, false
+	}
+
+	emailStart := strings.LastIndex(identity, "<")
+	emailEnd := strings.LastIndex(identity, ">")
+	if emailStart == -1 || emailEnd < emailStart {
+		return Entry{}, false
+	}
+
+	dateFields := strings.Fields(identity[emailEnd+1:])
+	if len(dateFields) == 0 {
+		return Entry{}, false
+	}
+
+	seconds, err := strconv.ParseInt(dateFields[0], 10, 64)
+	if err != nil {
+		return Entry{}, false
+	}
+
+	return Entry{
+		OldHash:   oldHash,
+		NewHash:   newHash,
+		Name:      strings.TrimSpace(identity[:emailStart]),
+		Email:     identity[emailStart+1 : emailEnd],
+		Timestamp: time.Unix(seconds, 0),
+		Message:   message,
+	}, true
+}
+
+// ReadFile parses a reflog file, returning its entries oldest first.
+func ReadFile(fileName string) ([]Entry, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	result := make([]Entry, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		if entry, ok := ParseLine(scanner.Text()); ok {
+			result = append(result, entry)
+		}
+	}
+
+	return result, scanner.Err()
+}
+
+// Repository locates the reflog files of a repository. HEAD's reflog belongs to the worktree,
+// while branch reflogs are shared by all worktrees.
+type Repository struct {
+	GitDir    string
+	CommonDir string
+	// RefStorage is the ref backend of the repository, "files" or "reftable". Repositories using the reftable
+	// backend have no reflog files, so their reflogs are read with git.
+	RefStorage string
+
+	path  string
+	cache *Cache
+}
+
+// Open returns the reflog locations of the repository at repoPath, or of the current repository if repoPath is empty.
+func Open(repoPath string) (*Repository, error) {
+	output, err := utils.RunCommand("git", helpers.GitArgs(repoPath, "rev-parse", "--path-format=absolute", "--git-dir", "--git-common-dir")...)
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository: %v", err)
+	}
+
+	dirs := strings.Split(output, "\n")
+	if len(dirs) != 2 {
+		return nil, fmt.Errorf("unexpected output from git rev-parse: %s", output)
+	}
+
+	repo := &Repository{GitDir: strings.TrimSpace(dirs[0]), CommonDir: strings.TrimSpace(dirs[1]), RefStorage: "files", path: repoPath}
+	if refStorage, err := utils.RunCommand("git", helpers.GitArgs(repoPath, "config", "--get", "extensions.refStorage")...); err == nil && refStorage != "" {
+		repo.RefStorage = refStorage
+	}
+
+	repo.cache = OpenCache(CacheDir(repo.CommonDir))
+
+	return repo, nil
+}
+
+// Cache returns the repository's reflog cache, or nil if it does not use one.
+func (r *Repository) Cache() *Cache {
+	return r.cache
+}
+
+// Close saves the reflog cache, if the repository uses one.
+func (r *Repository) Close() error {
+	if r.cache == nil {
+		return nil
+	}
+
+	r.cache.PruneMissing(r.CommonDir)
+
+	return r.cache.Save()
+}
+
+// readLog parses a reflog file, through the cache if the repository uses one.
+func (r *Repository) readLog(fileName string) ([]Entry, error) {
+	if r.cache == nil {
+		return ReadFile(fileName)
+	}
+
+	key, err := filepath.Rel(r.CommonDir, fileName)
+	if err != nil {
+		return ReadFile(fileName)
+	}
+
+	return r.cache.ReadFile(filepath.ToSlash(key), fileName)
+}
+
+// HeadLogFileName returns the path of the HEAD reflog file.
+func (r *Repository) HeadLogFileName() string {
+	return filepath.Join(r.GitDir, "logs", "HEAD")
+}
+
+// BranchLogsDir returns the directory containing the reflogs of local branches.
+func (r *Repository) BranchLogsDir() string {
+	return filepath.Join(r.CommonDir, "logs", "refs", "heads")
+}
+
+// readRef parses the reflog file of a ref, or reads the ref's reflog with git when the repository has no reflog
+// files, e.g. because it uses the reftable backend.
+func (r *Repository) readRef(ref string, fileName string) ([]Entry, error) {
+	if r.RefStorage == "reftable" {
+		return r.gitReflog(ref)
+	}
+
+	entries, err := r.readLog(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return r.gitReflog(ref)
+	}
+
+	return entries, err
+}
+
+// gitReflog reads the reflog of a ref with git, returning its entries oldest first. git doesn't show the old hash
+// of an entry, so it is taken from the entry before it, and the oldest entry's is all zeros.
+func (r *Repository) gitReflog(ref string) ([]Entry, error) {
+	result := make([]Entry, 0)
+
+	if _, err := utils.RunCommand("git", helpers.GitArgs(r.path, "reflog", "exists", ref)...); err != nil {
+		return result, nil
+	}
+
+	output, err := utils.RunCommand("git", helpers.GitArgs(r.path, "reflog", "show", "--date=unix", "--format=%H%x00%gn%x00%ge%x00%gd%x00%gs", ref, "--")...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reflog for %s: %v", ref, err)
+	}
+
+	lines := strings.Split(output, "\n")
+
+	for i := len(lines) - 1; i >= 0; i-- {
+		fields := strings.SplitN(lines[i], "\x00", 5)
+		if len(fields) != 5 {
+			continue
+		}
+
+		selector := fields[3]
+		seconds, err := strconv.ParseInt(strings.TrimSuffix(selector[strings.LastIndex(selector, "@{")+2:], "}"), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		oldHash := strings.Repeat("0", len(fields[0]))
+		if len(result) > 0 {
+			oldHash = result[len(result)-1].NewHash
+		}
+
+		result = append(result, Entry{
+			OldHash:   oldHash,
+			NewHash:   fields[0],
+			Name:      fields[1],
+			Email:     fields[2],
+			Timestamp: time.Unix(seconds, 0),
+			Message:   fields[4],
+		})
+	}
+
+	return result, nil
+}
+
+// HeadEntries returns the entries of the HEAD reflog, oldest first.
+func (r *Repository) HeadEntries() ([]Entry, error) {
+	return r.readRef("HEAD", r.HeadLogFileName())
+}
+
+// BranchLogFiles returns the reflog file of each local branch, keyed by branch name.
+func (r *Repository) BranchLogFiles() (map[string]string, error) {
+	result := make(map[string]string)
+	root := r.BranchLogsDir()
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		name, _ := filepath.Rel(root, path)
+		result[filepath.ToSlash(name)] = path
+
+		return nil
+	})
+
+	return result, err
+}
+
+// RefEntries returns the entries of the reflog of a ref such as "refs/remotes/origin/main", oldest first.
+func (r *Repository) RefEntries(ref string) ([]Entry, error) {
+	return r.readRef(ref, filepath.Join(r.CommonDir, "logs", filepath.FromSlash(ref)))
+}
+
+// BranchEntries returns the reflog entries of every local branch, oldest first, keyed by branch name.
+func (r *Repository) BranchEntries() (map[string][]Entry, error) {
+	if _, err := os.Stat(r.BranchLogsDir()); r.RefStorage == "reftable" || errors.Is(err, os.ErrNotExist) {
+		return r.gitBranchEntries()
+	}
+
+	files, err := r.BranchLogFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]Entry, len(files))
+
+	for name, fileName := range files {
+		entries, err := r.readLog(fileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read reflog for %s: %v", name, err)
+		}
+		result[name] = entries
+	}
+
+	return result, nil
+}
+
+// gitBranchEntries reads the reflog of every local branch with git, for repositories without reflog files.
+func (r *Repository) gitBranchEntries() (map[string][]Entry, error) {
+	output, err := utils.RunCommand("git", helpers.GitArgs(r.path, "for-each-ref", "--format=%(refname:lstrip=2)", "refs/heads/")...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %v", err)
+	}
+
+	result := make(map[string][]Entry)
+
+	for _, name := range strings.Fields(output) {
+		entries, err := r.gitReflog("refs/heads/" + name)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = entries
+	}
+
+	return result, nil
+}
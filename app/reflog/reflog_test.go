@@ -0,0 +1,248 @@
+package reflog_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/app/reflog"
+)
+
+// benchmarkBranches is the number of branches in the repository used by benchmarks
+const benchmarkBranches = 1000
+
+func runGit(tb testing.TB, dir string, stdin string, args ...string) string {
+	tb.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=bench", "GIT_AUTHOR_EMAIL=bench@example.com",
+		"GIT_COMMITTER_NAME=bench", "GIT_COMMITTER_EMAIL=bench@example.com", "GIT_CONFIG_GLOBAL=/dev/null")
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		tb.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+
+	return strings.TrimSpace(string(output))
+}
+
+// generateRepository creates a repository in a temporary directory with the given number of branches, each with
+// a creation entry and several commit entries in its reflog, and three checkouts in the HEAD reflog.
+func generateRepository(tb testing.TB, branchCount int, commitsPerBranch int) string {
+	tb.Helper()
+
+	dir := tb.TempDir()
+	runGit(tb, dir, "", "init", "-q", "-b", "main")
+	runGit(tb, dir, "", "commit", "-q", "--allow-empty", "-m", "initial")
+	hash := runGit(tb, dir, "", "rev-parse", "HEAD")
+
+	var create strings.Builder
+	for i := 0; i < branchCount; i++ {
+		fmt.Fprintf(&create, "create refs/heads/feature/branch-%d %s\n", i, hash)
+	}
+	runGit(tb, dir, create.String(), "update-ref", "--create-reflog", "-m", "branch: Created from HEAD", "--stdin")
+
+	for c := 0; c < commitsPerBranch; c++ {
+		// git doesn't log updates that leave a ref unchanged, so each round moves the branches to a new commit
+		hash = runGit(tb, dir, "", "commit-tree", "-p", hash, "-m", fmt.Sprintf("change %d", c), "HEAD^{tree}")
+
+		var update strings.Builder
+		for i := 0; i < branchCount; i++ {
+			fmt.Fprintf(&update, "update refs/heads/feature/branch-%d %s\n", i, hash)
+		}
+		runGit(tb, dir, update.String(), "update-ref", "--create-reflog", "-m", fmt.Sprintf("commit: change %d", c), "--stdin")
+	}
+
+	headLog, err := os.OpenFile(filepath.Join(dir, ".git", "logs", "HEAD"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer headLog.Close()
+
+	timestamp := time.Now().Add(-30 * 24 * time.Hour).Unix()
+	previous := "main"
+
+	for c := 0; c < 3; c++ {
+		for i := 0; i < branchCount; i++ {
+			branch := fmt.Sprintf("feature/branch-%d", i)
+			timestamp += 60
+			fmt.Fprintf(headLog, "%s %s bench <bench@example.com> %d +0000\tcheckout: moving from %s to %s\n", hash, hash, timestamp, previous, branch)
+			previous = branch
+		}
+	}
+
+	return dir
+}
+
+func TestAnalyzeRepository(t *testing.T) {
+	dir := generateRepository(t, 10, 5)
+
+	repo, err := reflog.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+
+	// the second analysis reads the reflogs from the cache
+	for i := 0; i < 2; i++ {
+		stats, err := reflog.AnalyzeRepository(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for b := 0; b < 10; b++ {
+			name := fmt.Sprintf("feature/branch-%d", b)
+			if s := stats[name]; s == nil || s.CheckoutCount != 3 || s.CommitCount != 5 {
+				t.Fatalf("expected 3 checkouts and 5 commits of %s, got %+v", name, s)
+			}
+		}
+	}
+}
+
+// BenchmarkReflogPerBranch reads the reflog of each branch with its own git process.
+func BenchmarkReflogPerBranch(b *testing.B) {
+	dir := generateRepository(b, benchmarkBranches, 5)
+	branches := strings.Split(runGit(b, dir, "", "for-each-ref", "--format=%(refname:short)", "refs/heads/"), "\n")
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		for _, branch := range branches {
+			runGit(b, dir, "", "reflog", "--pretty=format:%at|%H|%an|%ae|%gs|%gd", branch)
+		}
+	}
+}
+
+// BenchmarkAnalyzeRepository reads every reflog in a single pass, without the reflog cache.
+func BenchmarkAnalyzeRepository(b *testing.B) {
+	dir := generateRepository(b, benchmarkBranches, 5)
+	repo := &reflog.Repository{GitDir: filepath.Join(dir, ".git"), CommonDir: filepath.Join(dir, ".git")}
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		if _, err := reflog.AnalyzeRepository(repo); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkAnalyzeRepositoryCached reads every reflog with a warm reflog cache, so that each iteration only checks
+// the log files for changes.
+func BenchmarkAnalyzeRepositoryCached(b *testing.B) {
+	dir := generateRepository(b, benchmarkBranches, 5)
+
+	repo, err := reflog.Open(dir)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer repo.Close()
+
+	if _, err := reflog.AnalyzeRepository(repo); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		if _, err := reflog.AnalyzeRepository(repo); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestRepositoryReadsTheReflogsOfReftableRepositories(t *testing.T) {
+	dir := t.TempDir()
+	if output, err := exec.Command("git", "init", "-q", "-b", "main", "--ref-format=reftable", dir).CombinedOutput(); err != nil {
+		t.Skipf("git doesn't support the reftable backend: %s", output)
+	}
+
+	runGit(t, dir, "", "commit", "-q", "--allow-empty", "-m", "initial")
+	runGit(t, dir, "", "checkout", "-q", "-b", "feature")
+
+	repo, err := reflog.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+
+	if repo.RefStorage != "reftable" {
+		t.Errorf("expected the reftable ref storage, got %q", repo.RefStorage)
+	}
+
+	head, err := repo.HeadEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(head) != 2 || head[1].CheckedOutBranch() != "feature" || head[1].OldHash != head[0].NewHash {
+		t.Errorf("expected the commit and the checkout of feature in the HEAD reflog, got %+v", head)
+	}
+
+	branches, err := repo.BranchEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(branches["main"]) != 1 || len(branches["feature"]) != 1 || !branches["main"][0].IsCommit() {
+		t.Errorf("expected a reflog entry for each branch, got %+v", branches)
+	}
+}
+
+func TestRepositoryReadsReflogsWithGitLikeTheReflogFiles(t *testing.T) {
+	dir := generateRepository(t, 3, 2)
+
+	repo, err := reflog.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+
+	fromFiles, err := repo.BranchEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo.RefStorage = "reftable"
+
+	fromGit, err := repo.BranchEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fromGit) != len(fromFiles) {
+		t.Fatalf("expected %d branches, got %d", len(fromFiles), len(fromGit))
+	}
+
+	for name, entries := range fromFiles {
+		if fmt.Sprint(fromGit[name]) != fmt.Sprint(entries) {
+			t.Errorf("expected the reflog of %s to be %+v, got %+v", name, entries, fromGit[name])
+		}
+	}
+}
+
+func TestCheckedOutBranchSkipsDetachedHEAD(t *testing.T) {
+	hash := "c42cb174d3750a1daa679ad99bddd1dba11445ff"
+
+	entries := map[string]string{
+		"checkout: moving from main to feature/login": "feature/login",
+		"checkout: moving from main to c42cb17":       "",
+		"checkout: moving from main to " + hash:       "",
+		"commit: add the login page":                  "",
+	}
+
+	for message, expected := range entries {
+		entry := reflog.Entry{NewHash: hash, Message: message}
+
+		if branch := entry.CheckedOutBranch(); branch != expected {
+			t.Errorf("expected %q to check out %q, got %q", message, expected, branch)
+		}
+	}
+
+	if !(reflog.Entry{NewHash: hash, Message: "checkout: moving from main to c42cb17"}).DetachedHEAD() {
+		t.Error("expected a checkout of a commit hash to detach HEAD")
+	}
+}
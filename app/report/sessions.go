@@ -0,0 +1,89 @@
+package report
+
+import (
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/app/git"
+)
+
+// Session is a contiguous period of time spent on a branch
+type Session struct {
+	Branch string    `json:"branch"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// Duration returns the length of the session.
+func (s Session) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Clip returns the part of the session that falls within [from, to), and whether any part does.
+func (s Session) Clip(from time.Time, to time.Time) (Session, bool) {
+	if s.Start.Before(from) {
+		s.Start = from
+	}
+	if s.End.After(to) {
+		s.End = to
+	}
+
+	return s, s.End.After(s.Start)
+}
+
+// BuildSessions reconstructs the sessions spent on each branch from the HEAD reflog items, which must be
+// ordered newest first. Each reflog entry, such as a checkout or a commit, marks activity on the branch
+// that is checked out at the time; the time between two entries is attributed to that branch, but gaps
+// longer than idleLimit are cut off at idleLimit. Activity after the last entry is capped at now, and activity
+// on a detached HEAD is not attributed to any branch.
+func BuildSessions(items []*git.RefLogItem, idleLimit time.Duration, now time.Time) []Session {
+	result := make([]Session, 0)
+	currentBranch := ""
+
+	var current *Session
+
+	for i := len(items) - 1; i >= 0; i-- {
+		item := items[i]
+
+		if branch := item.SwitchedToBranch(); branch != "" {
+			currentBranch = branch
+		} else if item.Detached {
+			// time spent on a detached HEAD isn't booked to a branch
+			currentBranch = ""
+		}
+
+		if currentBranch == "" {
+			continue
+		}
+
+		end := now
+		if i > 0 {
+			end = items[i-1].Timestamp
+		}
+		if end.Sub(item.Timestamp) > idleLimit {
+			end = item.Timestamp.Add(idleLimit)
+		}
+		if end.After(now) {
+			end = now
+		}
+
+		// extend the current session when the activity continues on the same branch without a gap
+		if current != nil && current.Branch == currentBranch && !item.Timestamp.After(current.End) {
+			if end.After(current.End) {
+				current.End = end
+			}
+			continue
+		}
+
+		if current != nil {
+			result = append(result, *current)
+		}
+
+		current = &Session{Branch: currentBranch, Start: item.Timestamp, End: end}
+	}
+
+	if current != nil {
+		result = append(result, *current)
+	}
+
+	return result
+}
@@ -0,0 +1,160 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StandupCommit is a commit authored on a branch
+type StandupCommit struct {
+	Hash      string
+	Subject   string
+	Timestamp time.Time
+}
+
+// StandupIssue is the issue linked to a branch
+type StandupIssue struct {
+	Key     string
+	Summary string
+	URL     string
+}
+
+// StandupBranch summarizes the work done on a single branch
+type StandupBranch struct {
+	Name           string
+	CheckedOutLast time.Time
+	Commits        []StandupCommit
+	Pushes         []time.Time
+	Issue          *StandupIssue
+}
+
+// Standup is a summary of the work done since a point in time
+type Standup struct {
+	Since    time.Time
+	Branches []*StandupBranch
+}
+
+// LastWorkingDay returns the start of the working day before now, skipping weekends.
+func LastWorkingDay(now time.Time) time.Time {
+	day := StartOfDay(now).AddDate(0, 0, -1)
+
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	return day
+}
+
+// ParseSince parses either a date (YYYY-MM-DD) or a duration relative to now, such as "36h".
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, value, now.Location()); err == nil {
+		return t, nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid value '%s', expected a date (YYYY-MM-DD) or a duration (e.g. 36h)", value)
+}
+
+var reflogCommitPrefixRegex = regexp.MustCompile(`^commit( \([a-z]+\))?: `)
+
+// CommitSubjectFromRefLogMessage strips the "commit: " prefix from a reflog message.
+func CommitSubjectFromRefLogMessage(message string) string {
+	return reflogCommitPrefixRegex.ReplaceAllString(message, "")
+}
+
+// Branch returns the summary for the named branch, adding it if needed.
+func (s *Standup) Branch(name string) *StandupBranch {
+	for _, b := range s.Branches {
+		if b.Name == name {
+			return b
+		}
+	}
+
+	branch := &StandupBranch{Name: name, Commits: make([]StandupCommit, 0), Pushes: make([]time.Time, 0)}
+	s.Branches = append(s.Branches, branch)
+
+	return branch
+}
+
+// Markdown renders the standup summary as Markdown, with branches ordered by their latest activity.
+func (s *Standup) Markdown() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("**Since %s**\n\n", s.Since.Format("Mon, Jan 2 15:04")))
+
+	if len(s.Branches) == 0 {
+		sb.WriteString("No activity.\n")
+		return sb.String()
+	}
+
+	branches := append([]*StandupBranch{}, s.Branches...)
+	sort.SliceStable(branches, func(i, j int) bool {
+		return branches[i].lastActivity().After(branches[j].lastActivity())
+	})
+
+	for _, b := range branches {
+		sb.WriteString(fmt.Sprintf("- `%s`", b.Name))
+
+		if b.Issue != nil {
+			issue := b.Issue.Key
+			if b.Issue.URL != "" {
+				issue = fmt.Sprintf("[%s](%s)", b.Issue.Key, b.Issue.URL)
+			}
+			if b.Issue.Summary != "" {
+				issue += ": " + b.Issue.Summary
+			}
+			sb.WriteString(" — " + issue)
+		}
+
+		details := make([]string, 0)
+		if len(b.Commits) > 0 {
+			details = append(details, pluralize(len(b.Commits), "commit", "commits"))
+		}
+		if len(b.Pushes) > 0 {
+			details = append(details, pluralize(len(b.Pushes), "push", "pushes"))
+		}
+		if len(details) > 0 {
+			sb.WriteString(" (" + strings.Join(details, ", ") + ")")
+		}
+
+		sb.WriteString("\n")
+
+		for _, c := range b.Commits {
+			sb.WriteString(fmt.Sprintf("  - %s\n", c.Subject))
+		}
+	}
+
+	return sb.String()
+}
+
+func (b *StandupBranch) lastActivity() time.Time {
+	last := b.CheckedOutLast
+
+	for _, c := range b.Commits {
+		if c.Timestamp.After(last) {
+			last = c.Timestamp
+		}
+	}
+
+	for _, p := range b.Pushes {
+		if p.After(last) {
+			last = p
+		}
+	}
+
+	return last
+}
+
+func pluralize(count int, singular string, plural string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, singular)
+	}
+
+	return fmt.Sprintf("%d %s", count, plural)
+}
@@ -0,0 +1,135 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// BranchTime is the total time spent on a branch within a report's range
+type BranchTime struct {
+	Branch   string    `json:"branch"`
+	Issue    string    `json:"issue,omitempty"`
+	Hours    float64   `json:"hours"`
+	Sessions []Session `json:"sessions"`
+}
+
+// IssueTime is the total time spent on all branches linked to an issue
+type IssueTime struct {
+	Issue    string   `json:"issue"`
+	Hours    float64  `json:"hours"`
+	Branches []string `json:"branches"`
+}
+
+// TimeReport summarizes the time spent per branch and per issue between two points in time
+type TimeReport struct {
+	From     time.Time    `json:"from"`
+	To       time.Time    `json:"to"`
+	Hours    float64      `json:"hours"`
+	Branches []BranchTime `json:"branches"`
+	Issues   []IssueTime  `json:"issues"`
+}
+
+// StartOfDay returns midnight at the start of t's day, in t's location.
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns midnight at the start of the Monday of t's week.
+func StartOfWeek(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+
+	return StartOfDay(t).AddDate(0, 0, -daysSinceMonday)
+}
+
+// ParseRange returns the start and end of a report period. The period is either "day" or "week"; when from
+// is given (as YYYY-MM-DD) it is used instead, and the range ends at the end of the to date, or now. The to date
+// requires a from date.
+func ParseRange(period string, from string, to string, now time.Time) (time.Time, time.Time, error) {
+	if to != "" && from == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("--to requires --from")
+	}
+
+	if from != "" {
+		start, err := time.ParseInLocation(time.DateOnly, from, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start date '%s', expected YYYY-MM-DD", from)
+		}
+
+		end := now
+		if to != "" {
+			if end, err = time.ParseInLocation(time.DateOnly, to, now.Location()); err != nil {
+				return time.Time{}, time.Time{}, fmt.Errorf("invalid end date '%s', expected YYYY-MM-DD", to)
+			}
+			end = end.AddDate(0, 0, 1)
+		}
+
+		return start, end, nil
+	}
+
+	switch period {
+	case "", "day":
+		return StartOfDay(now), now, nil
+	case "week":
+		return StartOfWeek(now), now, nil
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf("unknown period '%s', expected day or week", period)
+}
+
+// BuildTimeReport aggregates the sessions that fall within [from, to) per branch and per issue key.
+// extractIssueKey returns the issue key for a branch name, or an empty string if it has none.
+func BuildTimeReport(sessions []Session, from time.Time, to time.Time, extractIssueKey func(string) string) *TimeReport {
+	report := &TimeReport{From: from, To: to, Branches: make([]BranchTime, 0), Issues: make([]IssueTime, 0)}
+	branches := make(map[string]*BranchTime)
+	issues := make(map[string]*IssueTime)
+
+	for _, session := range sessions {
+		clipped, ok := session.Clip(from, to)
+		if !ok {
+			continue
+		}
+
+		bt, exists := branches[clipped.Branch]
+		if !exists {
+			bt = &BranchTime{Branch: clipped.Branch, Issue: extractIssueKey(clipped.Branch)}
+			branches[clipped.Branch] = bt
+		}
+
+		bt.Hours += clipped.Duration().Hours()
+		bt.Sessions = append(bt.Sessions, clipped)
+		report.Hours += clipped.Duration().Hours()
+	}
+
+	for _, bt := range branches {
+		report.Branches = append(report.Branches, *bt)
+
+		if bt.Issue == "" {
+			continue
+		}
+
+		it, exists := issues[bt.Issue]
+		if !exists {
+			it = &IssueTime{Issue: bt.Issue}
+			issues[bt.Issue] = it
+		}
+
+		it.Hours += bt.Hours
+		it.Branches = append(it.Branches, bt.Branch)
+	}
+
+	for _, it := range issues {
+		sort.Strings(it.Branches)
+		report.Issues = append(report.Issues, *it)
+	}
+
+	sort.Slice(report.Branches, func(i, j int) bool {
+		return report.Branches[i].Hours > report.Branches[j].Hours
+	})
+
+	sort.Slice(report.Issues, func(i, j int) bool {
+		return report.Issues[i].Hours > report.Issues[j].Hours
+	})
+
+	return report
+}
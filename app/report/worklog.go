@@ -0,0 +1,236 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+)
+
+// LoggedInterval is a part of a session that has been posted to the issue tracker as part of a worklog
+type LoggedInterval struct {
+	Branch    string    `json:"branch"`
+	Issue     string    `json:"issue"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	WorklogID string    `json:"worklog_id"`
+	LoggedAt  time.Time `json:"logged_at"`
+}
+
+// WorklogLedger records the parts of a repository's sessions that have already been logged, so that
+// they are never logged twice
+type WorklogLedger struct {
+	Repository string           `json:"repository"`
+	Intervals  []LoggedInterval `json:"intervals"`
+
+	fileName string
+}
+
+// WorklogDraft is a worklog to be posted: the unlogged time spent on an issue's branches during one day
+type WorklogDraft struct {
+	Issue    string    `json:"issue"`
+	Started  time.Time `json:"started"`
+	Hours    float64   `json:"hours"`
+	Branches []string  `json:"branches"`
+	Sessions []Session `json:"sessions"`
+}
+
+// Duration returns the total length of the draft's sessions.
+func (d WorklogDraft) Duration() time.Duration {
+	var total time.Duration
+	for _, session := range d.Sessions {
+		total += session.Duration()
+	}
+
+	return total
+}
+
+// WorklogLedgerFileName returns the path of the worklog ledger for the repository located at repoRoot.
+func WorklogLedgerFileName(repoRoot string) string {
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(repoRoot)))
+
+	return filepath.Join(config.DataDir(), "worklogs", hash[:16]+".json")
+}
+
+// OpenWorklogLedger loads the worklog ledger for the repository located at repoRoot, returning an empty
+// ledger if none exists.
+func OpenWorklogLedger(repoRoot string) (*WorklogLedger, error) {
+	ledger := &WorklogLedger{Repository: repoRoot, Intervals: make([]LoggedInterval, 0), fileName: WorklogLedgerFileName(repoRoot)}
+
+	data, err := os.ReadFile(ledger.fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return ledger, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read worklog ledger: %v", err)
+	}
+
+	if err := json.Unmarshal(data, ledger); err != nil {
+		return nil, fmt.Errorf("failed to parse worklog ledger %s: %v", ledger.fileName, err)
+	}
+
+	return ledger, nil
+}
+
+// Save writes the ledger to disk, replacing the existing file atomically.
+func (l *WorklogLedger) Save() error {
+	if err := os.MkdirAll(filepath.Dir(l.fileName), 0755); err != nil {
+		return fmt.Errorf("failed to create worklog directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode worklog ledger: %v", err)
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(l.fileName), ".worklogs-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create worklog ledger: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write(data); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to write worklog ledger: %v", err)
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("failed to write worklog ledger: %v", err)
+	}
+
+	return os.Rename(tempFile.Name(), l.fileName)
+}
+
+// Record marks the sessions of a draft as logged by the worklog with the given ID.
+func (l *WorklogLedger) Record(draft WorklogDraft, worklogID string, now time.Time) {
+	for _, session := range draft.Sessions {
+		l.Intervals = append(l.Intervals, LoggedInterval{
+			Branch:    session.Branch,
+			Issue:     draft.Issue,
+			Start:     session.Start,
+			End:       session.End,
+			WorklogID: worklogID,
+			LoggedAt:  now,
+		})
+	}
+}
+
+// Unlogged returns the parts of a session that are not covered by the intervals logged for its branch.
+func (l *WorklogLedger) Unlogged(session Session) []Session {
+	logged := make([]LoggedInterval, 0)
+	for _, interval := range l.Intervals {
+		if interval.Branch == session.Branch && interval.End.After(session.Start) && interval.Start.Before(session.End) {
+			logged = append(logged, interval)
+		}
+	}
+
+	sort.Slice(logged, func(i, j int) bool {
+		return logged[i].Start.Before(logged[j].Start)
+	})
+
+	result := make([]Session, 0)
+	remaining := session
+
+	for _, interval := range logged {
+		if interval.Start.After(remaining.Start) {
+			result = append(result, Session{Branch: session.Branch, Start: remaining.Start, End: interval.Start})
+		}
+		if interval.End.After(remaining.Start) {
+			remaining.Start = interval.End
+		}
+		if !remaining.End.After(remaining.Start) {
+			return result
+		}
+	}
+
+	return append(result, remaining)
+}
+
+// BuildWorklogDrafts returns one draft per issue and day for the time spent on issue-linked branches within
+// [from, to) that has not been logged yet. Sessions are split at midnight, and drafts shorter than a minute,
+// the smallest amount of time an issue tracker records, are left out. extractIssueKey returns the issue key
+// for a branch name, or an empty string if it has none.
+func BuildWorklogDrafts(sessions []Session, from time.Time, to time.Time, ledger *WorklogLedger, extractIssueKey func(string) string) []WorklogDraft {
+	drafts := make(map[string]*WorklogDraft)
+
+	for _, session := range sessions {
+		issue := extractIssueKey(session.Branch)
+		if issue == "" {
+			continue
+		}
+
+		clipped, ok := session.Clip(from, to)
+		if !ok {
+			continue
+		}
+
+		for _, part := range splitAtMidnight(clipped) {
+			for _, unlogged := range ledger.Unlogged(part) {
+				key := issue + "|" + StartOfDay(unlogged.Start).Format(time.DateOnly)
+
+				draft, exists := drafts[key]
+				if !exists {
+					draft = &WorklogDraft{Issue: issue, Started: unlogged.Start}
+					drafts[key] = draft
+				}
+
+				if unlogged.Start.Before(draft.Started) {
+					draft.Started = unlogged.Start
+				}
+				if !containsBranch(draft.Branches, unlogged.Branch) {
+					draft.Branches = append(draft.Branches, unlogged.Branch)
+				}
+
+				draft.Sessions = append(draft.Sessions, unlogged)
+				draft.Hours += unlogged.Duration().Hours()
+			}
+		}
+	}
+
+	result := make([]WorklogDraft, 0, len(drafts))
+	for _, draft := range drafts {
+		if draft.Duration() < time.Minute {
+			continue
+		}
+
+		sort.Strings(draft.Branches)
+		result = append(result, *draft)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Started.Before(result[j].Started)
+	})
+
+	return result
+}
+
+// splitAtMidnight splits a session into the parts that fall on each day.
+func splitAtMidnight(session Session) []Session {
+	result := make([]Session, 0, 1)
+
+	for {
+		midnight := StartOfDay(session.Start).AddDate(0, 0, 1)
+		if !session.End.After(midnight) {
+			return append(result, session)
+		}
+
+		result = append(result, Session{Branch: session.Branch, Start: session.Start, End: midnight})
+		session.Start = midnight
+	}
+}
+
+func containsBranch(branches []string, branch string) bool {
+	for _, b := range branches {
+		if b == branch {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,88 @@
+package repos
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/app/git"
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/utils"
+)
+
+// RecentBranch is a branch checked out in one of the registered repositories
+type RecentBranch struct {
+	Repository     Repository
+	Branch         string
+	CheckedOutLast time.Time
+}
+
+// getRecentBranchesForRepository returns the existing branches of a repository, most recently checked out first.
+func getRecentBranchesForRepository(repo Repository) ([]RecentBranch, error) {
+	items, err := git.GetHeadRefLogItemsInDir(repo.Path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read reflog: %v", repo.Name, err)
+	}
+
+	existingBranches, err := helpers.GetAvailableBranchesMapInDir(repo.Path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list branches: %v", repo.Name, err)
+	}
+
+	result := make([]RecentBranch, 0)
+	seen := make(map[string]bool)
+
+	for _, item := range items {
+		if item.BranchName == "" || seen[item.BranchName] || !utils.MapEntryExists(item.BranchName, existingBranches) {
+			continue
+		}
+
+		seen[item.BranchName] = true
+		result = append(result, RecentBranch{Repository: repo, Branch: item.BranchName, CheckedOutLast: item.Timestamp})
+	}
+
+	return result, nil
+}
+
+// GetRecentBranches reads the reflog of each repository concurrently and returns all of their
+// branches, most recently checked out first. Repositories that cannot be read are reported as errors.
+func GetRecentBranches(repositories []Repository) ([]RecentBranch, []error) {
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	result := make([]RecentBranch, 0)
+	errs := make([]error, 0)
+	semaphore := make(chan struct{}, runtime.NumCPU())
+
+	wg.Add(len(repositories))
+
+	for _, repo := range repositories {
+		go func(repo Repository) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			branches, err := getRecentBranchesForRepository(repo)
+			<-semaphore
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+
+			result = append(result, branches...)
+		}(repo)
+	}
+
+	wg.Wait()
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CheckedOutLast.After(result[j].CheckedOutLast)
+	})
+
+	return result, errs
+}
@@ -0,0 +1,156 @@
+package repos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/utils"
+)
+
+// Repository is a repository registered with git-ninja
+type Repository struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// Registry is the list of repositories used by the cross-repository commands
+type Registry struct {
+	Repositories []Repository `json:"repositories"`
+
+	fileName string
+}
+
+// RegistryFileName returns the path of the repository registry file.
+func RegistryFileName() string {
+	return filepath.Join(config.DataDir(), "repos.json")
+}
+
+// LoadRegistry reads the repository registry, returning an empty registry if it does not exist.
+func LoadRegistry() (*Registry, error) {
+	registry := &Registry{Repositories: make([]Repository, 0), fileName: RegistryFileName()}
+
+	data, err := os.ReadFile(registry.fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return registry, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repository registry: %v", err)
+	}
+
+	if err := json.Unmarshal(data, registry); err != nil {
+		return nil, fmt.Errorf("failed to parse repository registry %s: %v", registry.fileName, err)
+	}
+
+	return registry, nil
+}
+
+// Save writes the registry to disk.
+func (r *Registry) Save() error {
+	sort.Slice(r.Repositories, func(i, j int) bool {
+		return r.Repositories[i].Path < r.Repositories[j].Path
+	})
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode repository registry: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.fileName), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %v", err)
+	}
+
+	return os.WriteFile(r.fileName, data, 0644)
+}
+
+// Find returns the registered repository with the given path, or nil.
+func (r *Registry) Find(path string) *Repository {
+	for i := range r.Repositories {
+		if r.Repositories[i].Path == path {
+			return &r.Repositories[i]
+		}
+	}
+
+	return nil
+}
+
+// Add registers the repository containing path. It returns the repository and whether it was newly added.
+func (r *Registry) Add(path string) (*Repository, bool, error) {
+	root, err := utils.RunCommand("git", helpers.GitArgs(path, "rev-parse", "--show-toplevel")...)
+	if err != nil || root == "" {
+		return nil, false, fmt.Errorf("%s is not a git repository", path)
+	}
+
+	if existing := r.Find(root); existing != nil {
+		return existing, false, nil
+	}
+
+	r.Repositories = append(r.Repositories, Repository{Name: filepath.Base(root), Path: root, AddedAt: time.Now()})
+
+	return &r.Repositories[len(r.Repositories)-1], true, nil
+}
+
+// Remove unregisters the repository with the given path and reports whether it was found.
+func (r *Registry) Remove(path string) bool {
+	for i := range r.Repositories {
+		if r.Repositories[i].Path == path || r.Repositories[i].Name == path {
+			r.Repositories = append(r.Repositories[:i], r.Repositories[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+var skippedScanDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// Scan returns the paths of all git repositories found under dir, descending at most maxDepth levels.
+// Repositories nested inside other repositories are not included.
+func Scan(dir string, maxDepth int) ([]string, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0)
+
+	err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			// skip unreadable directories instead of aborting the scan
+			return nil
+		}
+
+		depth := 0
+		if rel, _ := filepath.Rel(root, path); rel != "." {
+			depth = strings.Count(rel, string(filepath.Separator)) + 1
+		}
+
+		if path != root && (skippedScanDirs[d.Name()] || (len(d.Name()) > 1 && d.Name()[0] == '.')) {
+			return filepath.SkipDir
+		}
+
+		if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+			result = append(result, path)
+			return filepath.SkipDir
+		}
+
+		if depth >= maxDepth {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+
+	return result, err
+}
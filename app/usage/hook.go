@@ -0,0 +1,61 @@
+package usage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/permafrost-dev/git-ninja/app/utils"
+)
+
+const hookMarker = "# git-ninja: record branch usage"
+
+// post-checkout receives the previous HEAD, the new HEAD and a flag that is 1 for branch checkouts
+const postCheckoutHookLine = `[ "$3" = "1" ] && git-ninja usage:record checkout >/dev/null 2>&1 || true`
+
+// GetHooksDir returns the hooks directory of the current repository, respecting core.hooksPath.
+func GetHooksDir() (string, error) {
+	dir, err := utils.RunCommand("git", "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Abs(dir)
+}
+
+// InstallPostCheckoutHook adds the usage recording command to the repository's post-checkout hook,
+// creating the hook if needed. Existing hooks are preserved. It returns the path of the hook file.
+func InstallPostCheckoutHook() (string, error) {
+	hooksDir, err := GetHooksDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate hooks directory: %v", err)
+	}
+
+	hookFile := filepath.Join(hooksDir, "post-checkout")
+	contents := "#!/bin/sh\n"
+
+	if existing, err := os.ReadFile(hookFile); err == nil {
+		if strings.Contains(string(existing), hookMarker) {
+			return hookFile, nil
+		}
+		contents = strings.TrimRight(string(existing), "\n") + "\n"
+	}
+
+	contents += "\n" + hookMarker + "\n" + postCheckoutHookLine + "\n"
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create hooks directory: %v", err)
+	}
+
+	if err := os.WriteFile(hookFile, []byte(contents), 0755); err != nil {
+		return "", fmt.Errorf("failed to write hook: %v", err)
+	}
+
+	// existing hooks keep their permissions when written, so make sure git can run them
+	if err := os.Chmod(hookFile, 0755); err != nil {
+		return "", fmt.Errorf("failed to make hook executable: %v", err)
+	}
+
+	return hookFile, nil
+}
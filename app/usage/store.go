@@ -0,0 +1,312 @@
+package usage
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/app/git"
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/reflog"
+	"github.com/permafrost-dev/git-ninja/app/utils"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/cache"
+)
+
+type EventType string
+
+const (
+	EventCheckout EventType = "checkout"
+	EventCommit   EventType = "commit"
+	EventPush     EventType = "push"
+)
+
+const (
+	SourceRefLog   = "reflog"
+	SourceHook     = "hook"
+	SourceGitNinja = "git-ninja"
+)
+
+// duplicateWindow is how far apart two events of the same type for the same branch may be
+// and still be considered the same event, i.e. one recorded by a hook and one read from the reflog.
+const duplicateWindow = 2 * time.Second
+
+// Event is a single branch action observed by git-ninja
+type Event struct {
+	Type      EventType `json:"type"`
+	Branch    string    `json:"branch"`
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source"`
+}
+
+// Store holds the usage history of a single repository
+type Store struct {
+	Repository string    `json:"repository"`
+	LastSync   time.Time `json:"last_sync"`
+	Events     []Event   `json:"events"`
+
+	fileName string
+	index    map[string]bool
+}
+
+// StoreFileName returns the path of the usage file for the repository located at repoRoot.
+func StoreFileName(repoRoot string) string {
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(repoRoot)))
+
+	return filepath.Join(config.DataDir(), "usage", hash[:16]+".json")
+}
+
+// NewMemoryStore returns an empty store for the repository located at repoRoot that is never written to disk.
+func NewMemoryStore(repoRoot string) *Store {
+	store := &Store{Repository: repoRoot, Events: make([]Event, 0)}
+	store.buildIndex()
+
+	return store
+}
+
+// Open loads the usage store for the repository located at repoRoot, returning an empty store if none exists.
+func Open(repoRoot string) (*Store, error) {
+	store := &Store{Repository: repoRoot, Events: make([]Event, 0), fileName: StoreFileName(repoRoot)}
+
+	if err := store.load(store.fileName); err != nil {
+		return nil, err
+	}
+
+	store.buildIndex()
+
+	return store, nil
+}
+
+// load decodes the usage file fileName into the store, leaving it unchanged if the file doesn't exist.
+func (s *Store) load(fileName string) error {
+	data, err := os.ReadFile(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to read usage file: %v", err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return fmt.Errorf("failed to parse usage file %s: %v", fileName, err)
+	}
+
+	return nil
+}
+
+// OpenCurrent loads the usage store for the repository in the current working directory.
+func OpenCurrent() (*Store, error) {
+	root, err := helpers.GetRepositoryRoot()
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository: %v", err)
+	}
+
+	return Open(root)
+}
+
+// Save writes the store to disk, replacing the existing file atomically. Events saved by other processes since the
+// store was opened, e.g. by the post-checkout hook, are merged into it first, while holding the file's lock. Memory
+// stores are not saved.
+func (s *Store) Save() error {
+	if s.fileName == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.fileName), 0755); err != nil {
+		return fmt.Errorf("failed to create usage directory: %v", err)
+	}
+
+	unlock, err := cache.LockFile(s.fileName)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	saved := &Store{}
+	if err := saved.load(s.fileName); err != nil {
+		return err
+	}
+
+	for _, e := range saved.Events {
+		s.Add(e)
+	}
+
+	if saved.LastSync.After(s.LastSync) {
+		s.LastSync = saved.LastSync
+	}
+
+	sort.SliceStable(s.Events, func(i, j int) bool {
+		return s.Events[i].Timestamp.Before(s.Events[j].Timestamp)
+	})
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode usage data: %v", err)
+	}
+
+	if err := cache.WriteFile(s.fileName, data); err != nil {
+		return fmt.Errorf("failed to write usage file: %v", err)
+	}
+
+	return nil
+}
+
+func eventKey(eventType EventType, branch string, timestamp int64) string {
+	return fmt.Sprintf("%s|%s|%d", eventType, branch, timestamp)
+}
+
+func (s *Store) buildIndex() {
+	s.index = make(map[string]bool, len(s.Events))
+
+	for _, e := range s.Events {
+		s.index[eventKey(e.Type, e.Branch, e.Timestamp.Unix())] = true
+	}
+}
+
+// Contains reports whether an equivalent event has already been recorded.
+func (s *Store) Contains(e Event) bool {
+	window := int64(duplicateWindow.Seconds())
+
+	for ts := e.Timestamp.Unix() - window; ts <= e.Timestamp.Unix()+window; ts++ {
+		if s.index[eventKey(e.Type, e.Branch, ts)] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Add records an event unless an equivalent one already exists, and reports whether it was added.
+func (s *Store) Add(e Event) bool {
+	if e.Branch == "" || s.Contains(e) {
+		return false
+	}
+
+	s.Events = append(s.Events, e)
+	s.index[eventKey(e.Type, e.Branch, e.Timestamp.Unix())] = true
+
+	return true
+}
+
+// CountEvents returns the number of recorded events of a type for a branch.
+func (s *Store) CountEvents(eventType EventType, branch string) int {
+	count := 0
+
+	for _, e := range s.Events {
+		if e.Type == eventType && e.Branch == branch {
+			count++
+		}
+	}
+
+	return count
+}
+
+// SyncFromStats imports the checkouts and commits found by analyzing the reflogs, and returns the number of new events.
+func (s *Store) SyncFromStats(stats map[string]*reflog.BranchStats) int {
+	added := 0
+
+	for name, branch := range stats {
+		for _, ts := range branch.Checkouts {
+			if s.Add(Event{Type: EventCheckout, Branch: name, Timestamp: ts, Source: SourceRefLog}) {
+				added++
+			}
+		}
+
+		for _, ts := range branch.Commits {
+			if s.Add(Event{Type: EventCommit, Branch: name, Timestamp: ts, Source: SourceRefLog}) {
+				added++
+			}
+		}
+	}
+
+	s.LastSync = time.Now()
+
+	return added
+}
+
+// Sync imports new entries from the HEAD and branch reflogs of the store's repository.
+func (s *Store) Sync() (int, error) {
+	repo, err := reflog.Open(s.Repository)
+	if err != nil {
+		return 0, err
+	}
+	defer repo.Close()
+
+	stats, err := reflog.AnalyzeRepository(repo)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.SyncFromStats(stats), nil
+}
+
+// BranchInfos returns the checkout history and commit count of each branch found in existingBranches.
+func (s *Store) BranchInfos(existingBranches map[string]bool) map[string]git.BranchInfo {
+	branches := make(map[string]git.BranchInfo)
+
+	for _, e := range s.Events {
+		if !utils.MapEntryExists(e.Branch, existingBranches) {
+			continue
+		}
+
+		data := branches[e.Branch]
+		data.Name = e.Branch
+
+		switch e.Type {
+		case EventCheckout:
+			data.CheckoutCount++
+			data.CheckoutHistory = append(data.CheckoutHistory, &git.BranchCheckoutInfo{
+				BranchName:   e.Branch,
+				RelativeTime: utils.GetRelativeTime(e.Timestamp),
+				Timestamp:    e.Timestamp,
+			})
+			if e.Timestamp.After(data.CheckedOutLast) {
+				data.CheckedOutLast = e.Timestamp
+			}
+		case EventCommit:
+			data.CommitCount++
+		}
+
+		branches[e.Branch] = data
+	}
+
+	// branches that were committed to but never checked out don't have a usable history
+	for name, data := range branches {
+		if data.CheckoutCount == 0 {
+			delete(branches, name)
+		}
+	}
+
+	return branches
+}
+
+// EventsSince returns the events recorded at or after the given time.
+func (s *Store) EventsSince(since time.Time) []Event {
+	result := make([]Event, 0)
+
+	for _, e := range s.Events {
+		if !e.Timestamp.Before(since) {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
+// Record adds a single event for the current repository and saves the store.
+func Record(eventType EventType, branch string, source string) error {
+	store, err := OpenCurrent()
+	if err != nil {
+		return err
+	}
+
+	store.Add(Event{Type: eventType, Branch: branch, Timestamp: time.Now(), Source: source})
+
+	return store.Save()
+}
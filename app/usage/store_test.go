@@ -0,0 +1,60 @@
+package usage_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/app/usage"
+)
+
+func TestSaveKeepsEventsSavedConcurrently(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	const repo = "/src/repo"
+	const writers = 20
+
+	// a listing command opens the store before the hooks record their events, and saves it afterwards
+	listing, err := usage.Open(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(writers)
+
+	for i := 0; i < writers; i++ {
+		go func(i int) {
+			defer wg.Done()
+
+			store, err := usage.Open(repo)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			store.Add(usage.Event{Type: usage.EventCheckout, Branch: fmt.Sprintf("branch-%d", i), Timestamp: time.Now(), Source: usage.SourceHook})
+
+			if err := store.Save(); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	listing.Add(usage.Event{Type: usage.EventCheckout, Branch: "main", Timestamp: time.Now(), Source: usage.SourceRefLog})
+	if err := listing.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := usage.Open(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(store.Events) != writers+1 {
+		t.Errorf("expected %d events, got %d", writers+1, len(store.Events))
+	}
+}
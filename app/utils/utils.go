@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 func RunCommand(command string, args ...string) (string, error) {
@@ -66,3 +67,37 @@ func MapEntryExists(key string, mappedData map[string]bool) bool {
 	_, exists := mappedData[key]
 	return exists
 }
+
+// TruncateString shortens s to at most maxLength characters, ending it with an ellipsis when it is cut.
+func TruncateString(s string, maxLength int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLength {
+		return s
+	}
+
+	return string(runes[:maxLength-1]) + "…"
+}
+
+// Slugify lowercases s and replaces each run of characters other than letters and digits with a hyphen, for
+// use in branch names, e.g. "Fix the login page!" becomes "fix-the-login-page". The result is cut at a word
+// boundary to at most maxLength characters.
+func Slugify(s string, maxLength int) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	result := ""
+	for _, word := range words {
+		if result != "" {
+			word = "-" + word
+		}
+
+		if len(result)+len(word) > maxLength {
+			break
+		}
+
+		result += word
+	}
+
+	return result
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/app/output"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/credentials"
+	"github.com/spf13/cobra"
+)
+
+// authIntegration is an integration whose credentials are reported by auth:status
+type authIntegration struct {
+	credentials credentials.Integration
+	// check reports why the integration can't be used with the resolved credentials, if it can't
+	check func(resolved *credentials.Resolved) error
+}
+
+// authIntegrations lists the integrations reported by auth:status
+var authIntegrations = []authIntegration{
+	{credentials: jiraCredentials, check: func(resolved *credentials.Resolved) error {
+		_, err := newJiraClient(resolved)
+		return err
+	}},
+	{credentials: linearCredentials, check: func(resolved *credentials.Resolved) error {
+		_, err := newLinearClient(resolved)
+		return err
+	}},
+	{credentials: githubCredentials, check: func(resolved *credentials.Resolved) error {
+		_, err := newGitHubClient(resolved)
+		return err
+	}},
+	{credentials: gitlabCredentials, check: func(resolved *credentials.Resolved) error {
+		_, err := newGitLabClient(resolved)
+		return err
+	}},
+	{credentials: giteaCredentials, check: func(resolved *credentials.Resolved) error {
+		_, err := newGiteaClient(resolved)
+		return err
+	}},
+}
+
+// credentialChain returns the sources of integration credentials, in order of precedence: environment
+// variables, git config (ninja.<integration>.<field>), ~/.netrc, the configured credential helper, the
+// credentials file in the config directory, and finally the settings in the config file.
+func credentialChain() credentials.Chain {
+	settings := credentials.SettingsProvider{
+		FileName: config.ConfigFileName(),
+		Values: map[string]map[string]string{
+			"jira":   {"base_url": config.Get().Jira.BaseURL},
+			"linear": {"api_url": config.Get().Linear.APIURL},
+			"github": {"base_url": config.Get().GitHub.BaseURL},
+			"gitlab": {"base_url": config.Get().GitLab.BaseURL},
+			"gitea":  {"base_url": config.Get().Gitea.BaseURL},
+		},
+	}
+
+	return credentials.Chain{
+		credentials.EnvProvider{},
+		credentials.GitConfigProvider{},
+		credentials.NetrcProvider{FileName: credentials.DefaultNetrcFileName()},
+		&credentials.HelperProvider{Helper: config.Get().Credentials.Helper},
+		credentials.FileProvider{FileName: config.CredentialsFileName()},
+		settings,
+	}
+}
+
+// describeCredential returns the value of a credential for display, hiding secrets.
+func describeCredential(c credentials.Credential) string {
+	switch {
+	case c.Value == "":
+		return "(not set)"
+	case c.Field.IsSecret():
+		return "********"
+	}
+
+	return c.Value
+}
+
+func init() {
+	authStatusCmd := &cobra.Command{
+		Use:   "auth:status",
+		Short: "Show which sources supplied the credentials of each integration",
+		Long: `Show which sources supplied the credentials of each integration, without displaying secrets.
+
+Credentials are looked up in order of precedence in environment variables, git config (ninja.<integration>.<field>),
+~/.netrc (by host), the credential helper set as credentials.helper in the config file, the credentials file
+in the config directory, and the settings in the config file, such as jira.base_url.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			chain := credentialChain()
+
+			for i, integration := range authIntegrations {
+				if i > 0 {
+					fmt.Println()
+				}
+
+				resolved := chain.Resolve(integration.credentials)
+
+				title := "\033[37;1m" + integration.credentials.Name + "\033[0m"
+				if resolved.Host != "" {
+					title += " (" + resolved.Host + ")"
+				}
+				fmt.Println(title)
+
+				table := &output.Table{Headers: []string{"CREDENTIAL", "VALUE", "SOURCE"}}
+				for _, c := range resolved.Credentials {
+					table.AddRow(c.Field.Name, describeCredential(c), firstNonEmpty(c.Source, "-"))
+				}
+
+				if err := table.WriteText(os.Stdout); err != nil {
+					fmt.Printf("Error: %v\n", err)
+					return
+				}
+
+				for _, warning := range resolved.Warnings {
+					fmt.Printf("  \033[33mwarning:\033[0m %v\n", warning)
+				}
+
+				if err := integration.check(resolved); err != nil {
+					fmt.Printf("  \033[31mnot configured:\033[0m %v\n", err)
+				} else {
+					fmt.Printf("  \033[32mconfigured\033[0m\n")
+				}
+			}
+		},
+	}
+
+	rootCmd.AddCommand(authStatusCmd)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/usage"
 	"github.com/spf13/cobra"
 )
 
@@ -15,6 +16,9 @@ func init() {
 	flagForce := false
 	flagRebase := "main"
 	flagMerge := ""
+	flagNoTransition := false
+	flagPR := false
+	flagDraft := false
 
 	cmd := &cobra.Command{
 		Use:   "branch:current",
@@ -56,10 +60,22 @@ func init() {
 					args = append(args, "--force")
 				}
 
-				helpers.RunCommandOnStdout("git", args...)
+				if err := helpers.RunCommandOnStdout("git", args...); err == nil {
+					recordUsage(usage.EventPush, branchName)
+
+					if !flagNoTransition {
+						transitionBranchIssue(branchName, transitionReview)
+					}
+				}
+			}
+
+			if flagPR {
+				if err := createPullRequest(branchName, pullRequestOptions{Draft: flagDraft, NoTransition: flagNoTransition}); err != nil {
+					fmt.Printf("Error: %v\n", err)
+				}
 			}
 
-			if !flagPull && !flagPush {
+			if !flagPull && !flagPush && !flagPR {
 				fmt.Println(branchName)
 			}
 		},
@@ -72,6 +88,9 @@ func init() {
 	cmd.Flags().BoolVarP(&flagForce, "force", "F", false, "when pushing, perform a force push")
 	cmd.Flags().StringVarP(&flagRebase, "rebase", "R", "", "rebase the current branch using the specified branch")
 	cmd.Flags().StringVarP(&flagMerge, "merge", "M", "", "merge the specified branch into the current branch")
+	cmd.Flags().BoolVar(&flagNoTransition, "no-transition", false, "when pushing, don't move the linked issue to In Review")
+	cmd.Flags().BoolVar(&flagPR, "pr", false, "open a pull request for the current branch, pushing it if needed (see pr:create)")
+	cmd.Flags().BoolVar(&flagDraft, "draft", false, "with --pr, open the pull request as a draft")
 
 	rootCmd.AddCommand(cmd)
 }
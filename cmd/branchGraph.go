@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/permafrost-dev/git-ninja/app/git"
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/ranking"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	flagGraphCount := 10
+	flagGraphAscii := false
+	flagGraphBase := ""
+	flagGraphSort := ""
+
+	branchGraphCmd := &cobra.Command{
+		Use:   "branch:graph [--count|-c <count>] [--base|-b <branch>] [--ascii]",
+		Short: "Show where recent branches forked from the default branch and how they are stacked",
+		Run: func(cmd *cobra.Command, args []string) {
+			existingBranches, _ := helpers.GetAvailableBranchesMap()
+			currentBranch, _ := helpers.GetCurrentBranchName()
+
+			defaultBranch := flagGraphBase
+			if defaultBranch == "" {
+				var err error
+				if defaultBranch, err = helpers.GetDefaultBranchName("origin"); err != nil {
+					fmt.Printf("Error: %v, use --base to specify it\n", err)
+					return
+				}
+			}
+
+			sorted, err := getRecentBranchesRanked(existingBranches, "", flagGraphSort, nil, nil)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			branches := make([]string, 0, flagGraphCount)
+			for _, ranked := range sorted {
+				if ranked.Branch.Name == defaultBranch {
+					continue
+				}
+
+				if branches = append(branches, ranked.Branch.Name); len(branches) >= flagGraphCount {
+					break
+				}
+			}
+
+			if len(branches) == 0 {
+				fmt.Println("No recent branches found.")
+				return
+			}
+
+			graph, err := git.BuildBranchGraph(".", defaultBranch, branches)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			style := git.UnicodeGraphStyle
+			if flagGraphAscii {
+				style = git.ASCIIGraphStyle
+			}
+
+			graph.Render(os.Stdout, style, func(b *git.GraphBranch) string {
+				if b.Name == currentBranch {
+					return " \033[33m(current)\033[0m"
+				}
+				return ""
+			})
+		},
+	}
+
+	branchGraphCmd.Flags().IntVarP(&flagGraphCount, "count", "c", 10, "Limit the number of branches to display")
+	branchGraphCmd.Flags().StringVarP(&flagGraphBase, "base", "b", "", "Branch to compute fork points from (default: the remote's default branch, main or master)")
+	branchGraphCmd.Flags().BoolVar(&flagGraphAscii, "ascii", false, "Draw the graph using ASCII characters only")
+	branchGraphCmd.Flags().StringVarP(&flagGraphSort, "sort", "s", "", "Ranking strategy used to select recent branches: "+strings.Join(ranking.Names(), ", "))
+
+	rootCmd.AddCommand(branchGraphCmd)
+}
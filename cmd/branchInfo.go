@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/utils"
+	"github.com/spf13/cobra"
+)
+
+// printBranchInfoLine prints a labelled line of branch:info.
+func printBranchInfoLine(label string, value string) {
+	fmt.Printf("  \033[33m%-15s\033[0m %s\n", label, value)
+}
+
+// describeAheadBehind describes how far a branch has diverged from another, e.g. "main (ahead 2, behind 5)".
+func describeAheadBehind(base string, branch string) string {
+	ahead, behind, err := helpers.GetAheadBehind(base, branch)
+	if err != nil {
+		return base
+	}
+
+	return fmt.Sprintf("%s (ahead %d, behind %d)", base, ahead, behind)
+}
+
+func init() {
+	flagNoIssue := false
+	flagNoPR := false
+
+	cmd := &cobra.Command{
+		Use:   "branch:info [branch]",
+		Short: "Show the last commit, upstream, linked issue and pull request of a branch",
+		Long: `Show the last commit, last checkout, upstream and divergence from the default branch of a branch, the current
+branch by default. When an issue tracker or forge is configured, the linked issue and the state, review and checks
+of the branch's pull request are shown as well.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			branchName, _ := helpers.GetCurrentBranchName()
+			if len(args) > 0 {
+				branchName = args[0]
+			}
+
+			if exists, _ := helpers.BranchExists(branchName); !exists || branchName == "" {
+				fmt.Printf("Error: branch '%s' does not exist\n", branchName)
+				return
+			}
+
+			fmt.Printf("\033[37;1m%s\033[0m\n", branchName)
+
+			if commit, err := utils.RunCommand("git", "log", "-1", "--format=%h%x09%cr%x09%s", "refs/heads/"+branchName); err == nil {
+				if parts := strings.SplitN(commit, "\t", 3); len(parts) == 3 {
+					printBranchInfoLine("last commit", fmt.Sprintf("%s %s, %s", parts[0], parts[1], utils.TruncateString(parts[2], 60)))
+				}
+			}
+
+			existing := map[string]bool{branchName: true}
+			if usage, ok := getBranchUsage(existing)[branchName]; ok && !usage.CheckedOutLast.IsZero() {
+				checkouts := fmt.Sprintf("%d checkouts", usage.CheckoutCount)
+				if usage.CheckoutCount == 1 {
+					checkouts = "1 checkout"
+				}
+
+				printBranchInfoLine("last checkout", fmt.Sprintf("%s (%s)", utils.GetRelativeTime(usage.CheckedOutLast), checkouts))
+			}
+
+			if upstream := helpers.GetUpstreamBranchName(branchName); upstream != "" {
+				printBranchInfoLine("upstream", describeAheadBehind(upstream, branchName))
+			} else {
+				printBranchInfoLine("upstream", "(none)")
+			}
+
+			if defaultBranch, err := helpers.GetDefaultBranchName(firstNonEmpty(config.Get().Forge.Remote, "origin")); err == nil && defaultBranch != branchName {
+				printBranchInfoLine("default branch", describeAheadBehind(defaultBranch, branchName))
+			}
+
+			if !flagNoIssue {
+				if issue := getBranchIssues([]string{branchName})[branchName]; issue != nil {
+					printBranchInfoLine("issue", fmt.Sprintf("%s %s[%s]\033[0m %s", issue.Key, issueStatusColor(issue), issue.Status, issue.Summary))
+					if issue.URL != "" {
+						printBranchInfoLine("", issue.URL)
+					}
+				}
+			}
+
+			if !flagNoPR {
+				if pr := getBranchPullRequests([]string{branchName})[branchName]; pr != nil {
+					printBranchInfoLine("pull request", fmt.Sprintf("%s%s\033[0m %s", pullRequestStateColor(pr), describePullRequest(pr), pr.Title))
+					printBranchInfoLine("", pr.URL)
+				} else {
+					printBranchInfoLine("pull request", "(none)")
+				}
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&flagNoIssue, "no-issue", false, "Don't look up the linked issue")
+	cmd.Flags().BoolVar(&flagNoPR, "no-pr", false, "Don't look up the pull request")
+
+	rootCmd.AddCommand(cmd)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	flagNoTransition := false
+
+	cmd := &cobra.Command{
+		Use:   "branch:new <name|issue-key> [start-point]",
+		Short: "Create and check out a new branch",
+		Long: `Creates and checks out a new branch. When issue transitions are enabled, the issue linked to the branch is moved to In Progress.
+
+When the name is an issue key such as ENG-123, the branch is named after the issue: the branch name suggested by
+the issue tracker is used when it has one, otherwise the key is followed by the issue's summary.`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			if tracker, err := getIssueTracker(); err == nil && strings.EqualFold(tracker.MatchBranch(args[0]), args[0]) {
+				args[0] = branchNameForIssue(tracker, tracker.MatchBranch(args[0]))
+			}
+
+			if exists, _ := helpers.BranchExists(args[0]); exists {
+				fmt.Printf("error: branch '%s' already exists\n", args[0])
+				return
+			}
+
+			if err := helpers.RunCommandOnStdout("git", append([]string{"checkout", "-b"}, args...)...); err != nil {
+				return
+			}
+
+			if !flagNoTransition {
+				transitionBranchIssue(args[0], transitionStart)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&flagNoTransition, "no-transition", false, "Don't move the linked issue to In Progress")
+
+	rootCmd.AddCommand(cmd)
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/utils"
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+	"github.com/spf13/cobra"
+)
+
+// prunableBranch is a local branch considered by branch:prune
+type prunableBranch struct {
+	Name string
+	// Reason explains why the branch is safe to delete, or why it is kept
+	Reason string
+	Safe   bool
+}
+
+// findPrunableBranches returns the local branches that are merged into the default branch, or whose pull requests
+// were merged, along with the branches whose pull requests were merged but that have commits the pull request
+// doesn't, which are not safe to delete. The current and default branches, branches with open pull requests and
+// merged branches pointing to a commit made on the default branch itself are never included.
+func findPrunableBranches(defaultBranch string, currentBranch string, exclude string, prs map[string]*integrations.PullRequest) ([]prunableBranch, error) {
+	existing, err := helpers.GetAvailableBranchesMap()
+	if err != nil {
+		return nil, err
+	}
+
+	mergedNames, err := helpers.GetMergedBranchNames(defaultBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]bool)
+	for _, name := range mergedNames {
+		merged[name] = true
+	}
+
+	// a branch pointing to a commit made on the default branch, e.g. one just created from it, has no commits of
+	// its own to prune
+	mainline, err := helpers.GetFirstParentCommits(defaultBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(existing))
+	for name := range existing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]prunableBranch, 0)
+
+	for _, name := range names {
+		if name == defaultBranch || name == currentBranch || utils.StringMatchesRegexPattern(exclude, name) {
+			continue
+		}
+
+		pr := prs[name]
+		if pr != nil && pr.IsOpen() {
+			continue
+		}
+
+		tip, err := helpers.GetBranchTip(name)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case merged[name] && !mainline[tip]:
+			result = append(result, prunableBranch{Name: name, Reason: "merged into " + defaultBranch, Safe: true})
+		case pr != nil && pr.IsMerged() && helpers.IsAncestor(tip, pr.HeadSHA):
+			result = append(result, prunableBranch{Name: name, Reason: fmt.Sprintf("pull request %s merged", pr.Ref()), Safe: true})
+		case pr != nil && pr.IsMerged():
+			result = append(result, prunableBranch{Name: name, Reason: fmt.Sprintf("pull request %s merged, but the branch has commits it doesn't", pr.Ref())})
+		}
+	}
+
+	return result, nil
+}
+
+func init() {
+	flagDryRun := false
+	flagYes := false
+	flagNoPRs := false
+	flagExclude := ""
+
+	cmd := &cobra.Command{
+		Use:   "branch:prune",
+		Short: "Delete local branches that are merged or whose pull requests were merged",
+		Long: `Delete the local branches that are merged into the default branch, or whose pull requests were merged on the
+repository's forge, e.g. with a squash merge. A branch whose pull request was merged is only deleted when the
+merged pull request contains all of its commits. The current and default branches, branches with open pull
+requests and branches without commits of their own, i.e. pointing to a commit made on the default branch (such
+as a new branch), are kept.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			defaultBranch, err := helpers.GetDefaultBranchName(firstNonEmpty(config.Get().Forge.Remote, "origin"))
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			currentBranch, _ := helpers.GetCurrentBranchName()
+
+			prs := map[string]*integrations.PullRequest{}
+			if !flagNoPRs {
+				existing, _ := helpers.GetAvailableBranchesMap()
+
+				names := make([]string, 0, len(existing))
+				for name := range existing {
+					names = append(names, name)
+				}
+
+				prs = getBranchPullRequests(names)
+			}
+
+			branches, err := findPrunableBranches(defaultBranch, currentBranch, flagExclude, prs)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			safe := make([]string, 0, len(branches))
+			for _, branch := range branches {
+				if branch.Safe {
+					safe = append(safe, branch.Name)
+					fmt.Printf("  \033[37;1m%s\033[0m  \033[2m%s\033[0m\n", branch.Name, branch.Reason)
+				} else {
+					fmt.Printf("  \033[33mkeeping %s\033[0m  \033[2m%s\033[0m\n", branch.Name, branch.Reason)
+				}
+			}
+
+			if len(safe) == 0 {
+				fmt.Println("No branches to prune.")
+				return
+			}
+
+			if flagDryRun {
+				return
+			}
+
+			question := fmt.Sprintf("\nDelete %d branches?", len(safe))
+			if len(safe) == 1 {
+				question = "\nDelete 1 branch?"
+			}
+
+			if !flagYes && !confirm(question) {
+				return
+			}
+
+			for _, name := range safe {
+				if _, err := utils.RunCommand("git", "branch", "-D", name); err != nil {
+					fmt.Printf("Error: failed to delete %s: %v\n", name, err)
+					continue
+				}
+
+				fmt.Printf("Deleted %s\n", name)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&flagDryRun, "dry-run", "n", false, "Only list the branches that would be deleted")
+	cmd.Flags().BoolVarP(&flagYes, "yes", "y", false, "Delete the branches without asking for confirmation")
+	cmd.Flags().BoolVar(&flagNoPRs, "no-prs", false, "Only delete branches merged into the default branch, without looking up pull requests")
+	cmd.Flags().StringVarP(&flagExclude, "exclude", "e", "", "Keep branches that match the provided regex")
+
+	rootCmd.AddCommand(cmd)
+}
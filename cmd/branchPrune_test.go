@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func runGit(t *testing.T, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null")
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+}
+
+func TestFindPrunableBranchesKeepsBranchesWithoutCommitsOfTheirOwn(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	runGit(t, "init", "--quiet", "--initial-branch=main")
+	runGit(t, "commit", "--quiet", "--allow-empty", "-m", "initial")
+	runGit(t, "branch", "new")
+	runGit(t, "checkout", "--quiet", "-b", "feature")
+	runGit(t, "commit", "--quiet", "--allow-empty", "-m", "feature")
+	runGit(t, "checkout", "--quiet", "main")
+	runGit(t, "commit", "--quiet", "--allow-empty", "-m", "later")
+	runGit(t, "merge", "--quiet", "--no-ff", "-m", "merge feature", "feature")
+
+	branches, err := findPrunableBranches("main", "main", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(branches) != 1 || branches[0].Name != "feature" || !branches[0].Safe {
+		t.Errorf("expected only feature to be pruned, got %+v", branches)
+	}
+}
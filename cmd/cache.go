@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/app/reflog"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/cache"
+	"github.com/spf13/cobra"
+)
+
+// configureCache applies the durations of the config file's cache section to an integration's cache. Missing or
+// invalid durations keep the cache's defaults, and a TTL of "0" disables expiry.
+func configureCache(store *cache.Store) {
+	cfg := config.Get().Cache
+
+	if d, err := time.ParseDuration(cfg.TTL); err == nil && d >= 0 {
+		store.TTL = d
+	}
+
+	if d, err := time.ParseDuration(cfg.MaxStale); err == nil && d >= 0 {
+		store.MaxStale = d
+	}
+}
+
+func init() {
+	flagVerbose := false
+	flagIntegrations := false
+
+	cacheStatusCmd := &cobra.Command{
+		Use:   "cache:status",
+		Short: "Show the state of the reflog cache",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			repo, err := reflog.Open("")
+			if err != nil {
+				fmt.Printf("error: %v\n", err)
+				return
+			}
+
+			reflogCache := repo.Cache()
+			statuses := reflogCache.Status(repo.CommonDir)
+
+			entries, stale := 0, 0
+			for _, status := range statuses {
+				entries += status.Entries
+				if !status.Fresh {
+					stale++
+				}
+			}
+
+			fmt.Printf("Cache file: %s\n", reflogCache.FileName())
+			fmt.Printf("Cached logs: %d (%d stale)\n", len(statuses), stale)
+			fmt.Printf("Cached entries: %d\n", entries)
+			fmt.Printf("Integration cache: %s\n", cache.DefaultDir())
+
+			if !flagVerbose {
+				return
+			}
+
+			fmt.Println()
+			for _, status := range statuses {
+				state := "\033[32mfresh\033[0m"
+				if !status.Fresh {
+					state = "\033[33mstale\033[0m"
+				}
+				fmt.Printf("  %s  %7d entries  %s\n", state, status.Entries, status.Name)
+			}
+		},
+	}
+
+	cacheClearCmd := &cobra.Command{
+		Use:   "cache:clear",
+		Short: "Remove the reflog cache",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if flagIntegrations {
+				if err := cache.NewStore(cache.DefaultDir()).Clear(); err != nil {
+					fmt.Printf("error: failed to clear cache: %v\n", err)
+					return
+				}
+
+				fmt.Println("Integration cache cleared.")
+				return
+			}
+
+			repo, err := reflog.Open("")
+			if err != nil {
+				fmt.Printf("error: %v\n", err)
+				return
+			}
+
+			if err := repo.Cache().Clear(); err != nil {
+				fmt.Printf("error: failed to clear cache: %v\n", err)
+				return
+			}
+
+			fmt.Println("Reflog cache cleared.")
+		},
+	}
+
+	cacheStatusCmd.Flags().BoolVarP(&flagVerbose, "verbose", "v", false, "Show the state of each cached log")
+
+	cacheClearCmd.Flags().BoolVar(&flagIntegrations, "integrations", false, "Remove the cache of data fetched from JIRA and other integrations instead")
+
+	rootCmd.AddCommand(cacheStatusCmd, cacheClearCmd)
+}
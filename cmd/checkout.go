@@ -9,6 +9,7 @@ import (
 
 func init() {
 	var flagAutoPull bool = false
+	var flagNoTransition bool = false
 
 	var checkoutCmd = &cobra.Command{
 		Use:     "checkout",
@@ -24,6 +25,10 @@ func init() {
 				return
 			}
 
+			if !flagNoTransition {
+				transitionIfFirstCheckout()
+			}
+
 			if flagAutoPull {
 				currentBranch, _ := helpers.GetCurrentBranchName()
 
@@ -41,4 +46,5 @@ func init() {
 
 	rootCmd.AddCommand(checkoutCmd)
 	checkoutCmd.Flags().BoolVarP(&flagAutoPull, "pull", "p", false, "Automatically pull origin after checkout")
+	checkoutCmd.Flags().BoolVar(&flagNoTransition, "no-transition", false, "Don't move the linked issue to In Progress on the first checkout")
 }
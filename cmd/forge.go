@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/app/utils"
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+)
+
+// urlHostname returns the host name of a URL in lowercase, or an empty string if it is invalid.
+func urlHostname(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(parsed.Hostname())
+}
+
+// getForge returns the forge hosting the repository of the configured remote: the one set as forge.provider
+// in the config file, or the one detected from the remote's host.
+func getForge() (integrations.Forge, error) {
+	remote := firstNonEmpty(config.Get().Forge.Remote, "origin")
+
+	remoteURL, err := utils.RunCommand("git", "remote", "get-url", remote)
+	if err != nil || remoteURL == "" {
+		return nil, fmt.Errorf("the repository has no remote named '%s'", remote)
+	}
+
+	repo, err := integrations.ParseRemoteURL(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return integrations.NewForge(config.Get().Forge.Provider, repo)
+}
+
+// getBranchPullRequests looks up the pull requests of the branches on the repository's forge, keyed by branch
+// name. Lookup failures are reported on stderr and result in fewer or no pull requests.
+func getBranchPullRequests(branchNames []string) map[string]*integrations.PullRequest {
+	result := make(map[string]*integrations.PullRequest)
+
+	forge, err := getForge()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		return result
+	}
+
+	if err := forge.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %s is not configured: %v\n", forge.Name(), err)
+		return result
+	}
+
+	pulls, err := forge.PullRequests(branchNames)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to look up pull requests: %v\n", err)
+	}
+
+	for name, pr := range pulls {
+		result[name] = pr
+	}
+
+	return result
+}
+
+// pullRequestStateColor returns the color used to display a pull request's state.
+func pullRequestStateColor(pr *integrations.PullRequest) string {
+	switch {
+	case pr.IsMerged():
+		return "\033[35m"
+	case pr.State == integrations.PullRequestClosed:
+		return "\033[31m"
+	case pr.Draft:
+		return "\033[2m"
+	default:
+		return "\033[32m"
+	}
+}
+
+// describePullRequest returns a pull request's number and state, followed by its review and check states while
+// it is open, e.g. "#12 open, approved, checks passing".
+func describePullRequest(pr *integrations.PullRequest) string {
+	state := pr.State
+	if pr.IsOpen() && pr.Draft {
+		state = "draft"
+	}
+
+	parts := []string{pr.Ref() + " " + state}
+
+	if pr.IsOpen() {
+		if pr.Review != "" {
+			parts = append(parts, strings.ReplaceAll(pr.Review, "_", " "))
+		}
+
+		if pr.Checks != "" {
+			parts = append(parts, "checks "+pr.Checks)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/credentials"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/gitea"
+)
+
+// giteaCredentials lists the settings and credentials of the Gitea integration, which also serves Forgejo
+var giteaCredentials = credentials.Integration{
+	Name: "gitea",
+	Fields: []credentials.Field{
+		{Name: "base_url", Kind: credentials.KindSetting, EnvVars: []string{"GITEA_BASE_URL", "FORGEJO_BASE_URL"}},
+		{Name: "token", Kind: credentials.KindPassword, EnvVars: []string{"GITEA_TOKEN", "FORGEJO_TOKEN"}},
+	},
+	Host: func(settings map[string]string) string {
+		return urlHostname(settings["base_url"])
+	},
+}
+
+func init() {
+	factory := func(repo integrations.Repository) integrations.Forge {
+		resolved := credentialChain().Resolve(giteaCredentials)
+		for _, warning := range resolved.Warnings {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", warning)
+		}
+
+		// the client's configuration is checked by the forge's Validate method
+		client, _ := newGiteaClient(resolved)
+
+		return gitea.NewForge(client, repo)
+	}
+
+	// there is no public instance to recognize, only the configured one
+	integrations.RegisterForge("gitea", func(host string) bool {
+		configured := credentialChain().ResolveHost(giteaCredentials)
+		return configured != "" && host == configured
+	}, factory)
+
+	// "forgejo" can be set as forge.provider too, but remotes are only detected as "gitea"
+	integrations.RegisterForge("forgejo", func(host string) bool {
+		return false
+	}, factory)
+}
+
+// newGiteaClient returns a client using the resolved credentials and the config file, or the reason it can't be used.
+func newGiteaClient(resolved *credentials.Resolved) (*gitea.Client, error) {
+	client := gitea.NewClient(resolved.Get("base_url"), resolved.Get("token"))
+
+	configureCache(client.Cache)
+
+	return client, client.Validate()
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/credentials"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/github"
+)
+
+// githubCredentials lists the settings and credentials of the GitHub integration
+var githubCredentials = credentials.Integration{
+	Name: "github",
+	Fields: []credentials.Field{
+		{Name: "base_url", Kind: credentials.KindSetting, EnvVars: []string{"GITHUB_BASE_URL"}},
+		{Name: "token", Kind: credentials.KindPassword, EnvVars: []string{"GITHUB_TOKEN", "GH_TOKEN"}},
+	},
+	Host: func(settings map[string]string) string {
+		return urlHostname(firstNonEmpty(settings["base_url"], "https://github.com"))
+	},
+}
+
+func init() {
+	integrations.RegisterForge("github", func(host string) bool {
+		return github.IsDotCom(host) || host == credentialChain().ResolveHost(githubCredentials)
+	}, func(repo integrations.Repository) integrations.Forge {
+		resolved := credentialChain().Resolve(githubCredentials)
+		for _, warning := range resolved.Warnings {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", warning)
+		}
+
+		// the client's configuration is checked by the forge's Validate method
+		client, _ := newGitHubClient(resolved)
+
+		return github.NewForge(client, repo)
+	})
+}
+
+// newGitHubClient returns a client using the resolved credentials and the config file, or the reason it can't be used.
+func newGitHubClient(resolved *credentials.Resolved) (*github.Client, error) {
+	client := github.NewClient(resolved.Get("base_url"), resolved.Get("token"))
+
+	configureCache(client.Cache)
+
+	return client, client.Validate()
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/credentials"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/gitlab"
+)
+
+// gitlabCredentials lists the settings and credentials of the GitLab integration
+var gitlabCredentials = credentials.Integration{
+	Name: "gitlab",
+	Fields: []credentials.Field{
+		{Name: "base_url", Kind: credentials.KindSetting, EnvVars: []string{"GITLAB_BASE_URL"}},
+		{Name: "token", Kind: credentials.KindPassword, EnvVars: []string{"GITLAB_TOKEN"}},
+	},
+	Host: func(settings map[string]string) string {
+		return urlHostname(firstNonEmpty(settings["base_url"], gitlab.DefaultBaseURL))
+	},
+}
+
+func init() {
+	integrations.RegisterForge("gitlab", func(host string) bool {
+		return gitlab.IsDotCom(host) || host == credentialChain().ResolveHost(gitlabCredentials)
+	}, func(repo integrations.Repository) integrations.Forge {
+		resolved := credentialChain().Resolve(gitlabCredentials)
+		for _, warning := range resolved.Warnings {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", warning)
+		}
+
+		// the client's configuration is checked by the forge's Validate method
+		client, _ := newGitLabClient(resolved)
+
+		return gitlab.NewForge(client, repo)
+	})
+}
+
+// newGitLabClient returns a client using the resolved credentials and the config file, or the reason it can't be used.
+func newGitLabClient(resolved *credentials.Resolved) (*gitlab.Client, error) {
+	client := gitlab.NewClient(resolved.Get("base_url"), resolved.Get("token"))
+
+	configureCache(client.Cache)
+
+	return client, client.Validate()
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/app/utils"
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/jira"
+)
+
+func init() {
+	integrations.Register("jira", func() integrations.IssueTracker {
+		return newJiraTracker("")
+	})
+
+	integrations.Register("linear", func() integrations.IssueTracker {
+		return newLinearTracker()
+	})
+}
+
+// newJiraTracker returns the JIRA issue tracker, whose active issues are those matched by the named query
+// profile, or by the default profile. An invalid profile is reported on stderr and the default is used instead.
+func newJiraTracker(queryName string) *jira.Tracker {
+	resolved := credentialChain().Resolve(jiraCredentials)
+	for _, warning := range resolved.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", warning)
+	}
+
+	// the client's configuration is checked by the tracker's Validate method
+	client, _ := newJiraClient(resolved)
+
+	jql, err := jiraConfig().Query(queryName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		jql, _ = jiraConfig().Query("")
+	}
+
+	return jira.NewTracker(client, jql)
+}
+
+// getIssueTracker returns the issue tracker chosen in the configuration file.
+func getIssueTracker() (integrations.IssueTracker, error) {
+	return integrations.New(config.Get().Issues.Tracker)
+}
+
+// issueRankBoost returns the adjustment, in hours, applied to a branch's score based on the active issue linked
+// to it, if any. Linked branches are boosted by the issue's position in the active issues, and further when the
+// issue is in progress, has a high priority, or was updated recently. Done issues are not boosted.
+func issueRankBoost(branchName string, tracker integrations.IssueTracker, activeIssues []integrations.Issue, now time.Time) float64 {
+	key := tracker.MatchBranch(branchName)
+	if key == "" {
+		return 0
+	}
+
+	for idx, issue := range activeIssues {
+		if issue.Key != key || issue.IsDone() {
+			continue
+		}
+
+		boost := 12 + float64(len(activeIssues)-idx)/float64(len(activeIssues))*12
+
+		if issue.IsInProgress() {
+			boost += 12
+		}
+
+		switch strings.ToLower(issue.Priority) {
+		case "highest", "blocker", "critical", "urgent":
+			boost += 12
+		case "high", "major":
+			boost += 6
+		}
+
+		if !issue.Updated.IsZero() {
+			// halves every three days since the issue was last updated
+			boost += 24 * math.Pow(0.5, now.Sub(issue.Updated).Hours()/72)
+		}
+
+		return boost
+	}
+
+	return 0
+}
+
+// getBranchIssues looks up the issues linked to the branches by their names in the configured issue tracker,
+// keyed by branch name. Lookup failures are reported on stderr and result in fewer or no issues.
+func getBranchIssues(branchNames []string) map[string]*integrations.Issue {
+	result := make(map[string]*integrations.Issue)
+
+	tracker, err := getIssueTracker()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		return result
+	}
+
+	keys := make([]string, 0, len(branchNames))
+	for _, name := range branchNames {
+		if key := tracker.MatchBranch(name); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return result
+	}
+
+	if err := tracker.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %s is not configured: %v\n", tracker.Name(), err)
+		return result
+	}
+
+	issues, err := integrations.LookupIssues(tracker, keys)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to look up issues: %v\n", err)
+	}
+
+	for _, name := range branchNames {
+		if issue := issues[tracker.MatchBranch(name)]; issue != nil {
+			result[name] = issue
+		}
+	}
+
+	return result
+}
+
+// branchNameForIssue returns the name of a new branch for the issue with the given key: the branch name suggested
+// by the tracker, or one built from the key and summary. The key alone is used when the issue can't be fetched.
+func branchNameForIssue(tracker integrations.IssueTracker, key string) string {
+	if err := tracker.Validate(); err != nil {
+		return key
+	}
+
+	issue, err := tracker.GetIssue(key)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to fetch %s: %v\n", key, err)
+		return key
+	}
+
+	if issue.BranchName != "" {
+		return issue.BranchName
+	}
+
+	return issueBranchName(issue.Key, issue.Summary)
+}
+
+// issueStatusColor returns the color used to display an issue's status, based on its status category.
+func issueStatusColor(issue *integrations.Issue) string {
+	switch issue.StatusCategory {
+	case integrations.StatusDone:
+		return "\033[32m"
+	case integrations.StatusInProgress:
+		return "\033[34;1m"
+	default:
+		return "\033[36m"
+	}
+}
+
+// formatBranchLine formats a line of a branch listing: the description followed by the branch name, the status and
+// summary of the issue linked to the branch when issues are shown, and the state of its pull request when pull
+// requests are shown. Branches whose issues are done are dimmed.
+func formatBranchLine(description string, branchName string, issues map[string]*integrations.Issue, prs map[string]*integrations.PullRequest) string {
+	line := fmt.Sprintf("  \033[33m%s \033[37;1m %s\033[0m", description, branchName)
+
+	if issue := issues[branchName]; issue != nil {
+		summary := utils.TruncateString(issue.Summary, 60)
+
+		if issue.IsDone() {
+			line = fmt.Sprintf("  \033[2m%s  %s  [%s] %s\033[0m", description, branchName, issue.Status, summary)
+		} else {
+			line += fmt.Sprintf("  %s[%s]\033[0m %s", issueStatusColor(issue), issue.Status, summary)
+		}
+	}
+
+	if pr := prs[branchName]; pr != nil {
+		line += fmt.Sprintf("  %s%s\033[0m", pullRequestStateColor(pr), describePullRequest(pr))
+	}
+
+	return line
+}
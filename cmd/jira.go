@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/credentials"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/jira"
+)
+
+// firstNonEmpty returns the first of its arguments that is not an empty string.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// jiraConfig returns the jira section of the config file, with the "mine" query profile defaulting to the
+// jira package's JQL.
+func jiraConfig() config.JiraConfig {
+	cfg := config.Get().Jira
+
+	queries := map[string]string{"mine": jira.DefaultJQL}
+	for name, jql := range cfg.Queries {
+		queries[name] = jql
+	}
+	cfg.Queries = queries
+
+	return cfg
+}
+
+// jiraCredentials lists the settings and credentials of the JIRA integration
+var jiraCredentials = credentials.Integration{
+	Name: "jira",
+	Fields: []credentials.Field{
+		{Name: "base_url", Kind: credentials.KindSetting, EnvVars: []string{"JIRA_BASE_URL"}},
+		{Name: "subdomain", Kind: credentials.KindSetting, EnvVars: []string{"JIRA_SUBDOMAIN"}},
+		{Name: "email", Kind: credentials.KindUsername, EnvVars: []string{"JIRA_EMAIL_ADDRESS"}},
+		{Name: "token", Kind: credentials.KindPassword, EnvVars: []string{"JIRA_API_TOKEN"}},
+	},
+	Host: func(settings map[string]string) string {
+		parsed, err := url.Parse(jiraBaseURL(settings["base_url"], settings["subdomain"]))
+		if err != nil {
+			return ""
+		}
+
+		return parsed.Hostname()
+	},
+}
+
+// jiraBaseURL returns the base URL of the JIRA instance, or that of the Jira Cloud site named by the subdomain.
+func jiraBaseURL(baseURL string, subdomain string) string {
+	if baseURL == "" && subdomain != "" {
+		baseURL = jira.CloudURL(subdomain)
+	}
+
+	return baseURL
+}
+
+// getJiraClient returns a client for the JIRA instance configured by the credential sources and the config file.
+// See credentialChain for the sources and their precedence.
+func getJiraClient() (*jira.Client, error) {
+	resolved := credentialChain().Resolve(jiraCredentials)
+	for _, warning := range resolved.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", warning)
+	}
+
+	client, err := newJiraClient(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("JIRA is not configured: %v (run auth:status to see which credentials were found)", err)
+	}
+
+	return client, nil
+}
+
+// newJiraClient returns a client using the resolved credentials and the config file, or the reason it can't be used.
+func newJiraClient(resolved *credentials.Resolved) (*jira.Client, error) {
+	cfg := config.Get().Jira
+
+	baseURL := jiraBaseURL(resolved.Get("base_url"), resolved.Get("subdomain"))
+	client := jira.NewClient(baseURL, resolved.Get("email"), resolved.Get("token"))
+
+	if auth := firstNonEmpty(os.Getenv("JIRA_AUTH"), cfg.Auth); auth != "" {
+		client.Auth = jira.AuthMethod(auth)
+	}
+
+	if version := firstNonEmpty(os.Getenv("JIRA_API_VERSION"), cfg.APIVersion); version != "" {
+		client.APIVersion = version
+	}
+
+	configureCache(client.Cache)
+
+	return client, client.Validate()
+}
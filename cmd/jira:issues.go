@@ -6,36 +6,286 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/permafrost-dev/git-ninja/app/git"
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/output"
+	"github.com/permafrost-dev/git-ninja/app/utils"
 	"github.com/permafrost-dev/git-ninja/lib/integrations/jira"
 	"github.com/spf13/cobra"
 )
 
-var jiraissuesCmd = &cobra.Command{
-	Use:    "jira:issues",
-	Short:  "List open JIRA Ticket IDs",
-	Hidden: true,
-	Run: func(cmd *cobra.Command, args []string) {
-		if len(os.Getenv("JIRA_SUBDOMAIN")) == 0 || len(os.Getenv("JIRA_EMAIL_ADDRESS")) == 0 || len(os.Getenv("JIRA_API_TOKEN")) == 0 {
-			fmt.Println("Error: JIRA_SUBDOMAIN, JIRA_EMAIL_ADDRESS and JIRA_API_TOKEN environment variables must be set.")
-			return
+// issueColumn is a column of the jira:issues table
+type issueColumn struct {
+	header string
+	value  func(issue jira.Issue) string
+}
+
+// issueColumns lists the columns that can be selected with --columns
+var issueColumns = map[string]issueColumn{
+	"key":      {"KEY", func(issue jira.Issue) string { return issue.Key }},
+	"status":   {"STATUS", func(issue jira.Issue) string { return issue.Status }},
+	"priority": {"PRIORITY", func(issue jira.Issue) string { return issue.Priority }},
+	"assignee": {"ASSIGNEE", func(issue jira.Issue) string { return issue.Assignee }},
+	"summary":  {"SUMMARY", func(issue jira.Issue) string { return issue.Summary }},
+	"updated": {"UPDATED", func(issue jira.Issue) string {
+		if issue.Updated.IsZero() {
+			return ""
 		}
+		return issue.Updated.Local().Format("2006-01-02 15:04")
+	}},
+}
 
-		ids := jira.GetJiraTicketIDs(os.Getenv("JIRA_SUBDOMAIN"), os.Getenv("JIRA_EMAIL_ADDRESS"))
+const defaultIssueColumns = "key,status,priority,updated,summary"
 
-		if len(ids) > 0 {
-			fmt.Println("Open JIRA Tickets:")
-			for _, id := range ids {
-				fmt.Println(id)
-			}
+// issueBranchNameLength limits the length of the branch names suggested for issues
+const issueBranchNameLength = 60
+
+// parseIssueColumns validates a comma-separated list of column names.
+func parseIssueColumns(names string) ([]issueColumn, error) {
+	result := make([]issueColumn, 0)
+
+	for _, name := range splitList(names) {
+		column, ok := issueColumns[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown column '%s', expected some of: %s", name, "key, status, priority, assignee, updated, summary")
+		}
+
+		result = append(result, column)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no columns selected")
+	}
+
+	return result, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(value string) []string {
+	result := make([]string, 0)
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// printIssues writes the issues in the requested format. JSON output includes every field.
+func printIssues(issues []jira.Issue, columns []issueColumn, format output.Format) error {
+	if format == output.FormatJSON {
+		return output.WriteJSON(os.Stdout, issues)
+	}
+
+	table := &output.Table{}
+	for _, column := range columns {
+		table.Headers = append(table.Headers, column.header)
+	}
+
+	for _, issue := range issues {
+		row := make([]string, 0, len(columns))
+		for _, column := range columns {
+			row = append(row, column.value(issue))
+		}
+		table.AddRow(row...)
+	}
+
+	if format == output.FormatCSV {
+		return table.WriteCSV(os.Stdout)
+	}
+
+	return table.WriteText(os.Stdout)
+}
+
+// issuesWithoutBranch returns the issues that are not done and have no local or remote branch named after them.
+func issuesWithoutBranch(issues []jira.Issue) ([]jira.Issue, error) {
+	refs, err := git.FindRefIssueReferences("", jira.ExtractIssueKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	hasBranch := make(map[string]bool)
+	for _, ref := range refs {
+		hasBranch[ref.Key] = true
+	}
+
+	result := make([]jira.Issue, 0)
+	for _, issue := range issues {
+		if !issue.IsDone() && !hasBranch[issue.Key] {
+			result = append(result, issue)
+		}
+	}
+
+	return result, nil
+}
+
+// issueBranchName suggests a branch name for an issue, e.g. "ABC-123-fix-the-login-page".
+func issueBranchName(key string, summary string) string {
+	slug := utils.Slugify(summary, issueBranchNameLength-len(key)-1)
+	if slug == "" {
+		return key
+	}
+
+	return key + "-" + slug
+}
+
+// offerIssueBranches asks, for each issue, whether to create a branch for it, and creates the branches
+// from startPoint without checking them out. The start transition is applied on their first checkout.
+func offerIssueBranches(issues []jira.Issue, startPoint string) {
+	created := make([]string, 0)
+
+	fmt.Println()
+
+	for _, issue := range issues {
+		answer := strings.ToLower(ask(fmt.Sprintf("Create a branch for %s (%s)? [y/N/q]", issue.Key, utils.TruncateString(issue.Summary, 50)), ""))
+		if answer == "q" || answer == "quit" {
+			break
+		}
+		if answer != "y" && answer != "yes" {
+			continue
+		}
+
+		name := ask("  Branch name:", issueBranchName(issue.Key, issue.Summary))
+		if exists, _ := helpers.BranchExists(name); exists {
+			fmt.Printf("  error: branch '%s' already exists\n", name)
+			continue
 		}
 
-		if len(ids) == 0 {
-			fmt.Println("No open JIRA tickets.")
+		if err := helpers.RunCommandOnStdout("git", "branch", name, startPoint); err != nil {
+			fmt.Printf("  error: failed to create branch '%s'\n", name)
+			continue
 		}
-	},
+
+		fmt.Printf("  Created \033[37;1m%s\033[0m from %s\n", name, startPoint)
+		created = append(created, name)
+	}
+
+	if len(created) > 0 {
+		fmt.Printf("\nCheck out a new branch with: git-ninja checkout %s\n", created[0])
+	}
 }
 
 func init() {
-	rootCmd.AddCommand(jiraissuesCmd)
+	flagQuery := ""
+	flagStatus := ""
+	flagProject := ""
+	flagPriority := ""
+	flagColumns := defaultIssueColumns
+	flagFormat := string(output.FormatTable)
+	flagWithoutBranch := false
+	flagNoPrompt := false
+	flagBase := ""
+
+	cmd := &cobra.Command{
+		Use:   "jira:issues",
+		Short: "List JIRA issues",
+		Long: `List the JIRA issues matched by a query profile, optionally filtered by status category, project and priority.
+The filters are added to the profile's JQL, and --status replaces the status categories the profile selects.
+
+With --without-branch, only open issues that have no local or remote branch are listed, and a branch can be
+created for each of them.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			format, err := output.ParseFormat(flagFormat)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			columns, err := parseIssueColumns(flagColumns)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			filter := jira.IssueFilter{Projects: splitList(flagProject), Priorities: splitList(flagPriority)}
+			for _, name := range splitList(flagStatus) {
+				category, err := jira.ParseStatusCategory(name)
+				if err != nil {
+					fmt.Printf("Error: %v\n", err)
+					return
+				}
+				filter.StatusCategories = append(filter.StatusCategories, category)
+			}
+
+			jql, err := jiraConfig().Query(flagQuery)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			client, err := getJiraClient()
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			result, meta, err := client.IssuesWithMetadata(filter.JQL(jql))
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			if result.Truncated {
+				fmt.Fprintf(os.Stderr, "warning: %v; narrow the query or the filters to list the rest\n", jira.ErrSearchTruncated)
+			}
+
+			issues := result.Issues
+
+			if flagWithoutBranch {
+				if issues, err = issuesWithoutBranch(issues); err != nil {
+					fmt.Printf("Error: %v\n", err)
+					return
+				}
+			}
+
+			if len(issues) == 0 && format == output.FormatTable {
+				if flagWithoutBranch {
+					fmt.Println("Every open issue has a branch.")
+				} else {
+					fmt.Println("No matching JIRA issues.")
+				}
+			} else if err := printIssues(issues, columns, format); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			if format != output.FormatTable {
+				return
+			}
+
+			fmt.Printf("\033[2m(%s)\033[0m\n", meta.Describe(time.Now()))
+
+			if !flagWithoutBranch || flagNoPrompt || len(issues) == 0 {
+				return
+			}
+
+			startPoint := flagBase
+			if startPoint == "" {
+				if startPoint, err = helpers.GetDefaultBranchName("origin"); err != nil {
+					fmt.Printf("Error: %v; use --base to choose the start point of new branches\n", err)
+					return
+				}
+			}
+
+			offerIssueBranches(issues, startPoint)
+		},
+	}
+
+	cmd.Flags().StringVar(&flagQuery, "jira-query", "", "Name of the JIRA query profile to list issues for")
+	cmd.Flags().StringVarP(&flagStatus, "status", "s", "", "Only list issues in these status categories: todo, in-progress, done (comma-separated)")
+	cmd.Flags().StringVarP(&flagProject, "project", "p", "", "Only list issues in these projects (comma-separated)")
+	cmd.Flags().StringVar(&flagPriority, "priority", "", "Only list issues with these priorities (comma-separated)")
+	cmd.Flags().StringVarP(&flagColumns, "columns", "c", defaultIssueColumns, "Columns to show: key, status, priority, assignee, updated, summary")
+	cmd.Flags().StringVarP(&flagFormat, "format", "f", string(output.FormatTable), "Output format: "+strings.Join(output.Formats, ", "))
+	cmd.Flags().BoolVar(&flagWithoutBranch, "without-branch", false, "Only list open issues without a local or remote branch, and offer to create branches")
+	cmd.Flags().BoolVar(&flagNoPrompt, "no-prompt", false, "Don't offer to create branches for issues without one")
+	cmd.Flags().StringVarP(&flagBase, "base", "b", "", "Start point of the branches created for issues (default: the default branch)")
+
+	rootCmd.AddCommand(cmd)
 }
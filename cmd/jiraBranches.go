@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/permafrost-dev/git-ninja/app/git"
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/output"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/jira"
+	"github.com/spf13/cobra"
+)
+
+// shortCommitHash returns the abbreviated form of a commit hash.
+func shortCommitHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+
+	return hash
+}
+
+// printIssueReferences writes the references in the requested format.
+func printIssueReferences(refs []git.IssueReference, format output.Format) error {
+	if format == output.FormatJSON {
+		return output.WriteJSON(os.Stdout, refs)
+	}
+
+	table := &output.Table{Headers: []string{"ISSUE", "SOURCE", "BRANCH", "COMMIT", "DATE", "MESSAGE"}}
+	for _, ref := range refs {
+		date := ""
+		if !ref.Timestamp.IsZero() {
+			date = ref.Timestamp.Format("2006-01-02")
+		}
+
+		table.AddRow(ref.Key, ref.Source, ref.Branch, shortCommitHash(ref.Commit), date, ref.Message)
+	}
+
+	if format == output.FormatCSV {
+		return table.WriteCSV(os.Stdout)
+	}
+
+	return table.WriteText(os.Stdout)
+}
+
+func init() {
+	flagBranchesFormat := string(output.FormatTable)
+	flagIssueFormat := string(output.FormatTable)
+	flagIssueBase := ""
+
+	jiraBranchesCmd := &cobra.Command{
+		Use:   "jira:branches <ISSUE-KEY>",
+		Short: "Find the branches and commits that reference an issue",
+		Long: `Find the local and remote branches named after an issue, deleted branches found in the checkout
+history, and commits whose messages mention the issue.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			format, err := output.ParseFormat(flagBranchesFormat)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			key := args[0]
+			if jira.ExtractIssueKey(key) != key {
+				fmt.Fprintf(os.Stderr, "warning: '%s' does not match the issue key pattern %s\n", key, jira.IssueKeyPattern)
+			}
+
+			refs, err := git.FindIssueReferences("", key, jira.ExtractIssueKeys)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			if len(refs) == 0 && format == output.FormatTable {
+				fmt.Printf("No branches or commits reference %s.\n", key)
+				return
+			}
+
+			if err := printIssueReferences(refs, format); err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+		},
+	}
+
+	jiraIssueCmd := &cobra.Command{
+		Use:   "jira:issue [branch]",
+		Short: "Show the issues referenced by a branch",
+		Long: `Show the issues referenced by a branch's name and by the messages of its commits that are not on
+the default branch. Defaults to the current branch. When JIRA is configured, the status and summary of each
+issue are shown.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			format, err := output.ParseFormat(flagIssueFormat)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			branchName, _ := helpers.GetCurrentBranchName()
+			if len(args) > 0 {
+				branchName = args[0]
+			}
+
+			base := flagIssueBase
+			if base == "" {
+				base, _ = helpers.GetDefaultBranchName("origin")
+			}
+
+			refs, err := git.FindBranchIssueReferences("", base, branchName, jira.ExtractIssueKeys)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			if format != output.FormatTable {
+				if err := printIssueReferences(refs, format); err != nil {
+					fmt.Printf("Error: %v\n", err)
+				}
+				return
+			}
+
+			if len(refs) == 0 {
+				fmt.Printf("No issues are referenced by %s.\n", branchName)
+				return
+			}
+
+			keys := make([]string, 0)
+			commitCounts := make(map[string]int)
+			for _, ref := range refs {
+				if _, ok := commitCounts[ref.Key]; !ok {
+					keys = append(keys, ref.Key)
+					commitCounts[ref.Key] = 0
+				}
+				if ref.Source == git.IssueSourceCommit {
+					commitCounts[ref.Key]++
+				}
+			}
+
+			issues := getBranchIssues(keys)
+
+			for _, key := range keys {
+				commits := fmt.Sprintf("\033[2m%d commits\033[0m", commitCounts[key])
+				if commitCounts[key] == 1 {
+					commits = "\033[2m1 commit\033[0m"
+				}
+
+				if issue := issues[key]; issue != nil {
+					fmt.Printf("  \033[37;1m%-12s\033[0m %s[%s]\033[0m %s  %s\n", key, issueStatusColor(issue), issue.Status, issue.Summary, commits)
+				} else {
+					fmt.Printf("  \033[37;1m%-12s\033[0m %s\n", key, commits)
+				}
+			}
+		},
+	}
+
+	jiraBranchesCmd.Flags().StringVarP(&flagBranchesFormat, "format", "f", string(output.FormatTable), "Output format: "+strings.Join(output.Formats, ", "))
+	jiraIssueCmd.Flags().StringVarP(&flagIssueFormat, "format", "f", string(output.FormatTable), "Output format: "+strings.Join(output.Formats, ", "))
+	jiraIssueCmd.Flags().StringVarP(&flagIssueBase, "base", "b", "", "Branch whose commits are excluded (default: the remote's default branch, main or master)")
+
+	rootCmd.AddCommand(jiraBranchesCmd, jiraIssueCmd)
+}
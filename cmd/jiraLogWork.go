@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/app/git"
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/output"
+	"github.com/permafrost-dev/git-ninja/app/report"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/jira"
+	"github.com/spf13/cobra"
+)
+
+// formatWorkDuration formats a duration as hours and minutes, e.g. "1h 05m".
+func formatWorkDuration(d time.Duration) string {
+	minutes := int(d.Truncate(time.Minute).Minutes())
+
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
+// printWorklogDrafts writes the drafts as a table, with the total time to be logged.
+func printWorklogDrafts(drafts []report.WorklogDraft) error {
+	table := &output.Table{Headers: []string{"DATE", "ISSUE", "STARTED", "TIME", "BRANCHES"}}
+
+	var total time.Duration
+	for _, draft := range drafts {
+		table.AddRow(
+			draft.Started.Format(time.DateOnly),
+			draft.Issue,
+			draft.Started.Format("15:04"),
+			formatWorkDuration(draft.Duration()),
+			strings.Join(draft.Branches, ", "),
+		)
+		total += draft.Duration().Truncate(time.Minute)
+	}
+
+	if err := table.WriteText(os.Stdout); err != nil {
+		return err
+	}
+
+	worklogs := "worklogs"
+	if len(drafts) == 1 {
+		worklogs = "worklog"
+	}
+
+	fmt.Printf("\nTotal: %s in %d %s\n", formatWorkDuration(total), len(drafts), worklogs)
+
+	return nil
+}
+
+func init() {
+	flagPeriod := "day"
+	flagFrom := ""
+	flagTo := ""
+	flagIdle := ""
+	flagComment := ""
+	flagYes := false
+	flagDryRun := false
+
+	cmd := &cobra.Command{
+		Use:   "jira:log-work [--period day|week] [--from YYYY-MM-DD [--to YYYY-MM-DD]]",
+		Short: "Log the time spent on issue branches as JIRA worklogs",
+		Long: `Log the time spent on branches linked to JIRA issues as worklogs. The time is reconstructed from the
+checkouts and commits recorded in the reflog, as in report:time, and one worklog is drafted per issue and day.
+The drafts are shown for review and posted after confirmation.
+
+Logged sessions are recorded, so running the command again only logs time that has not been logged yet.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+
+			idleLimit := config.Get().Reports.IdleLimitDuration()
+			if flagIdle != "" {
+				if idleLimit, err = time.ParseDuration(flagIdle); err != nil || idleLimit <= 0 {
+					fmt.Printf("Error: invalid idle limit '%s'\n", flagIdle)
+					return
+				}
+			}
+
+			now := time.Now()
+
+			from, to, err := report.ParseRange(flagPeriod, flagFrom, flagTo, now)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			root, err := helpers.GetRepositoryRoot()
+			if err != nil {
+				fmt.Printf("Error: not a git repository: %v\n", err)
+				return
+			}
+
+			ledger, err := report.OpenWorklogLedger(root)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			items, err := git.GetHeadRefLogItems()
+			if err != nil {
+				fmt.Printf("Error: failed to read reflog: %v\n", err)
+				return
+			}
+
+			sessions := report.BuildSessions(items, idleLimit, now)
+			drafts := report.BuildWorklogDrafts(sessions, from, to, ledger, jira.ExtractIssueKey)
+
+			if len(drafts) == 0 {
+				fmt.Println("No unlogged time on issue branches.")
+				return
+			}
+
+			if err := printWorklogDrafts(drafts); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			if flagDryRun {
+				return
+			}
+
+			client, err := getJiraClient()
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			fmt.Println()
+			if !flagYes && !confirm("Post these worklogs to JIRA?") {
+				fmt.Println("Nothing was logged.")
+				return
+			}
+
+			logged := 0
+
+			for _, draft := range drafts {
+				comment := flagComment
+				if comment == "" {
+					comment = "Work on " + strings.Join(draft.Branches, ", ")
+				}
+
+				id, err := client.AddWorklog(draft.Issue, draft.Started, draft.Duration(), comment)
+				if err != nil {
+					fmt.Printf("Error: failed to log %s on %s: %v\n", formatWorkDuration(draft.Duration()), draft.Issue, err)
+					continue
+				}
+
+				// record each worklog as soon as it is posted, so that a later failure can't cause it to be logged again
+				ledger.Record(draft, id, time.Now())
+				if err := ledger.Save(); err != nil {
+					fmt.Printf("Error: %s was logged, but recording it failed: %v\n", draft.Issue, err)
+					return
+				}
+
+				logged++
+				fmt.Printf("Logged %s on \033[37;1m%s\033[0m\n", formatWorkDuration(draft.Duration()), draft.Issue)
+			}
+
+			fmt.Printf("%d of %d worklogs posted.\n", logged, len(drafts))
+		},
+	}
+
+	cmd.Flags().StringVarP(&flagPeriod, "period", "p", "day", "Period to log: day or week")
+	cmd.Flags().StringVar(&flagFrom, "from", "", "Start date of a custom range (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&flagTo, "to", "", "End date of a custom range, inclusive (YYYY-MM-DD)")
+	cmd.Flags().StringVarP(&flagIdle, "idle", "i", "", "Idle limit, e.g. 30m (default from config, or 1h)")
+	cmd.Flags().StringVarP(&flagComment, "comment", "m", "", "Worklog comment (default: the names of the branches)")
+	cmd.Flags().BoolVarP(&flagYes, "yes", "y", false, "Post the worklogs without asking for confirmation")
+	cmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "Show the drafts without posting them")
+
+	rootCmd.AddCommand(cmd)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/credentials"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/linear"
+)
+
+// linearCredentials lists the settings and credentials of the Linear integration
+var linearCredentials = credentials.Integration{
+	Name: "linear",
+	Fields: []credentials.Field{
+		{Name: "api_url", Kind: credentials.KindSetting, EnvVars: []string{"LINEAR_API_URL"}},
+		{Name: "api_key", Kind: credentials.KindPassword, EnvVars: []string{"LINEAR_API_KEY"}},
+	},
+	Host: func(settings map[string]string) string {
+		parsed, err := url.Parse(firstNonEmpty(settings["api_url"], linear.DefaultAPIURL))
+		if err != nil {
+			return ""
+		}
+
+		return parsed.Hostname()
+	},
+}
+
+// newLinearClient returns a client using the resolved credentials and the config file, or the reason it can't be used.
+func newLinearClient(resolved *credentials.Resolved) (*linear.Client, error) {
+	client := linear.NewClient(resolved.Get("api_url"), resolved.Get("api_key"))
+
+	configureCache(client.Cache)
+
+	return client, client.Validate()
+}
+
+// newLinearTracker returns the Linear issue tracker configured by the credential sources and the config file.
+func newLinearTracker() *linear.Tracker {
+	resolved := credentialChain().Resolve(linearCredentials)
+	for _, warning := range resolved.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", warning)
+	}
+
+	// the client's configuration is checked by the tracker's Validate method
+	client, _ := newLinearClient(resolved)
+
+	cfg := config.Get().Linear
+
+	return linear.NewTracker(client, cfg.Teams, cfg.CurrentCycle)
+}
@@ -3,22 +3,63 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"regexp"
-	"sort"
 	"strings"
+	"time"
 
 	"github.com/permafrost-dev/git-ninja/app/git"
 	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/ranking"
 	"github.com/permafrost-dev/git-ninja/app/utils"
-	"github.com/permafrost-dev/git-ninja/lib/integrations/jira"
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
 	"github.com/spf13/cobra"
 )
 
 var flagCount int = 10
+var flagRecentIssues bool = false
 var flagJira bool = false
 var flagFilterIgnore string = ""
-var lineRegex = regexp.MustCompile(`([0-9]+) ~ (checkout):.+ ([^~]+) ~ HEAD@{(.*)}`)
-var refLogCheckoutsFmt = "%at ~ %gs ~ %gd"
+var flagRecentSort string = ""
+var flagRecentExplain bool = false
+var flagJiraQuery string = ""
+var flagRecentWithIssues bool = false
+var flagRecentWithPRs bool = false
+
+// getRecentBranchesRanked returns the branches ranked for branch:recent, excluding the current branch and
+// branches matched by the exclude flag. When activeIssues is not empty, branches the tracker links to those
+// issues are boosted.
+func getRecentBranchesRanked(existingBranches map[string]bool, currentBranch string, sortBy string, tracker integrations.IssueTracker, activeIssues []integrations.Issue) ([]ranking.Ranked, error) {
+	ranker, err := getRanker(sortBy, "recency")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(activeIssues) > 0 {
+		ranker = ranking.WithBoost(ranker, "issues", func(b *git.BranchInfo) float64 {
+			return issueRankBoost(b.Name, tracker, activeIssues, time.Now())
+		})
+	}
+
+	branches := getBranchUsage(existingBranches)
+
+	for name := range branches {
+		// exclude branches matched by the exclude flag, and don't show the current branch
+		if utils.StringMatchesRegexPattern(flagFilterIgnore, name) || strings.EqualFold(name, currentBranch) {
+			delete(branches, name)
+		}
+	}
+
+	return ranking.Rank(branchInfoMapToSlice(branches), ranker, time.Now()), nil
+}
+
+// rankedBranchNames returns the names of the ranked branches.
+func rankedBranchNames(ranked []ranking.Ranked) []string {
+	result := make([]string, 0, len(ranked))
+	for _, r := range ranked {
+		result = append(result, r.Branch.Name)
+	}
+
+	return result
+}
 
 var listRecentBranchesCmd = &cobra.Command{
 	Use:   "branch:recent [--count|-c <count>]",
@@ -26,75 +67,63 @@ var listRecentBranchesCmd = &cobra.Command{
 	Run: func(c *cobra.Command, args []string) {
 		existingBranches, _ := helpers.GetAvailableBranchesMap()
 		currentBranch, _ := helpers.GetCurrentBranchName()
-		lines, _ := git.GetGitReflogLines(refLogCheckoutsFmt)
-		seen := make(map[string]*git.BranchCheckoutInfo)
 		count := 0
 
-		jiraIssues := make([]string, 0)
+		var tracker integrations.IssueTracker
+		var activeIssues []integrations.Issue
 
-		if flagJira {
-			if len(os.Getenv("JIRA_SUBDOMAIN")) == 0 || len(os.Getenv("JIRA_EMAIL_ADDRESS")) == 0 || len(os.Getenv("JIRA_API_TOKEN")) == 0 {
-				fmt.Println("Error: JIRA_SUBDOMAIN, JIRA_EMAIL_ADDRESS and JIRA_API_TOKEN environment variables must be set.")
-				return
-			}
+		if flagRecentIssues || flagJira || flagJiraQuery != "" {
+			var err error
 
-			jiraIssues = jira.GetJiraTicketIDs(os.Getenv("JIRA_SUBDOMAIN"), os.Getenv("JIRA_EMAIL_ADDRESS"))
-		}
-
-		sorted := make([]git.BranchInfo, 0)
-
-		for _, line := range lines {
-			info := git.GetBranchInfoFromReflogLine(lineRegex, line, 4)
-			if info == nil {
-				continue
-			}
-
-			// exclude branches that are not in the list of current branches, i.e. branches that have been deleted
-			if !utils.MapEntryExists(info.BranchName, existingBranches) {
-				continue
-			}
+			if flagJiraQuery != "" {
+				if _, err = jiraConfig().Query(flagJiraQuery); err != nil {
+					fmt.Printf("Error: %v\n", err)
+					return
+				}
 
-			// is the branch name excluded by the exclude flag?
-			if utils.StringMatchesRegexPattern(flagFilterIgnore, info.BranchName) {
-				continue
+				tracker = newJiraTracker(flagJiraQuery)
+			} else if tracker, err = getIssueTracker(); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
 			}
 
-			// don't show the current branch
-			if strings.EqualFold(info.BranchName, currentBranch) {
-				continue
+			if err := tracker.Validate(); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
 			}
 
-			// have we already seen this branch?
-			if seen[info.BranchName] != nil {
-				continue
+			if activeIssues, err = tracker.ActiveIssues(); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to retrieve %s issues: %v\n", tracker.Name(), err)
 			}
+		}
 
-			var rank int64 = info.Timestamp.Unix() - 1610000000
-
-			if flagJira {
-				for idx, issue := range jiraIssues {
-					jiraHash, _ := jira.HashJiraIssueKey(info.BranchName)
+		sorted, err := getRecentBranchesRanked(existingBranches, currentBranch, flagRecentSort, tracker, activeIssues)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
 
-					if strings.Contains(info.BranchName, issue) {
-						rank += int64(1000*(len(jiraIssues)-idx)) + (jiraHash * -50)
-					} else {
-						rank -= ((1000 + jiraHash) + int64(100*(len(jiraIssues)-idx))) * 4
-					}
-				}
-			}
+		if len(sorted) > flagCount {
+			sorted = sorted[:flagCount]
+		}
 
-			rank = rank / 100
+		issues := map[string]*integrations.Issue{}
+		if flagRecentWithIssues {
+			issues = getBranchIssues(rankedBranchNames(sorted))
+		}
 
-			seen[info.BranchName] = info
-			sorted = append(sorted, git.BranchInfo{Rank: rank, Name: info.BranchName, CheckedOutLast: info.Timestamp, CheckoutHistory: []*git.BranchCheckoutInfo{info}})
+		prs := map[string]*integrations.PullRequest{}
+		if flagRecentWithPRs {
+			prs = getBranchPullRequests(rankedBranchNames(sorted))
 		}
 
-		sort.Slice(sorted, func(i, j int) bool {
-			return sorted[i].Rank >= sorted[j].Rank
-		})
+		for _, ranked := range sorted {
+			bi := ranked.Branch
+			fmt.Println(formatBranchLine(fmt.Sprintf("%-15s", utils.GetRelativeTime(bi.CheckedOutLast)), bi.Name, issues, prs))
 
-		for _, bi := range sorted {
-			fmt.Printf("  \033[33m%-15s %-5d \033[37;1m %s\033[0m\n", utils.GetRelativeTime(bi.CheckedOutLast), bi.Rank, bi.Name)
+			if flagRecentExplain {
+				printScoreExplanation(ranked.Score)
+			}
 
 			if count += 1; count >= flagCount {
 				break
@@ -114,5 +143,11 @@ func init() {
 
 	listRecentBranchesCmd.Flags().IntVarP(&flagCount, "count", "c", 10, "Limit the number of branches to display")
 	listRecentBranchesCmd.Flags().StringVarP(&flagFilterIgnore, "exclude", "e", "", "Exclude branches that match the provided regex")
+	listRecentBranchesCmd.Flags().BoolVarP(&flagRecentIssues, "issues", "I", false, "Use your active issues in the configured issue tracker to help rank branches")
 	listRecentBranchesCmd.Flags().BoolVarP(&flagJira, "jira", "J", false, "Use JIRA issues to help rank branches")
+	listRecentBranchesCmd.Flags().MarkDeprecated("jira", "use --issues instead")
+	listRecentBranchesCmd.Flags().BoolVar(&flagRecentWithIssues, "with-issues", false, "Show the status and summary of the issue linked to each branch")
+	listRecentBranchesCmd.Flags().BoolVar(&flagRecentWithPRs, "with-prs", false, "Show the state, review and checks of the pull request of each branch")
+	listRecentBranchesCmd.Flags().StringVar(&flagJiraQuery, "jira-query", "", "Name of the JIRA query profile used to rank branches (implies --issues with the JIRA tracker)")
+	addRankingFlags(listRecentBranchesCmd, &flagRecentSort, &flagRecentExplain)
 }
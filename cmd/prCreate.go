@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/usage"
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+	"github.com/spf13/cobra"
+)
+
+// pullRequestTemplateFiles lists the files, relative to the repository root, whose first existing one is used as
+// the template of pull request bodies
+var pullRequestTemplateFiles = []string{
+	".github/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	"docs/pull_request_template.md",
+	"pull_request_template.md",
+	".gitlab/merge_request_templates/Default.md",
+}
+
+// pullRequestOptions controls how createPullRequest opens a pull request
+type pullRequestOptions struct {
+	// Base is the branch the pull request merges into; the default branch is used when it is empty
+	Base  string
+	Draft bool
+	// DryRun prints the pull request instead of pushing the branch and creating it
+	DryRun       bool
+	NoTransition bool
+}
+
+// pullRequestTemplateData is the data available to pull request templates, e.g. {{.IssueKey}}
+type pullRequestTemplateData struct {
+	Title    string
+	Branch   string
+	Base     string
+	IssueKey string
+	IssueURL string
+	// Commits is a Markdown list of the subjects of the branch's commits
+	Commits string
+}
+
+// readPullRequestTemplate returns the contents of the repository's pull request template, or an empty string
+// when it has none.
+func readPullRequestTemplate() string {
+	root, err := helpers.GetRepositoryRoot()
+	if err != nil {
+		return ""
+	}
+
+	for _, name := range pullRequestTemplateFiles {
+		if data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name))); err == nil {
+			return string(data)
+		}
+	}
+
+	return ""
+}
+
+// pullRequestBody builds the body of a pull request from the repository's template, when it has one, or from a
+// link to the issue otherwise. Templates may use the fields of pullRequestTemplateData; the list of commits is
+// appended to those that don't include it.
+func pullRequestBody(data pullRequestTemplateData) string {
+	source := readPullRequestTemplate()
+	text := source
+
+	if source == "" {
+		if data.IssueKey == "" {
+			return data.Commits
+		}
+
+		issue := data.IssueKey
+		if data.IssueURL != "" {
+			issue = fmt.Sprintf("[%s](%s)", data.IssueKey, data.IssueURL)
+		}
+
+		return fmt.Sprintf("Issue: %s\n\n%s", issue, data.Commits)
+	}
+
+	if strings.Contains(source, "{{") {
+		var rendered bytes.Buffer
+
+		tmpl, err := template.New("pull_request_template").Parse(text)
+		if err == nil {
+			err = tmpl.Execute(&rendered, data)
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to render the pull request template, using it as is: %v\n", err)
+		} else {
+			text = rendered.String()
+		}
+	}
+
+	if strings.Contains(source, ".Commits") {
+		return strings.TrimSpace(text)
+	}
+
+	return strings.TrimSpace(text) + "\n\n## Commits\n\n" + data.Commits
+}
+
+// createPullRequest opens a pull request for a branch on the repository's forge, into the default branch unless
+// another base is given, and prints its URL. The branch is pushed to the forge's remote first when the remote
+// doesn't have all of its commits. The title is that of the linked issue, or the subject of the branch's first
+// commit, and the body lists the branch's commits.
+func createPullRequest(branchName string, options pullRequestOptions) error {
+	remote := firstNonEmpty(config.Get().Forge.Remote, "origin")
+
+	forge, err := getForge()
+	if err != nil {
+		return err
+	}
+
+	if err := forge.Validate(); err != nil {
+		return fmt.Errorf("%s is not configured: %v", forge.Name(), err)
+	}
+
+	base := options.Base
+	if base == "" {
+		if base, err = helpers.GetDefaultBranchName(remote); err != nil {
+			return err
+		}
+	}
+
+	if base == branchName {
+		return fmt.Errorf("cannot open a pull request from '%s' into itself", branchName)
+	}
+
+	// compare with the remote's base branch when it is known, as the local one may be out of date
+	baseRef := base
+	if helpers.RemoteBranchExists(remote, base) {
+		baseRef = remote + "/" + base
+	}
+
+	subjects, err := helpers.GetCommitSubjects(baseRef, branchName)
+	if err != nil {
+		return fmt.Errorf("failed to list the commits of '%s': %v", branchName, err)
+	}
+
+	if len(subjects) == 0 {
+		return fmt.Errorf("'%s' has no commits that '%s' doesn't have", branchName, base)
+	}
+
+	if !options.DryRun {
+		if pr := getBranchPullRequests([]string{branchName})[branchName]; pr != nil && pr.IsOpen() {
+			fmt.Printf("%s already has pull request %s: %s\n", branchName, pr.Ref(), pr.Title)
+			fmt.Println(pr.URL)
+			return nil
+		}
+	}
+
+	data := pullRequestTemplateData{Title: subjects[0], Branch: branchName, Base: base}
+	if issue := getBranchIssues([]string{branchName})[branchName]; issue != nil {
+		data.Title = fmt.Sprintf("%s: %s", issue.Key, issue.Summary)
+		data.IssueKey, data.IssueURL = issue.Key, issue.URL
+	}
+
+	commits := make([]string, 0, len(subjects))
+	for _, subject := range subjects {
+		commits = append(commits, "- "+subject)
+	}
+	data.Commits = strings.Join(commits, "\n")
+
+	pr := integrations.NewPullRequest{
+		Title:  data.Title,
+		Body:   pullRequestBody(data),
+		Branch: branchName,
+		Base:   base,
+		Draft:  options.Draft,
+	}
+
+	if options.DryRun {
+		fmt.Printf("\033[37;1m%s\033[0m\n\033[2m%s into %s on %s\033[0m\n\n%s\n", pr.Title, branchName, base, forge.Name(), pr.Body)
+		return nil
+	}
+
+	ahead := 1
+	if helpers.RemoteBranchExists(remote, branchName) {
+		ahead, _, _ = helpers.GetAheadBehind(remote+"/"+branchName, branchName)
+	}
+
+	if ahead > 0 {
+		if err := helpers.RunCommandOnStdout("git", "push", "--set-upstream", remote, branchName); err != nil {
+			return fmt.Errorf("failed to push '%s' to %s: %v", branchName, remote, err)
+		}
+
+		recordUsage(usage.EventPush, branchName)
+
+		if !options.NoTransition {
+			transitionBranchIssue(branchName, transitionReview)
+		}
+	}
+
+	created, err := forge.CreatePullRequest(pr)
+	if err != nil {
+		return fmt.Errorf("failed to create the pull request: %v", err)
+	}
+
+	fmt.Printf("Created pull request %s: %s\n", created.Ref(), created.Title)
+	fmt.Println(created.URL)
+
+	return nil
+}
+
+func init() {
+	options := pullRequestOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "pr:create",
+		Short: "Open a pull request for the current branch",
+		Long: `Open a pull request, or merge request, for the current branch on the forge hosting the repository, into the
+default branch. The branch is pushed first when needed. The title is taken from the linked issue, or the first
+commit, and the body lists the branch's commits, using the repository's pull request template when it has one,
+e.g. .github/pull_request_template.md. Templates may refer to {{.Title}}, {{.Branch}}, {{.Base}}, {{.IssueKey}},
+{{.IssueURL}} and {{.Commits}}.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			branchName, err := helpers.GetCurrentBranchName()
+			if err == nil && branchName == "" {
+				err = errors.New("HEAD is detached")
+			}
+
+			if err == nil {
+				err = createPullRequest(branchName, options)
+			}
+
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Base, "base", "b", "", "Branch to merge into instead of the default branch")
+	cmd.Flags().BoolVarP(&options.Draft, "draft", "d", false, "Open the pull request as a draft")
+	cmd.Flags().BoolVarP(&options.DryRun, "dry-run", "n", false, "Show the pull request without pushing or creating it")
+	cmd.Flags().BoolVar(&options.NoTransition, "no-transition", false, "When pushing, don't move the linked issue to In Review")
+
+	rootCmd.AddCommand(cmd)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// stdinReader is shared by all prompts, so that answers piped to stdin are not lost to buffering
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// ask prints a question and returns the answer, trimmed, or defaultAnswer when the answer is empty.
+func ask(question string, defaultAnswer string) string {
+	if defaultAnswer != "" {
+		fmt.Printf("%s [%s] ", question, defaultAnswer)
+	} else {
+		fmt.Printf("%s ", question)
+	}
+
+	answer, _ := stdinReader.ReadString('\n')
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return defaultAnswer
+	}
+
+	return answer
+}
+
+// confirm asks a yes/no question on stdout and reports whether it was answered with yes.
+func confirm(question string) bool {
+	answer := strings.ToLower(ask(question+" [y/N]", ""))
+
+	return answer == "y" || answer == "yes"
+}
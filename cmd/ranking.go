@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/app/ranking"
+	"github.com/spf13/cobra"
+)
+
+// addRankingFlags registers the --sort and --explain flags shared by the branch listing commands.
+func addRankingFlags(c *cobra.Command, sortBy *string, explain *bool) {
+	c.Flags().StringVarP(sortBy, "sort", "s", "", "Ranking strategy: "+strings.Join(ranking.Names(), ", "))
+	c.Flags().BoolVarP(explain, "explain", "x", false, "Show the components of each branch's score")
+}
+
+// getRanker returns the ranker selected by the --sort flag, the config file, or the command's default.
+func getRanker(sortBy string, defaultName string) (ranking.Ranker, error) {
+	return ranking.New(sortBy, defaultName, config.Get().Ranking)
+}
+
+// printScoreExplanation prints the components of a branch's score beneath its listing.
+func printScoreExplanation(score ranking.Score) {
+	fmt.Printf("      \033[2m%s\033[0m\n", score.Explain())
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/app/git"
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/report"
+	"github.com/permafrost-dev/git-ninja/app/usage"
+	"github.com/permafrost-dev/git-ninja/app/utils"
+	"github.com/spf13/cobra"
+)
+
+// addStandupCommits adds the commits authored by email on the branch since the given time.
+func addStandupCommits(branch *report.StandupBranch, email string, since time.Time) {
+	items, _ := git.GetRefLogItemsForBranch(branch.Name)
+	seen := make(map[string]bool)
+
+	for _, item := range items {
+		if !strings.HasPrefix(item.Action, "commit") || item.Timestamp.Before(since) || seen[item.CommitHash] {
+			continue
+		}
+
+		if email != "" && !strings.EqualFold(item.AuthorEmail, email) {
+			continue
+		}
+
+		seen[item.CommitHash] = true
+		branch.Commits = append(branch.Commits, report.StandupCommit{
+			Hash:      item.CommitHash,
+			Subject:   report.CommitSubjectFromRefLogMessage(item.Message),
+			Timestamp: item.Timestamp,
+		})
+	}
+}
+
+// addStandupPushes adds the pushes recorded in the remote-tracking branch's reflog and in the usage history.
+func addStandupPushes(branch *report.StandupBranch, remoteName string, since time.Time, events []usage.Event) {
+	pushes, _ := git.GetPushTimes(remoteName, branch.Name)
+
+	for _, pushedAt := range pushes {
+		if !pushedAt.Before(since) {
+			branch.Pushes = append(branch.Pushes, pushedAt)
+		}
+	}
+
+	for _, e := range events {
+		if e.Type != usage.EventPush || e.Branch != branch.Name {
+			continue
+		}
+
+		duplicate := false
+		for _, pushedAt := range branch.Pushes {
+			duplicate = duplicate || e.Timestamp.Sub(pushedAt).Abs() < 5*time.Second
+		}
+
+		if !duplicate {
+			branch.Pushes = append(branch.Pushes, e.Timestamp)
+		}
+	}
+}
+
+// buildStandup collects the branches checked out, committed to or pushed since the given time.
+func buildStandup(since time.Time, email string, remoteName string) *report.Standup {
+	standup := &report.Standup{Since: since}
+	events := make([]usage.Event, 0)
+
+	if store, err := usage.OpenCurrent(); err == nil {
+		events = store.EventsSince(since)
+	}
+
+	items, _ := git.GetHeadRefLogItems()
+	for _, item := range items {
+		if item.BranchName != "" && !item.Timestamp.Before(since) {
+			events = append(events, usage.Event{Type: usage.EventCheckout, Branch: item.BranchName, Timestamp: item.Timestamp})
+		}
+	}
+
+	existingBranches, _ := helpers.GetAvailableBranchesMap()
+
+	for _, e := range events {
+		if !utils.MapEntryExists(e.Branch, existingBranches) {
+			continue
+		}
+
+		branch := standup.Branch(e.Branch)
+		if e.Type == usage.EventCheckout && e.Timestamp.After(branch.CheckedOutLast) {
+			branch.CheckedOutLast = e.Timestamp
+		}
+	}
+
+	// work on the current branch counts even if it was checked out before the standup period
+	if currentBranch, err := helpers.GetCurrentBranchName(); err == nil && currentBranch != "" {
+		standup.Branch(currentBranch)
+	}
+
+	for _, branch := range standup.Branches {
+		addStandupCommits(branch, email, since)
+		addStandupPushes(branch, remoteName, since, events)
+	}
+
+	// drop the current branch again if nothing happened on it
+	filtered := make([]*report.StandupBranch, 0, len(standup.Branches))
+	for _, branch := range standup.Branches {
+		if !branch.CheckedOutLast.IsZero() || len(branch.Commits) > 0 || len(branch.Pushes) > 0 {
+			filtered = append(filtered, branch)
+		}
+	}
+	standup.Branches = filtered
+
+	return standup
+}
+
+// addStandupIssues links each branch to the issue the configured tracker matches to its name, fetching the
+// summaries from the tracker when it is configured.
+func addStandupIssues(standup *report.Standup) {
+	tracker, err := getIssueTracker()
+	if err != nil {
+		return
+	}
+
+	validateErr := tracker.Validate()
+
+	for _, branch := range standup.Branches {
+		key := tracker.MatchBranch(branch.Name)
+		if key == "" {
+			continue
+		}
+
+		branch.Issue = &report.StandupIssue{Key: key}
+
+		if validateErr != nil {
+			continue
+		}
+
+		if issue, err := tracker.GetIssue(key); err == nil {
+			branch.Issue.URL = issue.URL
+			branch.Issue.Summary = issue.Summary
+		}
+	}
+}
+
+func init() {
+	flagSince := ""
+	flagRemote := "origin"
+
+	cmd := &cobra.Command{
+		Use:   "report:standup [--since <date|duration>]",
+		Short: "Summarize your work since the last working day as Markdown",
+		Long: `Summarize the branches you checked out, the commits you authored and the pushes you made since
+the start of the last working day, as Markdown that can be pasted into chat. Commits are matched
+against your user.email. When an issue tracker is configured, linked issue summaries are included.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			now := time.Now()
+			since := report.LastWorkingDay(now)
+
+			if flagSince != "" {
+				var err error
+				if since, err = report.ParseSince(flagSince, now); err != nil {
+					fmt.Printf("Error: %v\n", err)
+					return
+				}
+			}
+
+			email, _ := utils.RunCommand("git", "config", "user.email")
+
+			standup := buildStandup(since, email, flagRemote)
+			addStandupIssues(standup)
+
+			fmt.Print(standup.Markdown())
+		},
+	}
+
+	cmd.Flags().StringVarP(&flagSince, "since", "s", "", "Start of the summary as a date (YYYY-MM-DD) or duration (e.g. 36h)")
+	cmd.Flags().StringVarP(&flagRemote, "remote", "r", "origin", "Remote used to find pushes")
+
+	rootCmd.AddCommand(cmd)
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/app/git"
+	"github.com/permafrost-dev/git-ninja/app/output"
+	"github.com/permafrost-dev/git-ninja/app/report"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/jira"
+	"github.com/spf13/cobra"
+)
+
+// printTimeReport writes the report in the requested format.
+func printTimeReport(r *report.TimeReport, format output.Format) error {
+	if format == output.FormatJSON {
+		return output.WriteJSON(os.Stdout, r)
+	}
+
+	branches := &output.Table{Headers: []string{"BRANCH", "ISSUE", "SESSIONS", "HOURS"}}
+	for _, bt := range r.Branches {
+		branches.AddRow(bt.Branch, bt.Issue, fmt.Sprintf("%d", len(bt.Sessions)), fmt.Sprintf("%.2f", bt.Hours))
+	}
+
+	if format == output.FormatCSV {
+		return branches.WriteCSV(os.Stdout)
+	}
+
+	fmt.Printf("Time spent from %s to %s: %.2f hours\n\n", r.From.Format("2006-01-02 15:04"), r.To.Format("2006-01-02 15:04"), r.Hours)
+
+	if err := branches.WriteText(os.Stdout); err != nil {
+		return err
+	}
+
+	if len(r.Issues) == 0 {
+		return nil
+	}
+
+	issues := &output.Table{Headers: []string{"ISSUE", "BRANCHES", "HOURS"}}
+	for _, it := range r.Issues {
+		issues.AddRow(it.Issue, strings.Join(it.Branches, ", "), fmt.Sprintf("%.2f", it.Hours))
+	}
+
+	fmt.Println()
+
+	return issues.WriteText(os.Stdout)
+}
+
+func init() {
+	flagPeriod := "day"
+	flagFrom := ""
+	flagTo := ""
+	flagIdle := ""
+	flagFormat := string(output.FormatTable)
+
+	cmd := &cobra.Command{
+		Use:   "report:time [--period day|week] [--from YYYY-MM-DD [--to YYYY-MM-DD]]",
+		Short: "Report the time spent on each branch and issue",
+		Long: `Report the time spent on each branch and issue, reconstructed from the checkouts and commits
+recorded in the reflog. Gaps in activity longer than the idle limit are not counted.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			format, err := output.ParseFormat(flagFormat)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			idleLimit := config.Get().Reports.IdleLimitDuration()
+			if flagIdle != "" {
+				if idleLimit, err = time.ParseDuration(flagIdle); err != nil || idleLimit <= 0 {
+					fmt.Printf("Error: invalid idle limit '%s'\n", flagIdle)
+					return
+				}
+			}
+
+			now := time.Now()
+
+			from, to, err := report.ParseRange(flagPeriod, flagFrom, flagTo, now)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+
+			items, err := git.GetHeadRefLogItems()
+			if err != nil {
+				fmt.Printf("Error: failed to read reflog: %v\n", err)
+				return
+			}
+
+			sessions := report.BuildSessions(items, idleLimit, now)
+			timeReport := report.BuildTimeReport(sessions, from, to, jira.ExtractIssueKey)
+
+			if err := printTimeReport(timeReport, format); err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&flagPeriod, "period", "p", "day", "Report period: day or week")
+	cmd.Flags().StringVar(&flagFrom, "from", "", "Start date of a custom range (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&flagTo, "to", "", "End date of a custom range, inclusive (YYYY-MM-DD)")
+	cmd.Flags().StringVarP(&flagIdle, "idle", "i", "", "Idle limit, e.g. 30m (default from config, or 1h)")
+	cmd.Flags().StringVarP(&flagFormat, "format", "f", string(output.FormatTable), "Output format: "+strings.Join(output.Formats, ", "))
+
+	rootCmd.AddCommand(cmd)
+}
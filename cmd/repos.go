@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/repos"
+	"github.com/permafrost-dev/git-ninja/app/utils"
+	"github.com/spf13/cobra"
+)
+
+// addRepositories registers each path and saves the registry, printing the result for each one.
+func addRepositories(paths []string) {
+	registry, err := repos.LoadRegistry()
+	if err != nil {
+		fmt.Printf("error: %v\n", err)
+		return
+	}
+
+	for _, path := range paths {
+		repo, added, err := registry.Add(path)
+		if err != nil {
+			fmt.Printf("error: %v\n", err)
+			continue
+		}
+
+		if added {
+			fmt.Printf("Added %s (%s)\n", repo.Name, repo.Path)
+		} else {
+			fmt.Printf("Already registered: %s (%s)\n", repo.Name, repo.Path)
+		}
+	}
+
+	if err := registry.Save(); err != nil {
+		fmt.Printf("error: %v\n", err)
+	}
+}
+
+func init() {
+	flagScanDepth := 3
+	flagRecentCount := 15
+	flagRecentCheckout := 0
+	flagRecentSwitch := false
+
+	reposAddCmd := &cobra.Command{
+		Use:   "repos:add [path...]",
+		Short: "Register repositories for the cross-repository commands",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				args = []string{"."}
+			}
+
+			addRepositories(args)
+		},
+	}
+
+	reposScanCmd := &cobra.Command{
+		Use:   "repos:scan <dir>",
+		Short: "Find and register all repositories in a directory",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			paths, err := repos.Scan(args[0], flagScanDepth)
+			if err != nil {
+				fmt.Printf("error: %v\n", err)
+				return
+			}
+
+			if len(paths) == 0 {
+				fmt.Println("No repositories found.")
+				return
+			}
+
+			addRepositories(paths)
+		},
+	}
+
+	reposListCmd := &cobra.Command{
+		Use:   "repos:list",
+		Short: "List registered repositories",
+		Run: func(cmd *cobra.Command, args []string) {
+			registry, err := repos.LoadRegistry()
+			if err != nil {
+				fmt.Printf("error: %v\n", err)
+				return
+			}
+
+			for _, repo := range registry.Repositories {
+				fmt.Printf("  \033[37;1m%-25s\033[0m %s\n", repo.Name, repo.Path)
+			}
+		},
+	}
+
+	reposRemoveCmd := &cobra.Command{
+		Use:   "repos:remove <name-or-path>",
+		Short: "Unregister a repository",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			registry, err := repos.LoadRegistry()
+			if err != nil {
+				fmt.Printf("error: %v\n", err)
+				return
+			}
+
+			path := args[0]
+			if abs, err := filepath.Abs(path); err == nil && registry.Find(abs) != nil {
+				path = abs
+			}
+
+			if !registry.Remove(path) {
+				fmt.Printf("error: %s is not registered\n", args[0])
+				return
+			}
+
+			if err := registry.Save(); err != nil {
+				fmt.Printf("error: %v\n", err)
+			}
+		},
+	}
+
+	reposRecentCmd := &cobra.Command{
+		Use:   "repos:recent [--count|-c <count>] [--checkout|-o <number> [--switch-branch]]",
+		Short: "Show recently checked out branches across all registered repositories",
+		Long: `Show recently checked out branches across all registered repositories.
+
+With --checkout, only the repository path of the numbered branch is printed, so it can be
+used with cd. Add --switch-branch to also check out the branch in that repository:
+
+    cd "$(git-ninja repos:recent --checkout 2 --switch-branch)"`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if flagRecentSwitch && flagRecentCheckout == 0 {
+				fmt.Fprintln(os.Stderr, "error: --switch-branch requires --checkout")
+				os.Exit(1)
+			}
+
+			registry, err := repos.LoadRegistry()
+			if err != nil {
+				fmt.Printf("error: %v\n", err)
+				return
+			}
+
+			if len(registry.Repositories) == 0 {
+				fmt.Println("No repositories registered, add some with repos:add or repos:scan.")
+				return
+			}
+
+			branches, errs := repos.GetRecentBranches(registry.Repositories)
+			for _, err := range errs {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+			}
+
+			if flagRecentCheckout > 0 {
+				if flagRecentCheckout > len(branches) {
+					fmt.Fprintf(os.Stderr, "error: there is no branch number %d\n", flagRecentCheckout)
+					os.Exit(1)
+				}
+
+				selected := branches[flagRecentCheckout-1]
+
+				if flagRecentSwitch {
+					// git's output goes to stderr so that stdout only contains the path
+					checkout := exec.Command("git", helpers.GitArgs(selected.Repository.Path, "checkout", selected.Branch)...)
+					checkout.Stdout = os.Stderr
+					checkout.Stderr = os.Stderr
+
+					if err := checkout.Run(); err != nil {
+						os.Exit(1)
+					}
+				}
+
+				fmt.Println(selected.Repository.Path)
+				return
+			}
+
+			for idx, branch := range branches {
+				if idx >= flagRecentCount {
+					break
+				}
+
+				fmt.Printf("  \033[2m%2d.\033[0m \033[33m%-15s \033[36m%-20s\033[37;1m %s\033[0m \033[2m%s\033[0m\n",
+					idx+1, utils.GetRelativeTime(branch.CheckedOutLast), branch.Repository.Name, branch.Branch, branch.Repository.Path)
+			}
+		},
+	}
+
+	reposScanCmd.Flags().IntVarP(&flagScanDepth, "depth", "d", 3, "Maximum directory depth to search")
+	reposRecentCmd.Flags().IntVarP(&flagRecentCount, "count", "c", 15, "Limit the number of branches to display")
+	reposRecentCmd.Flags().IntVarP(&flagRecentCheckout, "checkout", "o", 0, "Print the repository path of the numbered branch")
+	reposRecentCmd.Flags().BoolVar(&flagRecentSwitch, "switch-branch", false, "Also check out the branch selected with --checkout in its repository")
+
+	rootCmd.AddCommand(reposAddCmd, reposScanCmd, reposListCmd, reposRemoveCmd, reposRecentCmd)
+}
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/jira"
 	"github.com/spf13/cobra"
 )
 
@@ -23,6 +26,13 @@ func Execute() {
 	}
 }
 
+// applyConfig configures the packages affected by settings in the configuration file.
+func applyConfig() {
+	if err := jira.SetIssueKeyPattern(config.Get().Jira.IssueKeyPattern); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+}
+
 func init() {
-	//
+	cobra.OnInitialize(applyConfig)
 }
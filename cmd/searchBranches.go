@@ -2,26 +2,31 @@ package cmd
 
 import (
 	"fmt"
-	"regexp"
 	"sort"
 	"strings"
 
 	"github.com/permafrost-dev/git-ninja/app/git"
 	"github.com/permafrost-dev/git-ninja/app/helpers"
 	"github.com/permafrost-dev/git-ninja/app/utils"
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
 	"github.com/spf13/cobra"
 )
 
-func getAllBranchDataSortedByAge(lines []string, availableBranches map[string]bool) []*git.BranchCheckoutInfo {
-	var lineRegex = regexp.MustCompile(`([0-9]+) ~ (checkout):.+ ([^~]+) ~ HEAD@{(.*)}`)
+func getAllBranchDataSortedByAge(items []*git.RefLogItem, availableBranches map[string]bool) []*git.BranchCheckoutInfo {
 	result := make([]*git.BranchCheckoutInfo, 0)
+	seen := make(map[string]bool)
 
-	for _, line := range lines {
-		info := git.GetBranchInfoFromReflogLine(lineRegex, line, 4)
-
-		if info != nil && utils.MapEntryExists(info.BranchName, availableBranches) && !git.SliceContainsBranchCommitData(result, info) {
-			result = append(result, info)
+	for _, item := range items {
+		if item.BranchName == "" || seen[item.BranchName] || !utils.MapEntryExists(item.BranchName, availableBranches) {
+			continue
 		}
+
+		seen[item.BranchName] = true
+		result = append(result, &git.BranchCheckoutInfo{
+			BranchName:   item.BranchName,
+			RelativeTime: utils.GetRelativeTime(item.Timestamp),
+			Timestamp:    item.Timestamp,
+		})
 	}
 
 	sort.Slice(result, func(i, j int) bool {
@@ -33,6 +38,7 @@ func getAllBranchDataSortedByAge(lines []string, availableBranches map[string]bo
 
 var flagRegex bool = false
 var flagCheckoutFirst bool = false
+var flagSearchWithIssues bool = false
 
 var searchBranchesCmd = &cobra.Command{
 	Use:   "branch:search [--regex|-r] <substring-or-regex>",
@@ -45,9 +51,9 @@ var searchBranchesCmd = &cobra.Command{
 		}
 
 		searchFor := args[0]
-		lines, _ := git.GetGitReflogLines("%at ~ %gs ~ %gd")
+		items, _ := git.GetHeadRefLogItems()
 		existingBranches, _ := helpers.GetAvailableBranchesMap()
-		sortedBranches := getAllBranchDataSortedByAge(lines, existingBranches)
+		sortedBranches := getAllBranchDataSortedByAge(items, existingBranches)
 
 		var matches []*git.BranchCheckoutInfo
 
@@ -71,8 +77,17 @@ var searchBranchesCmd = &cobra.Command{
 			return
 		}
 
+		issues := map[string]*integrations.Issue{}
+		if flagSearchWithIssues {
+			names := make([]string, 0, len(matches))
+			for _, branch := range matches {
+				names = append(names, branch.BranchName)
+			}
+			issues = getBranchIssues(names)
+		}
+
 		for _, branch := range matches {
-			fmt.Printf("  \033[33m%-16s \033[37;1m %s\033[0m\n", branch.RelativeTime, branch.BranchName)
+			fmt.Println(formatBranchLine(fmt.Sprintf("%-16s", branch.RelativeTime), branch.BranchName, issues, nil))
 		}
 	},
 }
@@ -81,5 +96,6 @@ func init() {
 	rootCmd.AddCommand(searchBranchesCmd)
 
 	searchBranchesCmd.Flags().BoolVarP(&flagRegex, "regex", "r", false, "Search using a regular expression pattern")
+	searchBranchesCmd.Flags().BoolVar(&flagSearchWithIssues, "with-issues", false, "Show the status and summary of the issue linked to each branch")
 	searchBranchesCmd.Flags().BoolVarP(&flagCheckoutFirst, "checkout", "o", false, "Checkout the first matching branch")
 }
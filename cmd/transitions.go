@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/permafrost-dev/git-ninja/app/config"
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/usage"
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+)
+
+// noTransitionEnv disables the automatic issue transitions when set
+const noTransitionEnv = "GIT_NINJA_NO_TRANSITION"
+
+// Branch events that can move the linked issue
+const (
+	transitionStart  = "start"
+	transitionReview = "review"
+)
+
+// transitionsDisabled reports whether automatic transitions are turned off by the environment.
+func transitionsDisabled() bool {
+	return os.Getenv(noTransitionEnv) != ""
+}
+
+// isFirstCheckout reports whether the branch has been checked out only once, according to the usage history.
+func isFirstCheckout(branchName string) bool {
+	store, err := usage.OpenCurrent()
+	if err != nil {
+		return false
+	}
+
+	syncUsage(store)
+
+	return store.CountEvents(usage.EventCheckout, branchName) <= 1
+}
+
+// transitionBranchIssue moves the issue linked to a branch using the transition configured for the event,
+// when transitions are enabled. Issues that are already past the event's status are left alone. Failures are
+// reported as warnings, since the git operation that triggered the transition has already succeeded.
+func transitionBranchIssue(branchName string, event string) {
+	cfg := config.Get().Issues.Transitions
+	if !cfg.Enabled || transitionsDisabled() {
+		return
+	}
+
+	name := cfg.Start
+	if event == transitionReview {
+		name = cfg.Review
+	}
+
+	tracker, err := getIssueTracker()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		return
+	}
+
+	key := tracker.MatchBranch(branchName)
+	if name == "" || key == "" {
+		return
+	}
+
+	issue, err := tracker.GetIssue(key)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to fetch %s: %v\n", key, err)
+		return
+	}
+
+	// don't move issues back to an earlier status
+	if strings.EqualFold(issue.Status, name) || issue.IsDone() || (event == transitionStart && issue.IsInProgress()) {
+		return
+	}
+
+	if err := tracker.TransitionIssue(key, name); err != nil {
+		if errors.Is(err, integrations.ErrTransitionNotFound) {
+			fmt.Fprintf(os.Stderr, "warning: %s cannot be moved to '%s' from '%s'\n", key, name, issue.Status)
+		} else {
+			fmt.Fprintf(os.Stderr, "warning: failed to move %s to '%s': %v\n", key, name, err)
+		}
+		return
+	}
+
+	fmt.Printf("Moved %s from %s to %s\n", key, issue.Status, name)
+}
+
+// transitionIfFirstCheckout applies the start transition when the current branch is checked out for the first time.
+// It is only called by git-ninja's own commands, never by the post-checkout hook, so that plain git checkouts don't
+// wait for the issue tracker.
+func transitionIfFirstCheckout() {
+	if !config.Get().Issues.Transitions.Enabled || transitionsDisabled() {
+		return
+	}
+
+	branchName, err := helpers.GetCurrentBranchName()
+	if err != nil || branchName == "" {
+		return
+	}
+
+	if isFirstCheckout(branchName) {
+		transitionBranchIssue(branchName, transitionStart)
+	}
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/permafrost-dev/git-ninja/app/git"
+	"github.com/permafrost-dev/git-ninja/app/helpers"
+	"github.com/permafrost-dev/git-ninja/app/usage"
+	"github.com/spf13/cobra"
+)
+
+// getBranchUsage returns the checkout history and commit counts of existing branches from the usage store,
+// after importing new reflog entries into it. When the store cannot be read, only the reflogs are used.
+func getBranchUsage(existingBranches map[string]bool) map[string]git.BranchInfo {
+	store, err := usage.OpenCurrent()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+
+		root, _ := helpers.GetRepositoryRoot()
+		store = usage.NewMemoryStore(root)
+	}
+
+	syncUsage(store)
+
+	return store.BranchInfos(existingBranches)
+}
+
+// syncUsage adds the reflog events missing from the usage history and saves it. Failures are reported as warnings,
+// since the history is still usable without them.
+func syncUsage(store *usage.Store) {
+	added, err := store.Sync()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to sync the usage history: %v\n", err)
+		return
+	}
+
+	if added > 0 {
+		if err := store.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to save the usage history: %v\n", err)
+		}
+	}
+}
+
+// recordUsage records a branch event in the usage history, reporting failures as warnings.
+func recordUsage(eventType usage.EventType, branchName string) {
+	if err := usage.Record(eventType, branchName, usage.SourceGitNinja); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record the %s of '%s': %v\n", eventType, branchName, err)
+	}
+}
+
+func init() {
+	usageRecordCmd := &cobra.Command{
+		Use:       "usage:record <checkout|commit|push> [branch]",
+		Short:     "Record a branch event in the usage history",
+		Hidden:    true,
+		Args:      cobra.RangeArgs(1, 2),
+		ValidArgs: []string{string(usage.EventCheckout), string(usage.EventCommit), string(usage.EventPush)},
+		Run: func(cmd *cobra.Command, args []string) {
+			eventType := usage.EventType(args[0])
+			if eventType != usage.EventCheckout && eventType != usage.EventCommit && eventType != usage.EventPush {
+				fmt.Printf("error: unknown event type '%s'\n", args[0])
+				return
+			}
+
+			branchName, _ := helpers.GetCurrentBranchName()
+			if len(args) > 1 {
+				branchName = args[1]
+			}
+
+			if err := usage.Record(eventType, branchName, usage.SourceHook); err != nil {
+				fmt.Printf("error: %v\n", err)
+			}
+		},
+	}
+
+	usageSyncCmd := &cobra.Command{
+		Use:   "usage:sync",
+		Short: "Import the reflog into the usage history",
+		Run: func(cmd *cobra.Command, args []string) {
+			store, err := usage.OpenCurrent()
+			if err != nil {
+				fmt.Printf("error: %v\n", err)
+				return
+			}
+
+			added, err := store.Sync()
+			if err != nil {
+				fmt.Printf("error: failed to read reflog: %v\n", err)
+				return
+			}
+
+			if err := store.Save(); err != nil {
+				fmt.Printf("error: %v\n", err)
+				return
+			}
+
+			fmt.Printf("Imported %d new events, %d events recorded.\n", added, len(store.Events))
+		},
+	}
+
+	hooksInstallCmd := &cobra.Command{
+		Use:   "hooks:install",
+		Short: "Install a post-checkout hook that records branch usage",
+		Run: func(cmd *cobra.Command, args []string) {
+			hookFile, err := usage.InstallPostCheckoutHook()
+			if err != nil {
+				fmt.Printf("error: %v\n", err)
+				return
+			}
+
+			fmt.Printf("Installed hook: %s\n", hookFile)
+		},
+	}
+
+	rootCmd.AddCommand(usageRecordCmd, usageSyncCmd, hooksInstallCmd)
+}
@@ -0,0 +1,338 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultTTL is how long cached data is fresh before it is fetched again
+const DefaultTTL = 5 * time.Minute
+
+// DefaultMaxStale is how long expired data may still be used when it cannot be fetched again
+const DefaultMaxStale = 24 * time.Hour
+
+// lockTimeout is how long to wait for another process to release a file's lock before proceeding without it
+const lockTimeout = 15 * time.Second
+
+// DefaultDir returns $XDG_CACHE_HOME/git-ninja, or ~/.cache/git-ninja.
+func DefaultDir() string {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" && filepath.IsAbs(dir) {
+		return filepath.Join(dir, "git-ninja")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = os.Getenv("HOME")
+	}
+
+	return filepath.Join(home, ".cache", "git-ninja")
+}
+
+// Metadata describes when cached data was fetched and how long it can be used
+type Metadata struct {
+	// Key identifies the cached data, e.g. the query it is the result of
+	Key       string    `json:"key"`
+	FetchedAt time.Time `json:"fetched_at"`
+	// ExpiresAt is when the data stops being fresh; a zero value means it never expires
+	ExpiresAt time.Time `json:"expires_at"`
+	// StaleUntil is when expired data stops being used as a fallback when it cannot be fetched again
+	StaleUntil time.Time `json:"stale_until"`
+	// LastError is the error of the last failed attempt to fetch the data again
+	LastError   string    `json:"last_error,omitempty"`
+	LastAttempt time.Time `json:"last_attempt,omitempty"`
+}
+
+// IsFresh reports whether the data has not expired.
+func (m *Metadata) IsFresh(now time.Time) bool {
+	return m.ExpiresAt.IsZero() || now.Before(m.ExpiresAt)
+}
+
+// IsUsable reports whether the data is fresh, or expired but still usable as a fallback.
+func (m *Metadata) IsUsable(now time.Time) bool {
+	return m.IsFresh(now) || now.Before(m.StaleUntil)
+}
+
+// Describe returns a short description of the age of the data, e.g. "cached 3m ago" or
+// "stale, cached 2h ago; refresh failed: ...", for display by commands.
+func (m *Metadata) Describe(now time.Time) string {
+	age := now.Sub(m.FetchedAt).Round(time.Second)
+
+	if m.IsFresh(now) {
+		return fmt.Sprintf("cached %s ago", age)
+	}
+
+	description := fmt.Sprintf("stale, cached %s ago", age)
+	if m.LastError != "" {
+		description += "; refresh failed: " + m.LastError
+	}
+
+	return description
+}
+
+// entry is the structure of a cache file
+type entry struct {
+	Metadata
+	Data json.RawMessage `json:"data"`
+}
+
+// Store keeps cached data in one JSON file per name in a directory. Files are replaced atomically, so they
+// can be read without locking, and refreshes are serialized between processes with advisory file locks.
+type Store struct {
+	Dir string
+	// TTL is how long data is fresh; zero means it never expires
+	TTL time.Duration
+	// MaxStale is how long expired data may be used when it cannot be fetched again
+	MaxStale time.Duration
+	// Now returns the current time; time.Now is used when it is nil
+	Now func() time.Time
+}
+
+// NewStore returns a store in dir using the default TTL and maximum staleness.
+func NewStore(dir string) *Store {
+	return &Store{Dir: dir, TTL: DefaultTTL, MaxStale: DefaultMaxStale}
+}
+
+func (s *Store) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+
+	return s.Now()
+}
+
+// FileName returns the path of the cache file for name, which may contain slashes to use subdirectories.
+func (s *Store) FileName(name string) string {
+	return filepath.Join(s.Dir, filepath.FromSlash(name)+".json")
+}
+
+func (s *Store) readEntry(name string) (*entry, error) {
+	data, err := os.ReadFile(s.FileName(name))
+	if err != nil {
+		return nil, err
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("failed to decode cache file: %v", err)
+	}
+
+	// expiry follows the store's current TTL, so that changing it applies to data that is already cached
+	e.Metadata = s.metadata(e.Key, e.FetchedAt, e.LastError, e.LastAttempt)
+
+	return &e, nil
+}
+
+func (s *Store) metadata(key string, fetchedAt time.Time, lastError string, lastAttempt time.Time) Metadata {
+	m := Metadata{Key: key, FetchedAt: fetchedAt, StaleUntil: fetchedAt.Add(s.TTL + s.MaxStale), LastError: lastError, LastAttempt: lastAttempt}
+	if s.TTL > 0 {
+		m.ExpiresAt = fetchedAt.Add(s.TTL)
+	}
+
+	return m
+}
+
+// Read decodes the cached data for name into v and returns its metadata, whether or not it has expired.
+// An error wrapping os.ErrNotExist is returned when nothing is cached.
+func (s *Store) Read(name string, v any) (*Metadata, error) {
+	e, err := s.readEntry(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return nil, fmt.Errorf("failed to decode cached data: %v", err)
+	}
+
+	return &e.Metadata, nil
+}
+
+// Write replaces the cached data for name, atomically.
+func (s *Store) Write(name string, key string, v any) (*Metadata, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cached data: %v", err)
+	}
+
+	e := &entry{Metadata: s.metadata(key, s.now(), "", time.Time{}), Data: data}
+
+	return &e.Metadata, s.writeEntry(name, e)
+}
+
+func (s *Store) writeEntry(name string, e *entry) error {
+	fileName := s.FileName(name)
+
+	if err := os.MkdirAll(filepath.Dir(fileName), 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cache file: %v", err)
+	}
+
+	if err := WriteFile(fileName, data); err != nil {
+		return fmt.Errorf("failed to write cache file: %v", err)
+	}
+
+	return nil
+}
+
+// Lock acquires an advisory lock on the cache file for name, shared by all processes, and returns a
+// function that releases it. If the lock cannot be acquired within 15 seconds, an error is returned.
+func (s *Store) Lock(name string) (func(), error) {
+	fileName := s.FileName(name)
+
+	if err := os.MkdirAll(filepath.Dir(fileName), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %v", err)
+	}
+
+	return LockFile(fileName)
+}
+
+// WriteFile replaces the file fileName with data atomically, by writing a temporary file in the same directory and
+// renaming it, so that readers never see a partly written file. The directory must exist.
+func WriteFile(fileName string, data []byte) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(fileName), "."+filepath.Base(fileName)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write(data); err != nil {
+		tempFile.Close()
+		return err
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), fileName)
+}
+
+// LockFile acquires an advisory lock on the file fileName, shared by all processes, using a lock file next to it,
+// and returns a function that releases it. If the lock cannot be acquired within 15 seconds, an error is returned.
+// The directory must exist.
+func LockFile(fileName string) (func(), error) {
+	file, err := os.OpenFile(fileName+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %v", err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		locked, err := tryLockFile(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %v", filepath.Base(fileName), err)
+		}
+
+		if locked {
+			return func() {
+				unlockFile(file)
+				file.Close()
+			}, nil
+		}
+
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("timed out waiting for the lock of %s", filepath.Base(fileName))
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// Fetch decodes the cached data for name into v if it is fresh and was stored with the same key. Otherwise the
+// data is fetched again, stored, and decoded into v. When fetching fails, expired data that is still usable is
+// returned instead, with the error recorded in its metadata. Only one process fetches the data at a time.
+func (s *Store) Fetch(name string, key string, v any, fetch func() (any, error)) (*Metadata, error) {
+	cached, err := s.readEntry(name)
+	if err == nil && cached.Key == key && cached.IsFresh(s.now()) {
+		return &cached.Metadata, s.decode(cached, v)
+	}
+
+	if unlock, err := s.Lock(name); err == nil {
+		defer unlock()
+
+		// another process may have fetched the data while we were waiting for the lock
+		if e, err := s.readEntry(name); err == nil && e.Key == key {
+			if e.IsFresh(s.now()) {
+				return &e.Metadata, s.decode(e, v)
+			}
+			cached = e
+		}
+	}
+
+	data, fetchErr := fetch()
+	if fetchErr != nil {
+		if cached == nil || cached.Key != key || !cached.IsUsable(s.now()) {
+			return nil, fetchErr
+		}
+
+		cached.LastError = fetchErr.Error()
+		cached.LastAttempt = s.now()
+		s.writeEntry(name, cached)
+
+		return &cached.Metadata, s.decode(cached, v)
+	}
+
+	meta, err := s.Write(name, key, data)
+	if err != nil {
+		// the data was fetched, so proceed without caching it
+		meta = &Metadata{Key: key, FetchedAt: s.now()}
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return meta, json.Unmarshal(encoded, v)
+}
+
+// Update reads the cached data for name into v, calls modify to change it, and stores the result, holding
+// the file's lock so that concurrent updates from other processes are not lost. Missing or unreadable data
+// leaves v unchanged before modify is called.
+func (s *Store) Update(name string, key string, v any, modify func() error) error {
+	unlock, err := s.Lock(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if e, err := s.readEntry(name); err == nil && e.Key == key {
+		s.decode(e, v)
+	}
+
+	if err := modify(); err != nil {
+		return err
+	}
+
+	_, err = s.Write(name, key, v)
+
+	return err
+}
+
+// Clear removes every cache file in the store's directory.
+func (s *Store) Clear() error {
+	err := os.RemoveAll(s.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *Store) decode(e *entry, v any) error {
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return fmt.Errorf("failed to decode cached data: %v", err)
+	}
+
+	return nil
+}
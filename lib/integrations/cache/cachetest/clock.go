@@ -0,0 +1,37 @@
+// Package cachetest provides helpers for testing code that caches data with the cache package.
+package cachetest
+
+import (
+	"sync"
+	"time"
+)
+
+// Start is the time a Clock starts at
+var Start = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+// Clock is a manually advanced clock for testing cache expiry. It is safe for concurrent use.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock returns a clock set to Start.
+func NewClock() *Clock {
+	return &Clock{now: Start}
+}
+
+// Now returns the clock's current time. It can be passed to the WithClock option of clients.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Advance moves the clock forward.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
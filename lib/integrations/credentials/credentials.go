@@ -0,0 +1,164 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FieldKind describes the role of a credential field, which determines the providers that can supply it
+type FieldKind int
+
+const (
+	// KindSetting is a field such as a base URL, which host-based providers cannot supply
+	KindSetting FieldKind = iota
+	// KindUsername is the account name, e.g. an email address; it is the login of a ~/.netrc entry
+	KindUsername
+	// KindPassword is the secret, e.g. an API token; it is the password of a ~/.netrc entry
+	KindPassword
+)
+
+// Field is a credential needed by an integration
+type Field struct {
+	// Name identifies the field within the integration, e.g. "token"; it is the key used in the credentials file
+	Name string
+	Kind FieldKind
+	// EnvVars are the environment variables the field is read from, in order of precedence
+	EnvVars []string
+}
+
+// IsSecret reports whether the field's value must not be displayed.
+func (f Field) IsSecret() bool {
+	return f.Kind == KindPassword
+}
+
+// Integration lists the credentials needed by an integration such as JIRA
+type Integration struct {
+	// Name identifies the integration, e.g. "jira"; it is the section used in git config and the credentials file
+	Name   string
+	Fields []Field
+	// Host returns the host that usernames and passwords are stored for, based on the resolved settings,
+	// or an empty string when it is not known
+	Host func(settings map[string]string) string
+}
+
+// Request is a lookup of a single credential field
+type Request struct {
+	Integration string
+	Field       Field
+	// Host is the host the credential is used for, needed by providers that store credentials per host
+	Host string
+}
+
+// Provider is a source of credentials
+type Provider interface {
+	// Name describes the source, e.g. "~/.netrc"
+	Name() string
+	// Get returns the value of the requested field, or an empty string if the source doesn't have it. The
+	// returned location describes where the value was found, e.g. the name of an environment variable.
+	Get(req Request) (value string, location string, err error)
+}
+
+// Credential is a resolved credential field and the source that supplied it
+type Credential struct {
+	Field Field
+	Value string
+	// Source describes where the value was found, e.g. "environment variable JIRA_API_TOKEN"; it is empty
+	// when no source has the field
+	Source string
+}
+
+// Resolved holds the credentials of an integration
+type Resolved struct {
+	Integration string
+	Host        string
+	Credentials []Credential
+	// Warnings lists the errors of sources that could not be read; other sources were used instead
+	Warnings []error
+}
+
+// Get returns the value of the named field, or an empty string if it was not found.
+func (r *Resolved) Get(name string) string {
+	for _, c := range r.Credentials {
+		if c.Field.Name == name {
+			return c.Value
+		}
+	}
+
+	return ""
+}
+
+// Chain is a list of providers, in order of precedence
+type Chain []Provider
+
+// ErrInsecureFile is returned when a file holding credentials can be read by other users
+var ErrInsecureFile = errors.New("file is accessible by other users")
+
+// Resolve looks up each of the integration's fields in the providers, in order, using the first value found.
+// Settings are resolved first, so that the host can be derived from them for usernames and passwords.
+// Providers that fail are skipped and reported in the result's warnings.
+func (c Chain) Resolve(integration Integration) *Resolved {
+	result := &Resolved{Integration: integration.Name}
+	settings := make(map[string]string)
+	credentials := make(map[string]Credential)
+	warned := make(map[string]bool)
+
+	resolve := func(field Field, host string) {
+		credential := Credential{Field: field}
+
+		for _, provider := range c {
+			value, location, err := provider.Get(Request{Integration: integration.Name, Field: field, Host: host})
+			if err != nil {
+				if !warned[provider.Name()] {
+					warned[provider.Name()] = true
+					result.Warnings = append(result.Warnings, fmt.Errorf("%s: %v", provider.Name(), err))
+				}
+				continue
+			}
+
+			if value != "" {
+				credential.Value, credential.Source = value, location
+				break
+			}
+		}
+
+		credentials[field.Name] = credential
+	}
+
+	for _, field := range integration.Fields {
+		if field.Kind == KindSetting {
+			resolve(field, "")
+			settings[field.Name] = credentials[field.Name].Value
+		}
+	}
+
+	if integration.Host != nil {
+		result.Host = integration.Host(settings)
+	}
+
+	for _, field := range integration.Fields {
+		if field.Kind != KindSetting {
+			resolve(field, result.Host)
+		}
+	}
+
+	for _, field := range integration.Fields {
+		result.Credentials = append(result.Credentials, credentials[field.Name])
+	}
+
+	return result
+}
+
+// ResolveHost resolves only the integration's settings and returns the host derived from them, without looking up
+// usernames and passwords, e.g. to tell whether the integration serves a host without running a credential helper.
+func (c Chain) ResolveHost(integration Integration) string {
+	settingsOnly := integration
+	settingsOnly.Fields = nil
+
+	for _, field := range integration.Fields {
+		if field.Kind == KindSetting {
+			settingsOnly.Fields = append(settingsOnly.Fields, field)
+		}
+	}
+
+	return c.Resolve(settingsOnly).Host
+}
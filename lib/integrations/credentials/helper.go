@@ -0,0 +1,94 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// HelperProvider gets credentials from a command that speaks git's credential helper protocol, which git runs
+// with the "get" argument: it receives "protocol=https" and "host=<host>" on stdin, and prints "username=..." and
+// "password=..." lines. Like git's credential.helper setting, the helper is either a shell command prefixed
+// with "!", an absolute path, or the name of a git-credential-<name> program, e.g. "osxkeychain".
+type HelperProvider struct {
+	Helper string
+
+	// answers caches the helper's output per host, so it runs once for both the username and password
+	answers map[string]map[string]string
+}
+
+func (p *HelperProvider) Name() string {
+	return "credential helper"
+}
+
+func (p *HelperProvider) Get(req Request) (string, string, error) {
+	if p.Helper == "" || req.Host == "" || req.Field.Kind == KindSetting {
+		return "", "", nil
+	}
+
+	if p.answers == nil {
+		p.answers = make(map[string]map[string]string)
+	}
+
+	answer, ok := p.answers[req.Host]
+	if !ok {
+		var err error
+		if answer, err = p.run(req.Host); err != nil {
+			return "", "", err
+		}
+		p.answers[req.Host] = answer
+	}
+
+	value := answer["username"]
+	if req.Field.Kind == KindPassword {
+		value = answer["password"]
+	}
+
+	// the helper isn't included, as shell commands may contain secrets
+	return value, "credential helper", nil
+}
+
+// run asks git for the credentials of host with "git credential fill", using only the configured helper, so that
+// the helper is run the way git runs it on every platform.
+func (p *HelperProvider) run(host string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// the empty credential.helper clears the helpers configured elsewhere, and the empty core.askPass the askpass
+	// program configured elsewhere
+	cmd := exec.CommandContext(ctx, "git", "-c", "core.askPass=", "-c", "credential.helper=", "-c", "credential.helper="+p.Helper, "credential", "fill")
+	cmd.Stdin = strings.NewReader("protocol=https\nhost=" + host + "\n\n")
+
+	// git asks for the credentials the helper doesn't have, so prompts, including those of graphical askpass
+	// programs, are disabled to make it fail instead
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if strings.Contains(message, "terminal prompts disabled") {
+			return map[string]string{}, nil
+		}
+
+		if message != "" {
+			err = fmt.Errorf("%v: %s", err, message)
+		}
+		return nil, fmt.Errorf("git credential fill failed: %v", err)
+	}
+
+	answer := make(map[string]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		if key, value, found := strings.Cut(strings.TrimRight(line, "\r"), "="); found {
+			answer[key] = value
+		}
+	}
+
+	return answer, nil
+}
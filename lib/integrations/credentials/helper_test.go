@@ -0,0 +1,74 @@
+package credentials_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/credentials"
+)
+
+func TestHelperProviderRunsTheHelperThroughGit(t *testing.T) {
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	token := credentials.Field{Name: "token", Kind: credentials.KindPassword}
+	email := credentials.Field{Name: "email", Kind: credentials.KindUsername}
+
+	tests := []struct {
+		helper, username, password string
+	}{
+		{`!f() { test "$1" = get && echo username=ann@example.com && echo password=secret; }; f`, "ann@example.com", "secret"},
+		// git asks for credentials the helper doesn't have, which must not block or fail
+		{`!true`, "", ""},
+	}
+
+	for _, test := range tests {
+		provider := &credentials.HelperProvider{Helper: test.helper}
+
+		username, _, err := provider.Get(credentials.Request{Integration: "jira", Field: email, Host: "jira.example.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		password, _, err := provider.Get(credentials.Request{Integration: "jira", Field: token, Host: "jira.example.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if username != test.username || password != test.password {
+			t.Errorf("%s: expected %q and %q, got %q and %q", test.helper, test.username, test.password, username, password)
+		}
+	}
+}
+
+func TestHelperProviderDoesNotRunAskpassPrograms(t *testing.T) {
+	dir := t.TempDir()
+
+	// an askpass program answers every prompt, as a graphical one would after asking the user
+	askpass := filepath.Join(dir, "askpass")
+	if err := os.WriteFile(askpass, []byte("#!/bin/sh\necho askpass\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	config := filepath.Join(dir, "gitconfig")
+	if err := os.WriteFile(config, []byte("[core]\n\taskPass = "+askpass+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("GIT_CONFIG_GLOBAL", config)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("SSH_ASKPASS", askpass)
+
+	provider := &credentials.HelperProvider{Helper: `!true`}
+	token := credentials.Field{Name: "token", Kind: credentials.KindPassword}
+
+	password, _, err := provider.Get(credentials.Request{Integration: "jira", Field: token, Host: "jira.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if password != "" {
+		t.Errorf("expected no password without asking, got %q", password)
+	}
+}
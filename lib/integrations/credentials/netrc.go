@@ -0,0 +1,135 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// NetrcProvider reads usernames and passwords from a .netrc file, using the entry of the integration's host
+type NetrcProvider struct {
+	FileName string
+}
+
+// DefaultNetrcFileName returns the file named by $NETRC, or ~/.netrc (~/_netrc on Windows).
+func DefaultNetrcFileName() string {
+	if fileName := os.Getenv("NETRC"); fileName != "" {
+		return fileName
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = os.Getenv("HOME")
+	}
+
+	if runtime.GOOS == "windows" {
+		return filepath.Join(home, "_netrc")
+	}
+
+	return filepath.Join(home, ".netrc")
+}
+
+func (p NetrcProvider) Name() string {
+	return p.FileName
+}
+
+func (p NetrcProvider) Get(req Request) (string, string, error) {
+	if req.Host == "" || req.Field.Kind == KindSetting {
+		return "", "", nil
+	}
+
+	data, err := os.ReadFile(p.FileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	entry, ok := findNetrcEntry(string(data), req.Host)
+	if !ok {
+		return "", "", nil
+	}
+
+	value := entry.login
+	if req.Field.Kind == KindPassword {
+		value = entry.password
+	}
+
+	return value, fmt.Sprintf("%s (machine %s)", p.FileName, entry.machine), nil
+}
+
+type netrcEntry struct {
+	machine  string
+	login    string
+	password string
+}
+
+// findNetrcEntry returns the entry for host, or the default entry, from the contents of a .netrc file.
+func findNetrcEntry(contents string, host string) (netrcEntry, bool) {
+	var entries []netrcEntry
+	var current *netrcEntry
+
+	lines := strings.Split(contents, "\n")
+
+	for i := 0; i < len(lines); i++ {
+		fields := strings.Fields(lines[i])
+
+		for j := 0; j < len(fields); j++ {
+			if strings.HasPrefix(fields[j], "#") {
+				break
+			}
+
+			value := ""
+			if j+1 < len(fields) {
+				value = fields[j+1]
+			}
+
+			switch fields[j] {
+			case "machine":
+				entries = append(entries, netrcEntry{machine: value})
+				current = &entries[len(entries)-1]
+				j++
+			case "default":
+				entries = append(entries, netrcEntry{machine: "default"})
+				current = &entries[len(entries)-1]
+			case "login":
+				if current != nil {
+					current.login = value
+				}
+				j++
+			case "password":
+				if current != nil {
+					current.password = value
+				}
+				j++
+			case "account":
+				j++
+			case "macdef":
+				// a macro definition runs until the next empty line
+				for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+					i++
+				}
+				current = nil
+				j = len(fields)
+			}
+		}
+	}
+
+	for _, entry := range entries {
+		if strings.EqualFold(entry.machine, host) {
+			return entry, true
+		}
+	}
+
+	for _, entry := range entries {
+		if entry.machine == "default" {
+			return entry, true
+		}
+	}
+
+	return netrcEntry{}, false
+}
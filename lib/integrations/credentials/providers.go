@@ -0,0 +1,124 @@
+package credentials
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// EnvProvider reads credentials from the environment variables listed by each field
+type EnvProvider struct{}
+
+func (EnvProvider) Name() string {
+	return "environment"
+}
+
+func (EnvProvider) Get(req Request) (string, string, error) {
+	for _, name := range req.Field.EnvVars {
+		if value := os.Getenv(name); value != "" {
+			return value, "environment variable " + name, nil
+		}
+	}
+
+	return "", "", nil
+}
+
+// GitConfigProvider reads credentials from git config keys such as ninja.jira.token. Field names are used
+// without underscores, since git config keys may not contain them, e.g. ninja.jira.baseurl for "base_url".
+type GitConfigProvider struct {
+	// Dir is the directory git is run in, which determines the repository whose config is included
+	Dir string
+}
+
+func (GitConfigProvider) Name() string {
+	return "git config"
+}
+
+// GitConfigKey returns the git config key of a field.
+func GitConfigKey(integration string, field string) string {
+	return "ninja." + integration + "." + strings.ReplaceAll(field, "_", "")
+}
+
+func (p GitConfigProvider) Get(req Request) (string, string, error) {
+	key := GitConfigKey(req.Integration, req.Field.Name)
+
+	cmd := exec.Command("git", "config", "--get", key)
+	cmd.Dir = p.Dir
+
+	output, err := cmd.Output()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		// the key is not set
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	return strings.TrimSpace(string(output)), "git config " + key, nil
+}
+
+// FileProvider reads credentials from a JSON file with a section per integration, e.g.
+// {"jira": {"email": "...", "token": "..."}}. The file must not be accessible by other users.
+type FileProvider struct {
+	FileName string
+}
+
+func (p FileProvider) Name() string {
+	return p.FileName
+}
+
+func (p FileProvider) Get(req Request) (string, string, error) {
+	info, err := os.Stat(p.FileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	// Windows does not use Unix permission bits; the file is protected by the user profile's ACLs instead
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return "", "", fmt.Errorf("%w (mode %04o), run: chmod 600 %s", ErrInsecureFile, info.Mode().Perm(), p.FileName)
+	}
+
+	data, err := os.ReadFile(p.FileName)
+	if err != nil {
+		return "", "", err
+	}
+
+	var sections map[string]map[string]string
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return "", "", fmt.Errorf("failed to parse credentials file: %v", err)
+	}
+
+	if value := sections[req.Integration][req.Field.Name]; value != "" {
+		return value, p.FileName, nil
+	}
+
+	return "", "", nil
+}
+
+// SettingsProvider supplies values from the configuration file, keyed by integration and field name. It is
+// meant for settings such as base URLs; secrets belong in one of the other sources.
+type SettingsProvider struct {
+	FileName string
+	Values   map[string]map[string]string
+}
+
+func (p SettingsProvider) Name() string {
+	return p.FileName
+}
+
+func (p SettingsProvider) Get(req Request) (string, string, error) {
+	if value := p.Values[req.Integration][req.Field.Name]; value != "" {
+		return value, fmt.Sprintf("%s (%s.%s)", p.FileName, req.Integration, req.Field.Name), nil
+	}
+
+	return "", "", nil
+}
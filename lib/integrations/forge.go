@@ -0,0 +1,192 @@
+package integrations
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// States of pull requests
+const (
+	PullRequestOpen   = "open"
+	PullRequestMerged = "merged"
+	PullRequestClosed = "closed"
+)
+
+// Review states of pull requests
+const (
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes_requested"
+	ReviewRequired         = "review_required"
+)
+
+// Check states of pull requests, combining the results of all checks
+const (
+	ChecksPassing = "passing"
+	ChecksFailing = "failing"
+	ChecksPending = "pending"
+)
+
+// PullRequest is a pull request, or merge request, on a forge such as GitHub
+type PullRequest struct {
+	Number int `json:"number"`
+	// Reference is how the forge refers to the pull request, e.g. "!12" for merge requests; "#" and the number
+	// are used when it is empty
+	Reference string `json:"reference,omitempty"`
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	// Branch is the name of the pull request's source branch
+	Branch string `json:"branch"`
+	// Base is the name of the branch the pull request merges into
+	Base string `json:"base"`
+	// State is PullRequestOpen, PullRequestMerged or PullRequestClosed
+	State string `json:"state"`
+	Draft bool   `json:"draft"`
+	// HeadSHA is the commit the pull request's source branch pointed to when it was last updated
+	HeadSHA string `json:"head_sha"`
+	// Review is ReviewApproved, ReviewChangesRequested, ReviewRequired, or empty when no review was requested
+	Review string `json:"review"`
+	// Checks is ChecksPassing, ChecksFailing, ChecksPending, or empty when there are no checks
+	Checks  string    `json:"checks"`
+	Updated time.Time `json:"updated"`
+}
+
+// Ref returns how the forge refers to the pull request, e.g. "#12".
+func (p PullRequest) Ref() string {
+	if p.Reference != "" {
+		return p.Reference
+	}
+
+	return fmt.Sprintf("#%d", p.Number)
+}
+
+// IsMerged reports whether the pull request was merged.
+func (p PullRequest) IsMerged() bool {
+	return p.State == PullRequestMerged
+}
+
+// IsOpen reports whether the pull request is open.
+func (p PullRequest) IsOpen() bool {
+	return p.State == PullRequestOpen
+}
+
+// Forge is a host of repositories with pull requests, such as GitHub
+type Forge interface {
+	// Name returns the name the forge is registered with, e.g. "github"
+	Name() string
+	// Validate reports why the forge can't be used, e.g. because credentials are missing
+	Validate() error
+	// PullRequests returns the pull request of each of the branches that has one, keyed by branch name. When a
+	// branch has several, the open one is returned, or else the most recently updated one. Pull requests whose
+	// review or check state could not be fetched are returned without it, along with the error.
+	PullRequests(branches []string) (map[string]*PullRequest, error)
+	// CreatePullRequest opens a pull request and returns it
+	CreatePullRequest(pr NewPullRequest) (*PullRequest, error)
+}
+
+// NewPullRequest describes a pull request to open
+type NewPullRequest struct {
+	Title string
+	// Body is the description of the pull request, in Markdown
+	Body string
+	// Branch is the name of the source branch, which must have been pushed to the forge's repository
+	Branch string
+	// Base is the name of the branch the pull request merges into
+	Base  string
+	Draft bool
+}
+
+// Repository identifies a repository on a forge
+type Repository struct {
+	// Host is the host name of the forge, e.g. "github.com"
+	Host string
+	// Owner is the user, organization or group owning the repository; it contains slashes for nested groups
+	Owner string
+	Name  string
+}
+
+// FullName returns the owner and name of the repository, e.g. "permafrost-dev/git-ninja".
+func (r Repository) FullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+// scpLikeURL matches remote URLs such as "git@github.com:owner/repo.git"
+var scpLikeURL = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// ParseRemoteURL returns the repository of a git remote URL such as "https://github.com/owner/repo.git",
+// "ssh://git@github.com/owner/repo.git" or "git@github.com:owner/repo.git".
+func ParseRemoteURL(remoteURL string) (Repository, error) {
+	var host, path string
+
+	if parsed, err := url.Parse(remoteURL); err == nil && parsed.Scheme != "" && parsed.Host != "" {
+		host, path = parsed.Hostname(), parsed.Path
+	} else if match := scpLikeURL.FindStringSubmatch(remoteURL); match != nil {
+		host, path = match[1], match[2]
+	} else {
+		return Repository{}, fmt.Errorf("unsupported remote URL '%s'", remoteURL)
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+
+	idx := strings.LastIndex(path, "/")
+	if idx <= 0 || idx == len(path)-1 {
+		return Repository{}, fmt.Errorf("remote URL '%s' does not name an owner and repository", remoteURL)
+	}
+
+	return Repository{Host: strings.ToLower(host), Owner: path[:idx], Name: path[idx+1:]}, nil
+}
+
+// ForgeFactory creates a forge for a repository from the current configuration
+type ForgeFactory func(repo Repository) Forge
+
+// forgeEntry is a registered forge
+type forgeEntry struct {
+	factory ForgeFactory
+	// matches reports whether the forge hosts repositories on a host
+	matches func(host string) bool
+}
+
+var forges = make(map[string]forgeEntry)
+
+// RegisterForge makes a forge available under a name, such as "github". matches reports whether the forge
+// hosts the repositories of a host, e.g. "github.com", and is used to detect the forge of a remote.
+func RegisterForge(name string, matches func(host string) bool, factory ForgeFactory) {
+	forges[strings.ToLower(name)] = forgeEntry{factory: factory, matches: matches}
+}
+
+// ForgeNames returns the names of the registered forges, sorted.
+func ForgeNames() []string {
+	result := make([]string, 0, len(forges))
+	for name := range forges {
+		result = append(result, name)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// NewForge creates the forge registered under name for a repository. When name is empty, the forge is
+// detected from the repository's host. The forge may still be unusable, e.g. without credentials, which its
+// Validate method reports.
+func NewForge(name string, repo Repository) (Forge, error) {
+	if name != "" {
+		entry, ok := forges[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown forge '%s', expected one of: %s", name, strings.Join(ForgeNames(), ", "))
+		}
+
+		return entry.factory(repo), nil
+	}
+
+	for _, name := range ForgeNames() {
+		if forges[name].matches(repo.Host) {
+			return forges[name].factory(repo), nil
+		}
+	}
+
+	return nil, fmt.Errorf("no forge is known for %s; set forge.provider to one of: %s", repo.Host, strings.Join(ForgeNames(), ", "))
+}
@@ -0,0 +1,65 @@
+// Package gitea is a client for the REST API of Gitea and Forgejo instances, which share it, used to find and open
+// the pull requests of branches.
+package gitea
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/internal/rest"
+)
+
+// Client makes requests to the REST API of a Gitea or Forgejo instance. Its cache stores pull requests and their
+// review and check states.
+type Client struct {
+	*rest.Client
+}
+
+// Option configures a Client created by NewClient
+type Option = rest.Option
+
+// APIError is returned when the Gitea API responds with an unsuccessful status code
+type APIError = rest.APIError
+
+// Options of NewClient
+var (
+	// WithAPIURL replaces the API URL derived from the base URL passed to NewClient, e.g. with the URL of a test server.
+	WithAPIURL = rest.WithAPIURL
+	// WithHTTPClient makes requests with the given HTTP client.
+	WithHTTPClient = rest.WithHTTPClient
+	// WithTransport makes requests with an HTTP client using the given transport.
+	WithTransport = rest.WithTransport
+	// WithCacheDir caches data in dir instead of the default cache directory. An empty dir disables caching.
+	WithCacheDir = rest.WithCacheDir
+	// WithClock uses now instead of time.Now to get the current time, including for cache expiry.
+	WithClock = rest.WithClock
+)
+
+// provider describes the REST API of Gitea and Forgejo
+var provider = rest.Provider{
+	Name:     "Gitea",
+	CacheDir: "gitea",
+	Header: func(token string) http.Header {
+		return http.Header{
+			"Authorization": {"token " + token},
+			"Accept":        {"application/json"},
+		}
+	},
+	AccountField: "login",
+}
+
+// APIURL returns the URL of the REST API of the instance at baseURL, or an empty string when baseURL is empty.
+func APIURL(baseURL string) string {
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	if baseURL == "" || strings.Contains(baseURL, "/api/") {
+		return baseURL
+	}
+
+	return baseURL + "/api/v1"
+}
+
+// NewClient returns a client for the instance at baseURL (see APIURL), authenticating with an access token.
+func NewClient(baseURL, token string, options ...Option) *Client {
+	return &Client{Client: rest.NewClient(provider, APIURL(baseURL), token, options...)}
+}
@@ -0,0 +1,22 @@
+package gitea_test
+
+import (
+	"testing"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/gitea"
+)
+
+func TestAPIURL(t *testing.T) {
+	tests := map[string]string{
+		"":                                "",
+		"https://git.example.com/":        "https://git.example.com/api/v1",
+		"https://example.com/forgejo":     "https://example.com/forgejo/api/v1",
+		"https://git.example.com/api/v1/": "https://git.example.com/api/v1",
+	}
+
+	for baseURL, want := range tests {
+		if got := gitea.APIURL(baseURL); got != want {
+			t.Errorf("expected %s for %q, got %s", want, baseURL, got)
+		}
+	}
+}
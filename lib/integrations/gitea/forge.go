@@ -0,0 +1,117 @@
+package gitea
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+)
+
+// Forge makes a Client usable as an integrations.Forge for a repository
+type Forge struct {
+	Client *Client
+	Repo   integrations.Repository
+}
+
+// NewForge returns a forge for repo using client.
+func NewForge(client *Client, repo integrations.Repository) *Forge {
+	return &Forge{Client: client, Repo: repo}
+}
+
+func (f *Forge) Name() string {
+	return "gitea"
+}
+
+func (f *Forge) Validate() error {
+	return f.Client.Validate()
+}
+
+func (f *Forge) PullRequests(branches []string) (map[string]*integrations.PullRequest, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	repo := f.Repo.FullName()
+
+	pulls, err := f.Client.PullRequests(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*integrations.PullRequest)
+	var errs []error
+
+	for _, branch := range branches {
+		pr := BranchPullRequest(pulls, repo, branch)
+		if pr == nil {
+			continue
+		}
+
+		converted := forgePullRequest(*pr)
+
+		// reviews and checks only matter while the pull request is open
+		if converted.IsOpen() {
+			status, err := f.Client.PullRequestStatus(repo, *pr)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to fetch the status of pull request #%d: %w", pr.Number, err))
+			}
+
+			if status != nil {
+				converted.Review, converted.Checks = reviewStates[status.Review], checkStates[status.Checks]
+			}
+		}
+
+		result[branch] = &converted
+	}
+
+	return result, errors.Join(errs...)
+}
+
+func (f *Forge) CreatePullRequest(pr integrations.NewPullRequest) (*integrations.PullRequest, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	created, err := f.Client.CreatePullRequest(f.Repo.FullName(), NewPullRequest{
+		Title: pr.Title,
+		Body:  pr.Body,
+		Head:  pr.Branch,
+		Base:  pr.Base,
+		Draft: pr.Draft,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	converted := forgePullRequest(*created)
+
+	return &converted, nil
+}
+
+// reviewStates maps Gitea's review states to those of the integrations package
+var reviewStates = map[string]string{
+	ReviewApproved:         integrations.ReviewApproved,
+	ReviewChangesRequested: integrations.ReviewChangesRequested,
+	ReviewRequired:         integrations.ReviewRequired,
+}
+
+// checkStates maps Gitea's check states to those of the integrations package
+var checkStates = map[string]string{
+	ChecksPassing: integrations.ChecksPassing,
+	ChecksFailing: integrations.ChecksFailing,
+	ChecksPending: integrations.ChecksPending,
+}
+
+func forgePullRequest(pr PullRequest) integrations.PullRequest {
+	return integrations.PullRequest{
+		Number:  pr.Number,
+		Title:   pr.Title,
+		URL:     pr.URL,
+		Branch:  pr.Head,
+		Base:    pr.Base,
+		State:   pr.State,
+		Draft:   pr.Draft,
+		HeadSHA: pr.HeadSHA,
+		Updated: pr.Updated,
+	}
+}
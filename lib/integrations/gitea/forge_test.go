@@ -0,0 +1,73 @@
+package gitea_test
+
+import (
+	"testing"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/gitea"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/gitea/giteatest"
+)
+
+// repo is the repository served by the fake server
+var repo = integrations.Repository{Host: "gitea.com", Owner: "octo", Name: "widgets"}
+
+func TestForgeChoosesThePullRequestOfEachBranch(t *testing.T) {
+	merged := giteatest.NewPullRequest(1, "feature")
+	merged.State = integrations.PullRequestMerged
+	open := giteatest.NewPullRequest(2, "feature")
+	fork := giteatest.NewPullRequest(3, "fix")
+	fork.HeadRepo = "someone/widgets"
+	closed := giteatest.NewPullRequest(4, "old")
+	closed.State = integrations.PullRequestClosed
+
+	server := giteatest.NewServer(merged, open, fork, closed)
+	defer server.Close()
+
+	result, err := gitea.NewForge(server.Client(), repo).PullRequests([]string{"feature", "fix", "old", "none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 2 || result["feature"].Number != 2 || !result["feature"].IsOpen() {
+		t.Errorf("expected open #2 for feature, got %+v", result["feature"])
+	}
+
+	if result["old"] == nil || result["old"].State != integrations.PullRequestClosed {
+		t.Errorf("expected closed #4 for old, got %+v", result["old"])
+	}
+}
+
+func TestForgeReportsReviewAndCheckStates(t *testing.T) {
+	approved := giteatest.NewPullRequest(1, "approved")
+	approved.Reviews = []giteatest.Review{{User: "ann", State: "REQUEST_CHANGES"}, {User: "bob", State: "COMMENT"}, {User: "ann", State: "APPROVED"}}
+	approved.Status = "warning"
+
+	changes := giteatest.NewPullRequest(2, "changes")
+	changes.Reviews = []giteatest.Review{{User: "ann", State: "APPROVED"}, {User: "bob", State: "REQUEST_CHANGES"}}
+	changes.Status = "pending"
+
+	waiting := giteatest.NewPullRequest(3, "waiting")
+	waiting.Reviews = []giteatest.Review{{User: "bob", State: "REQUEST_CHANGES", Dismissed: true}}
+	waiting.Reviewers = []string{"ann"}
+	waiting.Status = "failure"
+
+	server := giteatest.NewServer(approved, changes, waiting)
+	defer server.Close()
+
+	result, err := gitea.NewForge(server.Client(), repo).PullRequests([]string{"approved", "changes", "waiting"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][2]string{
+		"approved": {integrations.ReviewApproved, integrations.ChecksPassing},
+		"changes":  {integrations.ReviewChangesRequested, integrations.ChecksPending},
+		"waiting":  {integrations.ReviewRequired, integrations.ChecksFailing},
+	}
+
+	for branch, states := range want {
+		if pr := result[branch]; pr == nil || pr.Review != states[0] || pr.Checks != states[1] {
+			t.Errorf("expected %s to be %v, got %+v", branch, states, pr)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+// Package giteatest provides a fake Gitea REST API server for testing code that uses the gitea package without
+// contacting a Gitea or Forgejo instance.
+package giteatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/cache/cachetest"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/gitea"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/internal/apitest"
+)
+
+// Token is the token accepted by a Server unless it is changed
+const Token = "gitea-test"
+
+// Login is the login of the account of the token accepted by a Server unless it is changed
+const Login = "octocat"
+
+// Repo is the full name of the repository served by a Server unless it is changed
+const Repo = "octo/widgets"
+
+// Review is a review of a pull request
+type Review struct {
+	User string
+	// State is "APPROVED", "REQUEST_CHANGES", "COMMENT" or "PENDING"
+	State     string
+	Dismissed bool
+}
+
+// PullRequest is a pull request served by a Server, with its reviews and the combined status of its head commit
+type PullRequest struct {
+	gitea.PullRequest
+	// Body is the description the pull request was created with
+	Body    string
+	Reviews []Review
+	// Status is the combined commit status of the head commit, e.g. "success" or "pending"; there are no
+	// statuses when it is empty
+	Status string
+	// Reviewers lists the users still requested to review the pull request
+	Reviewers []string
+}
+
+// Server is a fake Gitea instance serving the pull requests of a single repository, their reviews, and the
+// combined statuses of their head commits, with pagination. Pull requests can be created too. It accepts the
+// token Token in the "token" authorization scheme. Paths may start with "/api/v1", so that both the instance URL
+// and the API URL can point to the server.
+type Server struct {
+	*apitest.Server
+
+	// Repo is the full name of the repository, e.g. "octo/widgets"
+	Repo string
+	// PullRequests are the pull requests served
+	PullRequests []PullRequest
+	// PageSize caps the number of items in a page of results
+	PageSize int
+	// Token is the accepted token
+	Token string
+	// Login is the login of the account the token belongs to
+	Login string
+}
+
+// NewServer starts a server serving the given pull requests. Call Close when done.
+func NewServer(pulls ...PullRequest) *Server {
+	s := &Server{Repo: Repo, PullRequests: pulls, PageSize: 50, Token: Token, Login: Login}
+	s.Server = apitest.NewServer(s.handle)
+
+	return s
+}
+
+// NewPullRequest returns an open pull request of a branch of the server's default repository into main, with a
+// head commit named after the number.
+func NewPullRequest(number int, branch string) PullRequest {
+	return PullRequest{PullRequest: gitea.PullRequest{
+		Number:   number,
+		Title:    fmt.Sprintf("Pull request %d", number),
+		State:    integrations.PullRequestOpen,
+		Head:     branch,
+		HeadRepo: Repo,
+		HeadSHA:  fmt.Sprintf("%040d", number),
+		Base:     "main",
+		Updated:  time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC).Add(time.Duration(number) * time.Minute),
+	}}
+}
+
+// Client returns a client for the server, with caching disabled unless an option enables it.
+func (s *Server) Client(options ...gitea.Option) *gitea.Client {
+	return gitea.NewClient(s.URL, s.Token, append([]gitea.Option{gitea.WithCacheDir("")}, options...)...)
+}
+
+// CachedClient returns a client for the server caching in a temporary directory of the test, with a clock the
+// test advances to expire cached data.
+func (s *Server) CachedClient(tb testing.TB, options ...gitea.Option) (*gitea.Client, *cachetest.Clock) {
+	cached, clock := apitest.CachedOptions(tb, gitea.WithCacheDir, gitea.WithClock)
+
+	return s.Client(append(cached, options...)...), clock
+}
+
+// Update changes a pull request while the server is running.
+func (s *Server) Update(number int, update func(pr *PullRequest)) {
+	s.Lock()
+	defer s.Unlock()
+
+	if pr := s.findPullRequest(number); pr != nil {
+		update(pr)
+	}
+}
+
+func (s *Server) findPullRequest(number int) *PullRequest {
+	for i := range s.PullRequests {
+		if s.PullRequests[i].Number == number {
+			return &s.PullRequests[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	prefix := "/repos/" + s.Repo
+	apiPath := strings.TrimPrefix(r.URL.Path, "/api/v1")
+	path, ok := strings.CutPrefix(apiPath, prefix)
+	if apiPath == "/user" {
+		path, ok = apiPath, true
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, "The target couldn't be found.")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	// count requests by endpoint, without the pull request number or commit
+	endpoint := "/" + parts[0]
+	if len(parts) > 2 {
+		endpoint += "/" + parts[2]
+	}
+	if status := s.Receive(r.Method + " " + endpoint); status != 0 {
+		writeError(w, status, "simulated failure")
+		return
+	}
+
+	if r.Header.Get("Authorization") != "token "+s.Token {
+		writeError(w, http.StatusUnauthorized, "user does not exist [uid: 0, name: ]")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && apiPath == "/user":
+		writeJSON(w, http.StatusOK, map[string]any{"login": s.Login})
+	case r.Method == http.MethodGet && len(parts) == 1 && parts[0] == "pulls":
+		s.listPullRequests(w, r)
+	case r.Method == http.MethodPost && len(parts) == 1 && parts[0] == "pulls":
+		s.createPullRequest(w, r)
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[0] == "pulls" && parts[2] == "reviews":
+		number, _ := strconv.Atoi(parts[1])
+		pr := s.findPullRequest(number)
+		if pr == nil {
+			writeError(w, http.StatusNotFound, "The target couldn't be found.")
+			return
+		}
+
+		reviews := make([]any, 0, len(pr.Reviews))
+		for i, review := range pr.Reviews {
+			reviews = append(reviews, map[string]any{
+				"id":        i + 1,
+				"state":     review.State,
+				"dismissed": review.Dismissed,
+				"user":      map[string]any{"login": review.User},
+			})
+		}
+		s.writePage(w, r, reviews)
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[0] == "commits" && parts[2] == "status":
+		state, statuses := "", make([]any, 0)
+		if pr := s.findCommit(parts[1]); pr != nil && pr.Status != "" {
+			state = pr.Status
+			statuses = append(statuses, map[string]any{"id": 1, "status": pr.Status, "context": "ci"})
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{"state": state, "sha": parts[1], "total_count": len(statuses), "statuses": statuses})
+	default:
+		writeError(w, http.StatusNotFound, "The target couldn't be found.")
+	}
+}
+
+func (s *Server) findCommit(sha string) *PullRequest {
+	for i := range s.PullRequests {
+		if s.PullRequests[i].HeadSHA == sha {
+			return &s.PullRequests[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) listPullRequests(w http.ResponseWriter, r *http.Request) {
+	state := r.URL.Query().Get("state")
+
+	pulls := make([]PullRequest, 0, len(s.PullRequests))
+	for _, pr := range s.PullRequests {
+		open := pr.State == integrations.PullRequestOpen
+		if state == "all" || (state == "closed" && !open) || ((state == "" || state == "open") && open) {
+			pulls = append(pulls, pr)
+		}
+	}
+
+	sort.SliceStable(pulls, func(i, j int) bool {
+		return pulls[i].Updated.After(pulls[j].Updated)
+	})
+
+	items := make([]any, 0, len(pulls))
+	for _, pr := range pulls {
+		items = append(items, s.pullResponse(pr))
+	}
+
+	s.writePage(w, r, items)
+}
+
+// createPullRequest opens a pull request, rejecting it like Gitea when a field is missing or the branch already
+// has an open pull request into the same base.
+func (s *Server) createPullRequest(w http.ResponseWriter, r *http.Request) {
+	var request gitea.NewPullRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "invalid JSON")
+		return
+	}
+
+	if request.Title == "" || request.Head == "" || request.Base == "" {
+		writeError(w, http.StatusUnprocessableEntity, "[Title]: Required")
+		return
+	}
+
+	number := 1
+	for _, pr := range s.PullRequests {
+		if pr.Head == request.Head && pr.Base == request.Base && pr.State == integrations.PullRequestOpen {
+			writeError(w, http.StatusConflict, fmt.Sprintf("pull request already exists for these targets [id: %d, issue_id: %d, head_repo_id: 1, base_repo_id: 1, head_branch: %s, base_branch: %s]",
+				pr.Number, pr.Number, pr.Head, pr.Base))
+			return
+		}
+
+		number = max(number, pr.Number+1)
+	}
+
+	pr := NewPullRequest(number, request.Head)
+	pr.Title, pr.Body, pr.Base, pr.HeadRepo = request.Title, request.Body, request.Base, s.Repo
+	pr.Draft = gitea.IsWorkInProgress(request.Title)
+	s.PullRequests = append(s.PullRequests, pr)
+
+	writeJSON(w, http.StatusCreated, s.pullResponse(pr))
+}
+
+// writePage writes the page of items selected by the page and limit parameters, with the total number of items in
+// the X-Total-Count header.
+func (s *Server) writePage(w http.ResponseWriter, r *http.Request, items []any) {
+	page, _ := s.Page(w, r, items, "limit", s.PageSize)
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(items)))
+	writeJSON(w, http.StatusOK, page)
+}
+
+// pullResponse returns a pull request in the structure used by Gitea's API responses.
+func (s *Server) pullResponse(pr PullRequest) map[string]any {
+	state, merged := pr.State, false
+	var mergedAt any
+	if pr.State == integrations.PullRequestMerged {
+		state, merged, mergedAt = "closed", true, pr.Updated.Format(time.RFC3339)
+	}
+
+	reviewers := make([]any, 0, len(pr.Reviewers))
+	for _, login := range pr.Reviewers {
+		reviewers = append(reviewers, map[string]any{"login": login})
+	}
+
+	return map[string]any{
+		"number":              pr.Number,
+		"title":               pr.Title,
+		"body":                pr.Body,
+		"html_url":            fmt.Sprintf("%s/%s/pulls/%d", s.URL, s.Repo, pr.Number),
+		"state":               state,
+		"merged":              merged,
+		"merged_at":           mergedAt,
+		"updated_at":          pr.Updated.Format(time.RFC3339),
+		"head":                map[string]any{"ref": pr.Head, "sha": pr.HeadSHA, "repo": map[string]any{"full_name": pr.HeadRepo}},
+		"base":                map[string]any{"ref": pr.Base},
+		"requested_reviewers": reviewers,
+	}
+}
+
+// writeJSON and writeError write the responses of the fake API
+var (
+	writeJSON  = apitest.WriteJSON
+	writeError = apitest.WriteError
+)
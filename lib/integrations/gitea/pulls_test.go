@@ -0,0 +1,70 @@
+package gitea_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/gitea"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/gitea/giteatest"
+)
+
+func TestPullRequestsFollowsPages(t *testing.T) {
+	pulls := make([]giteatest.PullRequest, 0)
+	for i := 1; i <= 320; i++ {
+		pulls = append(pulls, giteatest.NewPullRequest(i, fmt.Sprintf("branch-%d", i)))
+	}
+
+	server := giteatest.NewServer(pulls...)
+	defer server.Close()
+
+	result, err := server.Client().PullRequests(giteatest.Repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 320 || result[0].Number != 320 {
+		t.Errorf("expected 320 pull requests, most recent first, got %d", len(result))
+	}
+
+	if count := server.RequestCount("GET /pulls"); count != 7 {
+		t.Errorf("expected 7 requests, got %d", count)
+	}
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	server := giteatest.NewServer(giteatest.NewPullRequest(1, "feature"))
+	defer server.Close()
+
+	client, _ := server.CachedClient(t)
+	forge := gitea.NewForge(client, repo)
+	forge.PullRequests([]string{"feature", "fix"})
+
+	created, err := forge.CreatePullRequest(integrations.NewPullRequest{Title: "Fix it", Body: "- Fix it", Branch: "fix", Base: "main", Draft: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.Number != 2 || !created.Draft || created.Title != "WIP: Fix it" {
+		t.Errorf("expected draft #2, got %+v", created)
+	}
+
+	// the new pull request is found in the cached pull requests without fetching them again
+	result, err := forge.PullRequests([]string{"fix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result["fix"] == nil || result["fix"].Number != 2 || server.RequestCount("GET /pulls") != 1 {
+		t.Errorf("expected cached #2 for fix, got %+v", result["fix"])
+	}
+
+	_, err = forge.CreatePullRequest(integrations.NewPullRequest{Title: "Again", Branch: "feature", Base: "main"})
+
+	var apiErr *gitea.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		t.Errorf("expected a 409 APIError for a duplicate, got %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Review states of pull requests
+const (
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes_requested"
+	ReviewRequired         = "review_required"
+)
+
+// Check states of commits, combining their commit statuses, which include those of Gitea Actions
+const (
+	ChecksPassing = "passing"
+	ChecksFailing = "failing"
+	ChecksPending = "pending"
+)
+
+// Status is the review and check state of a pull request
+type Status struct {
+	// Review is ReviewApproved, ReviewChangesRequested, ReviewRequired, or empty when no review was requested
+	Review string `json:"review"`
+	// Checks is ChecksPassing, ChecksFailing, ChecksPending, or empty when there are no checks
+	Checks string `json:"checks"`
+}
+
+// statusCacheEntry is the status of a pull request at a head commit
+type statusCacheEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	HeadSHA   string    `json:"head_sha"`
+	Status    Status    `json:"status"`
+}
+
+// statusCacheName is the name of the cache file holding the statuses of pull requests, keyed by repository and number
+const statusCacheName = "statuses"
+
+// PullRequestStatus returns the review and check state of a pull request of repo, e.g. "owner/repo". Statuses are
+// cached for the cache's TTL, or until the pull request's head commit changes.
+func (c *Client) PullRequestStatus(repo string, pr PullRequest) (*Status, error) {
+	key := repo + "#" + strconv.Itoa(pr.Number)
+	now := c.Time()
+
+	cached := make(map[string]*statusCacheEntry)
+	if c.Cache != nil {
+		c.Cache.Read(c.CacheName(statusCacheName), &cached)
+
+		entry, ok := cached[key]
+		if ok && entry.HeadSHA == pr.HeadSHA && (c.Cache.TTL == 0 || now.Sub(entry.Timestamp) < c.Cache.TTL) {
+			return &entry.Status, nil
+		}
+	}
+
+	status, err := c.fetchStatus(repo, pr)
+	if err != nil {
+		// fall back to a stale status of the same commit rather than failing
+		if entry, ok := cached[key]; ok && entry.HeadSHA == pr.HeadSHA && now.Sub(entry.Timestamp) < c.Cache.TTL+c.Cache.MaxStale {
+			return &entry.Status, err
+		}
+		return nil, err
+	}
+
+	if c.Cache != nil {
+		// proceed without failing if the cache can't be written, as we have the status
+		c.Cache.Update(c.CacheName(statusCacheName), statusCacheName, &cached, func() error {
+			for k, entry := range cached {
+				if c.Cache.TTL > 0 && now.Sub(entry.Timestamp) >= c.Cache.TTL+c.Cache.MaxStale {
+					delete(cached, k)
+				}
+			}
+
+			cached[key] = &statusCacheEntry{Timestamp: now, HeadSHA: pr.HeadSHA, Status: *status}
+
+			return nil
+		})
+	}
+
+	return status, nil
+}
+
+// fetchStatus fetches the reviews of a pull request and the combined status of its head commit.
+func (c *Client) fetchStatus(repo string, pr PullRequest) (*Status, error) {
+	review, err := c.fetchReviewState(repo, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _, err := c.Get(c.repoURL(repo, "/commits/"+pr.HeadSHA+"/status"))
+	if err != nil {
+		return nil, err
+	}
+
+	var combined struct {
+		State      string `json:"state"`
+		TotalCount int    `json:"total_count"`
+	}
+	if err := json.Unmarshal(body, &combined); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+
+	status := &Status{Review: review}
+	if combined.TotalCount > 0 {
+		switch combined.State {
+		case "success", "warning":
+			status.Checks = ChecksPassing
+		case "pending":
+			status.Checks = ChecksPending
+		default:
+			status.Checks = ChecksFailing
+		}
+	}
+
+	return status, nil
+}
+
+// fetchReviewState returns the review state of a pull request, based on the latest review of each reviewer that
+// wasn't dismissed. Requested changes take precedence over approvals.
+func (c *Client) fetchReviewState(repo string, pr PullRequest) (string, error) {
+	latest := make(map[string]string)
+
+	next := c.repoURL(repo, "/pulls/"+strconv.Itoa(pr.Number)+"/reviews?limit=50")
+	for next != "" {
+		body, nextPage, err := c.Get(next)
+		if err != nil {
+			return "", err
+		}
+
+		var reviews []struct {
+			State     string `json:"state"`
+			Dismissed bool   `json:"dismissed"`
+			User      *struct {
+				Login string `json:"login"`
+			} `json:"user"`
+		}
+		if err := json.Unmarshal(body, &reviews); err != nil {
+			return "", fmt.Errorf("failed to parse JSON response: %v", err)
+		}
+
+		for _, review := range reviews {
+			if review.User == nil {
+				continue
+			}
+
+			// comments and pending reviews don't change a reviewer's verdict
+			switch {
+			case review.Dismissed:
+				delete(latest, review.User.Login)
+			case review.State == "APPROVED" || review.State == "REQUEST_CHANGES":
+				latest[review.User.Login] = review.State
+			}
+		}
+
+		next = nextPage
+	}
+
+	approved := false
+	for _, state := range latest {
+		switch state {
+		case "REQUEST_CHANGES":
+			return ReviewChangesRequested, nil
+		case "APPROVED":
+			approved = true
+		}
+	}
+
+	switch {
+	case approved:
+		return ReviewApproved, nil
+	case pr.ReviewRequested:
+		return ReviewRequired, nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,44 @@
+package gitea_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/gitea"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/gitea/giteatest"
+)
+
+func TestStatusesAreCachedUntilTheHeadCommitChanges(t *testing.T) {
+	pr := giteatest.NewPullRequest(1, "feature")
+	pr.Status = "pending"
+
+	server := giteatest.NewServer(pr)
+	defer server.Close()
+
+	client, clock := server.CachedClient(t)
+	forge := gitea.NewForge(client, repo)
+
+	forge.PullRequests([]string{"feature"})
+	forge.PullRequests([]string{"feature"})
+
+	if server.RequestCount("GET /pulls") != 1 || server.RequestCount("GET /commits/status") != 1 {
+		t.Errorf("expected one request of each kind before the TTL, got %v", server.Requests)
+	}
+
+	server.Update(1, func(pr *giteatest.PullRequest) {
+		pr.HeadSHA = fmt.Sprintf("%040d", 99)
+		pr.Status = "success"
+	})
+	clock.Advance(client.Cache.TTL + time.Second)
+
+	result, err := forge.PullRequests([]string{"feature"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result["feature"].Checks != integrations.ChecksPassing {
+		t.Errorf("expected the checks of the new head commit, got %+v", result["feature"])
+	}
+}
@@ -0,0 +1,82 @@
+// Package github is a client for the REST API of GitHub and GitHub Enterprise Server, used to find the pull
+// requests of branches.
+package github
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/internal/rest"
+)
+
+// DefaultAPIURL is the URL of the REST API of github.com
+const DefaultAPIURL = "https://api.github.com"
+
+// Client makes requests to the REST API of github.com or a GitHub Enterprise Server instance. Its cache stores pull
+// requests and their review and check states.
+type Client struct {
+	*rest.Client
+}
+
+// Option configures a Client created by NewClient
+type Option = rest.Option
+
+// APIError is returned when the GitHub API responds with an unsuccessful status code
+type APIError = rest.APIError
+
+// Options of NewClient
+var (
+	// WithAPIURL replaces the API URL derived from the base URL passed to NewClient, e.g. with the URL of a test server.
+	WithAPIURL = rest.WithAPIURL
+	// WithHTTPClient makes requests with the given HTTP client.
+	WithHTTPClient = rest.WithHTTPClient
+	// WithTransport makes requests with an HTTP client using the given transport.
+	WithTransport = rest.WithTransport
+	// WithCacheDir caches data in dir instead of the default cache directory. An empty dir disables caching.
+	WithCacheDir = rest.WithCacheDir
+	// WithClock uses now instead of time.Now to get the current time, including for cache expiry.
+	WithClock = rest.WithClock
+)
+
+// provider describes GitHub's REST API
+var provider = rest.Provider{
+	Name:     "GitHub",
+	CacheDir: "github",
+	Header: func(token string) http.Header {
+		return http.Header{
+			"Authorization":        {"Bearer " + token},
+			"Accept":               {"application/vnd.github+json"},
+			"X-Github-Api-Version": {"2022-11-28"},
+		}
+	},
+	AccountField: "login",
+}
+
+// APIURL returns the URL of the REST API of the GitHub instance at baseURL: that of github.com when baseURL
+// is empty or "https://github.com", or the "/api/v3" endpoint of a GitHub Enterprise Server instance.
+func APIURL(baseURL string) string {
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	if parsed, err := url.Parse(baseURL); baseURL == "" || (err == nil && IsDotCom(parsed.Hostname())) {
+		return DefaultAPIURL
+	}
+
+	if strings.Contains(baseURL, "/api/") {
+		return baseURL
+	}
+
+	return baseURL + "/api/v3"
+}
+
+// IsDotCom reports whether host is that of github.com or its API.
+func IsDotCom(host string) bool {
+	host = strings.ToLower(host)
+
+	return host == "github.com" || host == "api.github.com"
+}
+
+// NewClient returns a client for the GitHub instance at baseURL (see APIURL), authenticating with token.
+func NewClient(baseURL, token string, options ...Option) *Client {
+	return &Client{Client: rest.NewClient(provider, APIURL(baseURL), token, options...)}
+}
@@ -0,0 +1,22 @@
+package github_test
+
+import (
+	"testing"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/github"
+)
+
+func TestAPIURL(t *testing.T) {
+	tests := map[string]string{
+		"":                                   "https://api.github.com",
+		"https://github.com":                 "https://api.github.com",
+		"https://github.example.com/":        "https://github.example.com/api/v3",
+		"https://github.example.com/api/v3/": "https://github.example.com/api/v3",
+	}
+
+	for baseURL, want := range tests {
+		if got := github.APIURL(baseURL); got != want {
+			t.Errorf("expected %s for %q, got %s", want, baseURL, got)
+		}
+	}
+}
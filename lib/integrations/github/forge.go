@@ -0,0 +1,157 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+)
+
+// Forge makes a Client usable as an integrations.Forge for a repository
+type Forge struct {
+	Client *Client
+	Repo   integrations.Repository
+}
+
+// NewForge returns a forge for repo using client.
+func NewForge(client *Client, repo integrations.Repository) *Forge {
+	return &Forge{Client: client, Repo: repo}
+}
+
+func (f *Forge) Name() string {
+	return "github"
+}
+
+func (f *Forge) Validate() error {
+	return f.Client.Validate()
+}
+
+// lookupConcurrency limits the number of branches whose pull requests are looked up at the same time
+const lookupConcurrency = 8
+
+func (f *Forge) PullRequests(branches []string) (map[string]*integrations.PullRequest, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	result := make(map[string]*integrations.PullRequest)
+	errs := make([]error, 0)
+	reported := make(map[string]bool)
+	semaphore := make(chan struct{}, lookupConcurrency)
+
+	wg.Add(len(branches))
+
+	for _, branch := range branches {
+		go func(branch string) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			pr, err := f.branchPullRequest(branch)
+			<-semaphore
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if pr != nil {
+				result[branch] = pr
+			}
+
+			// failures such as an invalid token are the same for every branch and reported once
+			if err != nil && !reported[err.Error()] {
+				reported[err.Error()] = true
+				errs = append(errs, err)
+			}
+		}(branch)
+	}
+
+	wg.Wait()
+
+	return result, errors.Join(errs...)
+}
+
+// branchPullRequest returns the pull request of a branch, or nil, along with its review and check states while it
+// is open. The pull request is returned even when its states cannot be fetched.
+func (f *Forge) branchPullRequest(branch string) (*integrations.PullRequest, error) {
+	repo := f.Repo.FullName()
+
+	pulls, err := f.Client.PullRequests(repo, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	pr := BranchPullRequest(pulls, repo, branch)
+	if pr == nil {
+		return nil, nil
+	}
+
+	converted := forgePullRequest(*pr)
+
+	// reviews and checks only matter while the pull request is open
+	if !converted.IsOpen() {
+		return &converted, nil
+	}
+
+	status, err := f.Client.PullRequestStatus(repo, *pr)
+	if status != nil {
+		converted.Review, converted.Checks = reviewStates[status.Review], checkStates[status.Checks]
+	}
+
+	if err != nil {
+		return &converted, fmt.Errorf("failed to fetch the status of pull request #%d: %w", pr.Number, err)
+	}
+
+	return &converted, nil
+}
+
+func (f *Forge) CreatePullRequest(pr integrations.NewPullRequest) (*integrations.PullRequest, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	created, err := f.Client.CreatePullRequest(f.Repo.FullName(), NewPullRequest{
+		Title: pr.Title,
+		Body:  pr.Body,
+		Head:  pr.Branch,
+		Base:  pr.Base,
+		Draft: pr.Draft,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	converted := forgePullRequest(*created)
+
+	return &converted, nil
+}
+
+// reviewStates maps GitHub's review states to those of the integrations package
+var reviewStates = map[string]string{
+	ReviewApproved:         integrations.ReviewApproved,
+	ReviewChangesRequested: integrations.ReviewChangesRequested,
+	ReviewRequired:         integrations.ReviewRequired,
+}
+
+// checkStates maps GitHub's check states to those of the integrations package
+var checkStates = map[string]string{
+	ChecksPassing: integrations.ChecksPassing,
+	ChecksFailing: integrations.ChecksFailing,
+	ChecksPending: integrations.ChecksPending,
+}
+
+func forgePullRequest(pr PullRequest) integrations.PullRequest {
+	return integrations.PullRequest{
+		Number:  pr.Number,
+		Title:   pr.Title,
+		URL:     pr.URL,
+		Branch:  pr.Head,
+		Base:    pr.Base,
+		State:   pr.State,
+		Draft:   pr.Draft,
+		HeadSHA: pr.HeadSHA,
+		Updated: pr.Updated,
+	}
+}
@@ -0,0 +1,72 @@
+package github_test
+
+import (
+	"testing"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/github"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/github/githubtest"
+)
+
+// repo is the repository served by the fake server
+var repo = integrations.Repository{Host: "github.com", Owner: "octo", Name: "widgets"}
+
+func TestForgeChoosesThePullRequestOfEachBranch(t *testing.T) {
+	merged := githubtest.NewPullRequest(1, "feature")
+	merged.State = integrations.PullRequestMerged
+	open := githubtest.NewPullRequest(2, "feature")
+	fork := githubtest.NewPullRequest(3, "fix")
+	fork.HeadRepo = "someone/widgets"
+	closed := githubtest.NewPullRequest(4, "old")
+	closed.State = integrations.PullRequestClosed
+
+	server := githubtest.NewServer(merged, open, fork, closed)
+	defer server.Close()
+
+	result, err := github.NewForge(server.Client(), repo).PullRequests([]string{"feature", "fix", "old", "none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 2 || result["feature"].Number != 2 || !result["feature"].IsOpen() {
+		t.Errorf("expected open #2 for feature, got %+v", result["feature"])
+	}
+
+	if result["old"] == nil || result["old"].State != integrations.PullRequestClosed {
+		t.Errorf("expected closed #4 for old, got %+v", result["old"])
+	}
+}
+
+func TestForgeReportsReviewAndCheckStates(t *testing.T) {
+	approved := githubtest.NewPullRequest(1, "approved")
+	approved.Reviews = []githubtest.Review{{User: "ann", State: "CHANGES_REQUESTED"}, {User: "bob", State: "COMMENTED"}, {User: "ann", State: "APPROVED"}}
+	approved.Checks = []githubtest.Check{{Status: "completed", Conclusion: "success"}, {Status: "completed", Conclusion: "skipped"}}
+
+	changes := githubtest.NewPullRequest(2, "changes")
+	changes.Reviews = []githubtest.Review{{User: "ann", State: "APPROVED"}, {User: "bob", State: "CHANGES_REQUESTED"}}
+	changes.Checks = []githubtest.Check{{Status: "completed", Conclusion: "success"}, {Status: "in_progress"}}
+
+	waiting := githubtest.NewPullRequest(3, "waiting")
+	waiting.Reviewers = []string{"ann"}
+	waiting.Checks = []githubtest.Check{{Status: "in_progress"}, {Status: "completed", Conclusion: "failure"}}
+
+	server := githubtest.NewServer(approved, changes, waiting)
+	defer server.Close()
+
+	result, err := github.NewForge(server.Client(), repo).PullRequests([]string{"approved", "changes", "waiting"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][2]string{
+		"approved": {integrations.ReviewApproved, integrations.ChecksPassing},
+		"changes":  {integrations.ReviewChangesRequested, integrations.ChecksPending},
+		"waiting":  {integrations.ReviewRequired, integrations.ChecksFailing},
+	}
+
+	for branch, states := range want {
+		if pr := result[branch]; pr == nil || pr.Review != states[0] || pr.Checks != states[1] {
+			t.Errorf("expected %s to be %v, got %+v", branch, states, pr)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+// Package githubtest provides a fake GitHub REST API server for testing code that uses the github package
+// without contacting GitHub.
+package githubtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/cache/cachetest"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/github"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/internal/apitest"
+)
+
+// Token is the token accepted by a Server unless it is changed
+const Token = "ghp_test"
+
+// Login is the login of the account of the token accepted by a Server unless it is changed
+const Login = "octocat"
+
+// Repo is the full name of the repository served by a Server unless it is changed
+const Repo = "octo/widgets"
+
+// Review is a review of a pull request
+type Review struct {
+	User string
+	// State is "APPROVED", "CHANGES_REQUESTED", "COMMENTED" or "DISMISSED"
+	State string
+}
+
+// Check is a check run of a commit
+type Check struct {
+	// Status is "queued", "in_progress" or "completed"
+	Status string
+	// Conclusion is set for completed checks, e.g. "success" or "failure"
+	Conclusion string
+}
+
+// PullRequest is a pull request served by a Server, with its reviews and the check runs of its head commit
+type PullRequest struct {
+	github.PullRequest
+	// Body is the description the pull request was created with
+	Body    string
+	Reviews []Review
+	Checks  []Check
+	// Reviewers lists the users still requested to review the pull request
+	Reviewers []string
+}
+
+// Server is a fake GitHub instance serving the pull requests of a single repository, their reviews, and the
+// statuses and check runs of their head commits, with pagination. Pull requests can be created too. It accepts the
+// token Token as a bearer token. Paths may start with "/api/v3", like those of GitHub Enterprise Server.
+type Server struct {
+	*apitest.Server
+
+	// Repo is the full name of the repository, e.g. "octo/widgets"
+	Repo string
+	// PullRequests are the pull requests served
+	PullRequests []PullRequest
+	// PageSize caps the number of items in a page of results
+	PageSize int
+	// Token is the accepted token
+	Token string
+	// Login is the login of the account the token belongs to
+	Login string
+}
+
+// NewServer starts a server serving the given pull requests. Call Close when done.
+func NewServer(pulls ...PullRequest) *Server {
+	s := &Server{Repo: Repo, PullRequests: pulls, PageSize: 100, Token: Token, Login: Login}
+	s.Server = apitest.NewServer(s.handle)
+
+	return s
+}
+
+// NewPullRequest returns an open pull request of a branch of the server's default repository into main, with a
+// head commit named after the number.
+func NewPullRequest(number int, branch string) PullRequest {
+	return PullRequest{PullRequest: github.PullRequest{
+		Number:   number,
+		Title:    fmt.Sprintf("Pull request %d", number),
+		State:    integrations.PullRequestOpen,
+		Head:     branch,
+		HeadRepo: Repo,
+		HeadSHA:  fmt.Sprintf("%040d", number),
+		Base:     "main",
+		Updated:  time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC).Add(time.Duration(number) * time.Minute),
+	}}
+}
+
+// Client returns a client for the server, with caching disabled unless an option enables it.
+func (s *Server) Client(options ...github.Option) *github.Client {
+	return github.NewClient("", s.Token, append([]github.Option{github.WithAPIURL(s.URL), github.WithCacheDir("")}, options...)...)
+}
+
+// CachedClient returns a client for the server caching in a temporary directory of the test, with a clock the
+// test advances to expire cached data.
+func (s *Server) CachedClient(tb testing.TB, options ...github.Option) (*github.Client, *cachetest.Clock) {
+	cached, clock := apitest.CachedOptions(tb, github.WithCacheDir, github.WithClock)
+
+	return s.Client(append(cached, options...)...), clock
+}
+
+// Update changes a pull request while the server is running.
+func (s *Server) Update(number int, update func(pr *PullRequest)) {
+	s.Lock()
+	defer s.Unlock()
+
+	if pr := s.findPullRequest(number); pr != nil {
+		update(pr)
+	}
+}
+
+func (s *Server) findPullRequest(number int) *PullRequest {
+	for i := range s.PullRequests {
+		if s.PullRequests[i].Number == number {
+			return &s.PullRequests[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	// accept the paths of GitHub Enterprise Server too, so that a base URL can point to the server
+	prefix := "/repos/" + s.Repo
+	apiPath := strings.TrimPrefix(r.URL.Path, "/api/v3")
+	path, ok := strings.CutPrefix(apiPath, prefix)
+	if apiPath == "/user" {
+		path, ok = apiPath, true
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	// count requests by endpoint, without the pull request number or commit
+	endpoint := "/" + parts[0]
+	if len(parts) > 2 {
+		endpoint += "/" + parts[2]
+	}
+	if status := s.Receive(r.Method + " " + endpoint); status != 0 {
+		writeError(w, status, "simulated failure")
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeError(w, http.StatusUnauthorized, "Bad credentials")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && apiPath == "/user":
+		writeJSON(w, http.StatusOK, map[string]any{"login": s.Login})
+	case r.Method == http.MethodGet && len(parts) == 1 && parts[0] == "pulls":
+		s.listPullRequests(w, r)
+	case r.Method == http.MethodPost && len(parts) == 1 && parts[0] == "pulls":
+		s.createPullRequest(w, r)
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[0] == "pulls" && parts[2] == "reviews":
+		number, _ := strconv.Atoi(parts[1])
+		pr := s.findPullRequest(number)
+		if pr == nil {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+
+		reviews := make([]any, 0, len(pr.Reviews))
+		for i, review := range pr.Reviews {
+			reviews = append(reviews, map[string]any{"id": i + 1, "state": review.State, "user": map[string]any{"login": review.User}})
+		}
+		s.writePage(w, r, reviews, func(items []any) any { return items })
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[0] == "commits":
+		pr := s.findCommit(parts[1])
+
+		switch parts[2] {
+		case "status":
+			// check runs are the only checks of the fake server
+			writeJSON(w, http.StatusOK, map[string]any{"state": "pending", "total_count": 0, "statuses": []any{}})
+		case "check-runs":
+			runs := make([]any, 0)
+			if pr != nil {
+				for i, check := range pr.Checks {
+					run := map[string]any{"id": i + 1, "status": check.Status, "conclusion": nil}
+					if check.Conclusion != "" {
+						run["conclusion"] = check.Conclusion
+					}
+					runs = append(runs, run)
+				}
+			}
+			s.writePage(w, r, runs, func(items []any) any { return map[string]any{"total_count": len(runs), "check_runs": items} })
+		default:
+			writeError(w, http.StatusNotFound, "Not Found")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) findCommit(sha string) *PullRequest {
+	for i := range s.PullRequests {
+		if s.PullRequests[i].HeadSHA == sha {
+			return &s.PullRequests[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) listPullRequests(w http.ResponseWriter, r *http.Request) {
+	state := r.URL.Query().Get("state")
+	owner, branch, filterHead := strings.Cut(r.URL.Query().Get("head"), ":")
+
+	pulls := make([]PullRequest, 0, len(s.PullRequests))
+	for _, pr := range s.PullRequests {
+		headOwner, _, _ := strings.Cut(pr.HeadRepo, "/")
+		if filterHead && (pr.Head != branch || !strings.EqualFold(headOwner, owner)) {
+			continue
+		}
+
+		open := pr.State == integrations.PullRequestOpen
+		if state == "all" || (state == "closed" && !open) || ((state == "" || state == "open") && open) {
+			pulls = append(pulls, pr)
+		}
+	}
+
+	sort.SliceStable(pulls, func(i, j int) bool {
+		return pulls[i].Updated.After(pulls[j].Updated)
+	})
+
+	items := make([]any, 0, len(pulls))
+	for _, pr := range pulls {
+		items = append(items, pullResponse(pr))
+	}
+
+	s.writePage(w, r, items, func(items []any) any { return items })
+}
+
+// createPullRequest opens a pull request, rejecting it like GitHub when a field is missing or the branch already
+// has an open pull request.
+func (s *Server) createPullRequest(w http.ResponseWriter, r *http.Request) {
+	var request github.NewPullRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+
+	if request.Title == "" || request.Head == "" || request.Base == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	number := 1
+	for _, pr := range s.PullRequests {
+		if pr.Head == request.Head && pr.State == integrations.PullRequestOpen {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("A pull request already exists for %s:%s.", strings.Split(s.Repo, "/")[0], pr.Head))
+			return
+		}
+
+		number = max(number, pr.Number+1)
+	}
+
+	pr := NewPullRequest(number, request.Head)
+	pr.Title, pr.Body, pr.Base, pr.Draft, pr.HeadRepo = request.Title, request.Body, request.Base, request.Draft, s.Repo
+	s.PullRequests = append(s.PullRequests, pr)
+
+	writeJSON(w, http.StatusCreated, pullResponse(pr))
+}
+
+// writePage writes the page of items selected by the page and per_page parameters, using wrap to build the
+// response body.
+func (s *Server) writePage(w http.ResponseWriter, r *http.Request, items []any, wrap func(items []any) any) {
+	page, _ := s.Page(w, r, items, "per_page", s.PageSize)
+
+	writeJSON(w, http.StatusOK, wrap(page))
+}
+
+// pullResponse returns a pull request in the structure used by GitHub's API responses.
+func pullResponse(pr PullRequest) map[string]any {
+	state := pr.State
+	var mergedAt any
+	if pr.State == integrations.PullRequestMerged {
+		state, mergedAt = "closed", pr.Updated.Format(time.RFC3339)
+	}
+
+	reviewers := make([]any, 0, len(pr.Reviewers))
+	for _, login := range pr.Reviewers {
+		reviewers = append(reviewers, map[string]any{"login": login})
+	}
+
+	return map[string]any{
+		"number":              pr.Number,
+		"title":               pr.Title,
+		"html_url":            fmt.Sprintf("https://github.com/%s/pull/%d", pr.HeadRepo, pr.Number),
+		"state":               state,
+		"draft":               pr.Draft,
+		"merged_at":           mergedAt,
+		"updated_at":          pr.Updated.Format(time.RFC3339),
+		"head":                map[string]any{"ref": pr.Head, "sha": pr.HeadSHA, "repo": map[string]any{"full_name": pr.HeadRepo}},
+		"base":                map[string]any{"ref": pr.Base},
+		"requested_reviewers": reviewers,
+		"requested_teams":     []any{},
+	}
+}
+
+// writeJSON and writeError write the responses of the fake API
+var (
+	writeJSON  = apitest.WriteJSON
+	writeError = apitest.WriteError
+)
@@ -0,0 +1,221 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/cache"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/internal/rest"
+)
+
+// PullRequest is a pull request of a repository
+type PullRequest struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	// State is integrations.PullRequestOpen, PullRequestMerged or PullRequestClosed
+	State string `json:"state"`
+	Draft bool   `json:"draft"`
+	// Head is the source branch, HeadRepo the full name of the repository it belongs to, e.g. "owner/repo"
+	Head     string `json:"head"`
+	HeadRepo string `json:"head_repo"`
+	HeadSHA  string `json:"head_sha"`
+	Base     string `json:"base"`
+	// ReviewRequested reports whether reviewers or teams are still requested to review the pull request
+	ReviewRequested bool      `json:"review_requested"`
+	Updated         time.Time `json:"updated"`
+}
+
+// githubPullResponse represents a pull request in GitHub's API responses
+type githubPullResponse struct {
+	Number    int     `json:"number"`
+	Title     string  `json:"title"`
+	HTMLURL   string  `json:"html_url"`
+	State     string  `json:"state"`
+	Draft     bool    `json:"draft"`
+	MergedAt  *string `json:"merged_at"`
+	UpdatedAt string  `json:"updated_at"`
+	Head      struct {
+		Ref  string `json:"ref"`
+		SHA  string `json:"sha"`
+		Repo *struct {
+			FullName string `json:"full_name"`
+		} `json:"repo"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+	RequestedReviewers []json.RawMessage `json:"requested_reviewers"`
+	RequestedTeams     []json.RawMessage `json:"requested_teams"`
+}
+
+func (r githubPullResponse) toPullRequest() PullRequest {
+	pr := PullRequest{
+		Number:          r.Number,
+		Title:           r.Title,
+		URL:             r.HTMLURL,
+		State:           r.State,
+		Draft:           r.Draft,
+		Head:            r.Head.Ref,
+		HeadSHA:         r.Head.SHA,
+		Base:            r.Base.Ref,
+		ReviewRequested: len(r.RequestedReviewers)+len(r.RequestedTeams) > 0,
+	}
+
+	if r.Head.Repo != nil {
+		pr.HeadRepo = r.Head.Repo.FullName
+	}
+
+	if r.MergedAt != nil {
+		pr.State = integrations.PullRequestMerged
+	}
+
+	if updated, err := time.Parse(time.RFC3339, r.UpdatedAt); err == nil {
+		pr.Updated = updated
+	}
+
+	return pr
+}
+
+// repoURL returns the API URL of a repository's endpoint, e.g. repoURL("owner/repo", "/pulls").
+func (c *Client) repoURL(repo string, path string) string {
+	return c.APIURL + "/repos/" + repo + path
+}
+
+// PullRequests returns the pull requests of a branch of a repository, e.g. "owner/repo", in any state, most
+// recently updated first. Pull requests from branches of forks are not included. The results are cached until the
+// cache's TTL expires.
+func (c *Client) PullRequests(repo string, branch string) ([]PullRequest, error) {
+	pulls, _, err := c.PullRequestsWithMetadata(repo, branch)
+
+	return pulls, err
+}
+
+// PullRequestsWithMetadata returns a branch's pull requests like PullRequests, and the metadata of the cached
+// results, which describes their age and whether they are stale because fetching them again failed.
+func (c *Client) PullRequestsWithMetadata(repo string, branch string) ([]PullRequest, *cache.Metadata, error) {
+	head := headFilter(repo, branch)
+
+	var pulls []PullRequest
+
+	meta, err := c.Fetch(pullsCacheName(repo, branch), head, &pulls, func() (any, error) {
+		return c.fetchPullRequests(repo, head)
+	})
+
+	return pulls, meta, err
+}
+
+// headFilter returns the value of the head parameter selecting the pull requests of a branch of repo, e.g.
+// "owner:branch".
+func headFilter(repo string, branch string) string {
+	owner, _, _ := strings.Cut(repo, "/")
+
+	return owner + ":" + branch
+}
+
+// pullsCacheName returns the name of the cache file holding the pull requests of a branch of repo.
+func pullsCacheName(repo string, branch string) string {
+	return "pulls-" + rest.ShortHash(repo+"\x00"+branch)
+}
+
+// fetchPullRequests fetches the pull requests of a repository selected by the head filter, following the pages
+// of results.
+func (c *Client) fetchPullRequests(repo string, head string) ([]PullRequest, error) {
+	query := url.Values{}
+	query.Set("state", "all")
+	query.Set("head", head)
+	query.Set("sort", "updated")
+	query.Set("direction", "desc")
+	query.Set("per_page", "100")
+
+	result := make([]PullRequest, 0)
+	next := c.repoURL(repo, "/pulls?"+query.Encode())
+
+	for next != "" {
+		body, nextPage, err := c.Get(next)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []githubPullResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+		}
+
+		for _, pull := range page {
+			result = append(result, pull.toPullRequest())
+		}
+
+		next = nextPage
+	}
+
+	return result, nil
+}
+
+// BranchPullRequest returns the pull request of a branch of repo among pulls, or nil: the open one, or else the
+// most recently updated one. Pull requests from branches of forks with the same name are ignored.
+func BranchPullRequest(pulls []PullRequest, repo string, branch string) *PullRequest {
+	var result *PullRequest
+
+	for i := range pulls {
+		pr := &pulls[i]
+		if pr.Head != branch || (pr.HeadRepo != "" && !strings.EqualFold(pr.HeadRepo, repo)) {
+			continue
+		}
+
+		if pr.State == integrations.PullRequestOpen {
+			return pr
+		}
+
+		if result == nil || pr.Updated.After(result.Updated) {
+			result = pr
+		}
+	}
+
+	return result
+}
+
+// NewPullRequest describes a pull request to open
+type NewPullRequest struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	// Head is the source branch, Base the branch it merges into
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Draft bool   `json:"draft"`
+}
+
+// CreatePullRequest opens a pull request in repo, e.g. "owner/repo", and adds it to the cached pull requests of
+// its branch, so that it is found before they expire.
+func (c *Client) CreatePullRequest(repo string, pr NewPullRequest) (*PullRequest, error) {
+	body, err := c.Post(c.repoURL(repo, "/pulls"), pr)
+	if err != nil {
+		return nil, err
+	}
+
+	var response githubPullResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+
+	created := response.toPullRequest()
+
+	if c.Cache != nil {
+		name := c.CacheName(pullsCacheName(repo, pr.Head))
+
+		// only a cached list can be extended; without one, the next lookup fetches the pull request anyway
+		var pulls []PullRequest
+		if _, err := c.Cache.Read(name, &pulls); err == nil {
+			c.Cache.Update(name, headFilter(repo, pr.Head), &pulls, func() error {
+				pulls = append([]PullRequest{created}, pulls...)
+				return nil
+			})
+		}
+	}
+
+	return &created, nil
+}
@@ -0,0 +1,96 @@
+package github_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/github"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/github/githubtest"
+)
+
+func TestPullRequestsFollowsPages(t *testing.T) {
+	pulls := make([]githubtest.PullRequest, 0)
+	for i := 1; i <= 250; i++ {
+		pr := githubtest.NewPullRequest(i, "feature")
+		pr.State = integrations.PullRequestClosed
+		pulls = append(pulls, pr)
+	}
+
+	server := githubtest.NewServer(pulls...)
+	defer server.Close()
+
+	result, err := server.Client().PullRequests(githubtest.Repo, "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 250 || result[0].Number != 250 {
+		t.Errorf("expected 250 pull requests, most recent first, got %d", len(result))
+	}
+
+	if count := server.RequestCount("GET /pulls"); count != 3 {
+		t.Errorf("expected 3 requests, got %d", count)
+	}
+}
+
+func TestForgeFindsPullRequestsOfBranchesNotUpdatedRecently(t *testing.T) {
+	pulls := make([]githubtest.PullRequest, 0)
+	for i := 1; i <= 1000; i++ {
+		pulls = append(pulls, githubtest.NewPullRequest(i, fmt.Sprintf("branch-%d", i)))
+	}
+	pulls[0].State = integrations.PullRequestMerged
+
+	server := githubtest.NewServer(pulls...)
+	defer server.Close()
+
+	result, err := github.NewForge(server.Client(), repo).PullRequests([]string{"branch-1", "branch-1000", "none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 2 || result["branch-1"] == nil || !result["branch-1"].IsMerged() || result["branch-1000"] == nil {
+		t.Errorf("expected merged #1 and #1000, got %+v", result)
+	}
+
+	if count := server.RequestCount("GET /pulls"); count != 3 {
+		t.Errorf("expected one request per branch, got %d", count)
+	}
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	server := githubtest.NewServer(githubtest.NewPullRequest(1, "feature"))
+	defer server.Close()
+
+	client, _ := server.CachedClient(t)
+	forge := github.NewForge(client, repo)
+	forge.PullRequests([]string{"feature", "fix"})
+
+	created, err := forge.CreatePullRequest(integrations.NewPullRequest{Title: "Fix it", Body: "- Fix it", Branch: "fix", Base: "main", Draft: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.Number != 2 || !created.Draft || !created.IsOpen() {
+		t.Errorf("expected open draft #2, got %+v", created)
+	}
+
+	// the new pull request is found in the cached pull requests without fetching them again
+	result, err := forge.PullRequests([]string{"fix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result["fix"] == nil || result["fix"].Number != 2 || server.RequestCount("GET /pulls") != 2 {
+		t.Errorf("expected cached #2 for fix, got %+v", result["fix"])
+	}
+
+	_, err = forge.CreatePullRequest(integrations.NewPullRequest{Title: "Again", Branch: "feature", Base: "main"})
+
+	var apiErr *github.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected a 422 APIError for a duplicate, got %v", err)
+	}
+}
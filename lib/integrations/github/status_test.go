@@ -0,0 +1,44 @@
+package github_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/github"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/github/githubtest"
+)
+
+func TestStatusesAreCachedUntilTheHeadCommitChanges(t *testing.T) {
+	pr := githubtest.NewPullRequest(1, "feature")
+	pr.Checks = []githubtest.Check{{Status: "in_progress"}}
+
+	server := githubtest.NewServer(pr)
+	defer server.Close()
+
+	client, clock := server.CachedClient(t)
+	forge := github.NewForge(client, repo)
+
+	forge.PullRequests([]string{"feature"})
+	forge.PullRequests([]string{"feature"})
+
+	if server.RequestCount("GET /pulls") != 1 || server.RequestCount("GET /commits/check-runs") != 1 {
+		t.Errorf("expected one request of each kind before the TTL, got %v", server.Requests)
+	}
+
+	server.Update(1, func(pr *githubtest.PullRequest) {
+		pr.HeadSHA = fmt.Sprintf("%040d", 99)
+		pr.Checks = []githubtest.Check{{Status: "completed", Conclusion: "success"}}
+	})
+	clock.Advance(client.Cache.TTL + time.Second)
+
+	result, err := forge.PullRequests([]string{"feature"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result["feature"].Checks != integrations.ChecksPassing {
+		t.Errorf("expected the checks of the new head commit, got %+v", result["feature"])
+	}
+}
@@ -0,0 +1,86 @@
+// Package gitlab is a client for the REST API of gitlab.com and self-managed GitLab instances, used to find the
+// merge requests of branches.
+package gitlab
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/internal/rest"
+)
+
+// DefaultBaseURL is the URL of gitlab.com
+const DefaultBaseURL = "https://gitlab.com"
+
+// Client makes requests to the REST API of gitlab.com or a self-managed GitLab instance. Its cache stores merge
+// requests and their approval and pipeline states.
+type Client struct {
+	*rest.Client
+}
+
+// Option configures a Client created by NewClient
+type Option = rest.Option
+
+// APIError is returned when the GitLab API responds with an unsuccessful status code
+type APIError = rest.APIError
+
+// Options of NewClient
+var (
+	// WithAPIURL replaces the API URL derived from the base URL passed to NewClient, e.g. with the URL of a test server.
+	WithAPIURL = rest.WithAPIURL
+	// WithHTTPClient makes requests with the given HTTP client.
+	WithHTTPClient = rest.WithHTTPClient
+	// WithTransport makes requests with an HTTP client using the given transport.
+	WithTransport = rest.WithTransport
+	// WithCacheDir caches data in dir instead of the default cache directory. An empty dir disables caching.
+	WithCacheDir = rest.WithCacheDir
+	// WithClock uses now instead of time.Now to get the current time, including for cache expiry.
+	WithClock = rest.WithClock
+)
+
+// provider describes GitLab's REST API
+var provider = rest.Provider{
+	Name:     "GitLab",
+	CacheDir: "gitlab",
+	Header: func(token string) http.Header {
+		return http.Header{
+			"Private-Token": {token},
+			"Accept":        {"application/json"},
+		}
+	},
+	AccountField: "username",
+}
+
+// APIURL returns the URL of the REST API of the GitLab instance at baseURL, which is gitlab.com when baseURL is
+// empty.
+func APIURL(baseURL string) string {
+	baseURL = strings.TrimRight(firstNonEmpty(baseURL, DefaultBaseURL), "/")
+
+	if strings.Contains(baseURL, "/api/") {
+		return baseURL
+	}
+
+	return baseURL + "/api/v4"
+}
+
+// IsDotCom reports whether host is that of gitlab.com.
+func IsDotCom(host string) bool {
+	return strings.EqualFold(host, "gitlab.com")
+}
+
+// NewClient returns a client for the GitLab instance at baseURL (see APIURL), authenticating with a personal,
+// group or project access token.
+func NewClient(baseURL, token string, options ...Option) *Client {
+	return &Client{Client: rest.NewClient(provider, APIURL(baseURL), token, options...)}
+}
+
+// firstNonEmpty returns the first of values that is not empty.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
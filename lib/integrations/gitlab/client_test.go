@@ -0,0 +1,22 @@
+package gitlab_test
+
+import (
+	"testing"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/gitlab"
+)
+
+func TestAPIURL(t *testing.T) {
+	tests := map[string]string{
+		"":                                   "https://gitlab.com/api/v4",
+		"https://gitlab.example.com/":        "https://gitlab.example.com/api/v4",
+		"https://example.com/gitlab":         "https://example.com/gitlab/api/v4",
+		"https://gitlab.example.com/api/v4/": "https://gitlab.example.com/api/v4",
+	}
+
+	for baseURL, want := range tests {
+		if got := gitlab.APIURL(baseURL); got != want {
+			t.Errorf("expected %s for %q, got %s", want, baseURL, got)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package gitlab
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+)
+
+// Forge makes a Client usable as an integrations.Forge for a repository, whose owner and name form the path of
+// the GitLab project
+type Forge struct {
+	Client *Client
+	Repo   integrations.Repository
+}
+
+// NewForge returns a forge for repo using client.
+func NewForge(client *Client, repo integrations.Repository) *Forge {
+	return &Forge{Client: client, Repo: repo}
+}
+
+func (f *Forge) Name() string {
+	return "gitlab"
+}
+
+func (f *Forge) Validate() error {
+	return f.Client.Validate()
+}
+
+func (f *Forge) PullRequests(branches []string) (map[string]*integrations.PullRequest, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	project := f.Repo.FullName()
+
+	mrs, err := f.Client.BranchMergeRequests(project, branches)
+	if err != nil && len(mrs) == 0 {
+		return nil, err
+	}
+
+	result := make(map[string]*integrations.PullRequest)
+	errs := []error{err}
+
+	for branch, mr := range mrs {
+		converted := forgePullRequest(*mr)
+
+		// approvals and pipelines only matter while the merge request is open
+		if mr.IsOpen() {
+			status, err := f.Client.MergeRequestStatus(project, *mr)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to fetch the status of merge request !%d: %w", mr.IID, err))
+			}
+
+			if status != nil {
+				converted.Review, converted.Checks = reviewStates[status.Review], checkStates[status.Pipeline]
+			}
+		}
+
+		result[branch] = &converted
+	}
+
+	return result, errors.Join(errs...)
+}
+
+func (f *Forge) CreatePullRequest(pr integrations.NewPullRequest) (*integrations.PullRequest, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	created, err := f.Client.CreateMergeRequest(f.Repo.FullName(), NewMergeRequest{
+		Title:        pr.Title,
+		Description:  pr.Body,
+		SourceBranch: pr.Branch,
+		TargetBranch: pr.Base,
+		Draft:        pr.Draft,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	converted := forgePullRequest(*created)
+
+	return &converted, nil
+}
+
+// pullRequestStates maps GitLab's merge request states to those of the integrations package
+var pullRequestStates = map[string]string{
+	StateOpened: integrations.PullRequestOpen,
+	StateLocked: integrations.PullRequestOpen,
+	StateMerged: integrations.PullRequestMerged,
+	StateClosed: integrations.PullRequestClosed,
+}
+
+// reviewStates maps GitLab's review states to those of the integrations package
+var reviewStates = map[string]string{
+	ReviewApproved:         integrations.ReviewApproved,
+	ReviewChangesRequested: integrations.ReviewChangesRequested,
+	ReviewRequired:         integrations.ReviewRequired,
+}
+
+// checkStates maps GitLab's pipeline states to the check states of the integrations package
+var checkStates = map[string]string{
+	PipelinePassing: integrations.ChecksPassing,
+	PipelineFailing: integrations.ChecksFailing,
+	PipelinePending: integrations.ChecksPending,
+}
+
+func forgePullRequest(mr MergeRequest) integrations.PullRequest {
+	return integrations.PullRequest{
+		Number:    mr.IID,
+		Reference: "!" + strconv.Itoa(mr.IID),
+		Title:     mr.Title,
+		URL:       mr.URL,
+		Branch:    mr.SourceBranch,
+		Base:      mr.TargetBranch,
+		State:     pullRequestStates[mr.State],
+		Draft:     mr.Draft,
+		HeadSHA:   mr.SHA,
+		Updated:   mr.Updated,
+	}
+}
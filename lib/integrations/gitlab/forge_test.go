@@ -0,0 +1,182 @@
+package gitlab_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/gitlab"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/gitlab/gitlabtest"
+)
+
+// repo is the repository of the project served by the fake server
+var repo = integrations.Repository{Host: "gitlab.example.com", Owner: "octo", Name: "widgets"}
+
+func TestForgeFindsProjectsInSubgroups(t *testing.T) {
+	server := gitlabtest.NewServer(gitlabtest.NewMergeRequest(1, "feature"))
+	server.Project = "octo/platform/widgets"
+	defer server.Close()
+
+	nested := integrations.Repository{Host: "gitlab.example.com", Owner: "octo/platform", Name: "widgets"}
+	result, err := gitlab.NewForge(server.Client(), nested).PullRequests([]string{"feature"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result["feature"] == nil || result["feature"].Ref() != "!1" {
+		t.Errorf("expected !1 for feature, got %+v", result["feature"])
+	}
+}
+
+func TestForgeChoosesTheMergeRequestOfEachBranch(t *testing.T) {
+	merged := gitlabtest.NewMergeRequest(1, "feature")
+	merged.State = gitlab.StateMerged
+	open := gitlabtest.NewMergeRequest(2, "feature")
+	fork := gitlabtest.NewMergeRequest(3, "fix")
+	fork.SourceProjectID = 7
+	closed := gitlabtest.NewMergeRequest(4, "old")
+	closed.State = gitlab.StateClosed
+	draft := gitlabtest.NewMergeRequest(5, "wip")
+	draft.Draft = true
+
+	server := gitlabtest.NewServer(merged, open, fork, closed, draft)
+	server.PageSize = 1
+	defer server.Close()
+
+	result, err := gitlab.NewForge(server.Client(), repo).PullRequests([]string{"feature", "fix", "old", "wip", "none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 3 || result["feature"].Number != 2 || !result["feature"].IsOpen() {
+		t.Fatalf("expected open !2 for feature, got %+v", result["feature"])
+	}
+
+	if !result["wip"].Draft {
+		t.Errorf("expected a draft for wip, got %+v", result["wip"])
+	}
+
+	if result["old"].State != integrations.PullRequestClosed {
+		t.Errorf("expected closed !4 for old, got %+v", result["old"])
+	}
+}
+
+func TestForgeReportsApprovalAndPipelineStates(t *testing.T) {
+	approved := gitlabtest.NewMergeRequest(1, "approved")
+	approved.ApprovalsRequired = 1
+	approved.ApprovedBy = []string{"ann"}
+	approved.Pipeline = "success"
+
+	changes := gitlabtest.NewMergeRequest(2, "changes")
+	changes.ApprovedBy = []string{"ann"}
+	changes.ChangesRequested = true
+	changes.Pipeline = "running"
+
+	waiting := gitlabtest.NewMergeRequest(3, "waiting")
+	waiting.ApprovalsRequired = 2
+	waiting.ApprovedBy = []string{"ann"}
+	waiting.Pipeline = "failed"
+
+	optional := gitlabtest.NewMergeRequest(4, "optional")
+	optional.Pipeline = "manual"
+
+	server := gitlabtest.NewServer(approved, changes, waiting, optional)
+	defer server.Close()
+
+	result, err := gitlab.NewForge(server.Client(), repo).PullRequests([]string{"approved", "changes", "waiting", "optional"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][2]string{
+		"approved": {integrations.ReviewApproved, integrations.ChecksPassing},
+		"changes":  {integrations.ReviewChangesRequested, integrations.ChecksPending},
+		"waiting":  {integrations.ReviewRequired, integrations.ChecksFailing},
+		"optional": {"", ""},
+	}
+
+	for branch, states := range want {
+		if pr := result[branch]; pr == nil || pr.Review != states[0] || pr.Checks != states[1] {
+			t.Errorf("expected %s to be %v, got %+v", branch, states, pr)
+		}
+	}
+}
+
+func TestForgeCachesMergeRequestsAndStatusesUntilTheHeadCommitChanges(t *testing.T) {
+	mr := gitlabtest.NewMergeRequest(1, "feature")
+	mr.Pipeline = "running"
+
+	server := gitlabtest.NewServer(mr)
+	defer server.Close()
+
+	client, clock := server.CachedClient(t)
+	forge := gitlab.NewForge(client, repo)
+
+	forge.PullRequests([]string{"feature", "none"})
+	forge.PullRequests([]string{"feature", "none"})
+
+	if server.RequestCount("GET /merge_requests") != 2 || server.RequestCount("GET /merge_requests/:iid") != 1 {
+		t.Errorf("expected one request per branch and merge request before the TTL, got %v", server.Requests)
+	}
+
+	server.Update(1, func(mr *gitlabtest.MergeRequest) {
+		mr.SHA = fmt.Sprintf("%040d", 99)
+		mr.Pipeline = "success"
+	})
+	clock.Advance(client.Cache.TTL + time.Second)
+
+	result, err := forge.PullRequests([]string{"feature"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result["feature"].Checks != integrations.ChecksPassing {
+		t.Errorf("expected the pipeline of the new head commit, got %+v", result["feature"])
+	}
+	// merge requests are cached per branch rather than by the client core, so check their stale fallback too
+	server.Fail(http.StatusBadGateway)
+	clock.Advance(client.Cache.TTL + time.Second)
+
+	result, err = forge.PullRequests([]string{"feature"})
+	if err == nil || result["feature"] == nil {
+		t.Errorf("expected the stale merge request with the refresh error, got %v, %v", result, err)
+	}
+}
+
+func TestForgeCreatesMergeRequests(t *testing.T) {
+	server := gitlabtest.NewServer(gitlabtest.NewMergeRequest(1, "feature"))
+	defer server.Close()
+
+	client, _ := server.CachedClient(t)
+	forge := gitlab.NewForge(client, repo)
+	forge.PullRequests([]string{"feature", "fix"})
+
+	created, err := forge.CreatePullRequest(integrations.NewPullRequest{Title: "Fix it", Body: "- Fix it", Branch: "fix", Base: "main", Draft: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.Ref() != "!2" || !created.Draft || created.Title != "Draft: Fix it" {
+		t.Errorf("expected draft !2, got %+v", created)
+	}
+
+	// the branch's cached lack of a merge request is replaced by the new one
+	result, err := forge.PullRequests([]string{"fix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result["fix"] == nil || result["fix"].Number != 2 || server.RequestCount("GET /merge_requests") != 2 {
+		t.Errorf("expected cached !2 for fix, got %+v", result["fix"])
+	}
+
+	_, err = forge.CreatePullRequest(integrations.NewPullRequest{Title: "Again", Branch: "feature", Base: "main"})
+
+	var apiErr *gitlab.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		t.Errorf("expected a 409 APIError for a duplicate, got %v", err)
+	}
+}
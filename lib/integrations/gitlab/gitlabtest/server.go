@@ -0,0 +1,322 @@
+// Package gitlabtest provides a fake GitLab REST API server for testing code that uses the gitlab package
+// without contacting a GitLab instance.
+package gitlabtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/cache/cachetest"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/gitlab"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/internal/apitest"
+)
+
+// Token is the token accepted by a Server unless it is changed
+const Token = "glpat-test"
+
+// Username is the username of the account of the token accepted by a Server unless it is changed
+const Username = "octocat"
+
+// Project is the path of the project served by a Server unless it is changed
+const Project = "octo/widgets"
+
+// ProjectID is the ID of the project served by a Server
+const ProjectID = 42
+
+// MergeRequest is a merge request served by a Server, with the state of its approvals and head pipeline
+type MergeRequest struct {
+	gitlab.MergeRequest
+	// Description is the description the merge request was created with
+	Description string
+	// Pipeline is the status of the head pipeline, e.g. "success" or "running"; there is none when it is empty
+	Pipeline string
+	// ApprovalsRequired is the number of approvals needed before the merge request can be merged
+	ApprovalsRequired int
+	// ApprovedBy lists the users who approved the merge request
+	ApprovedBy []string
+	// ChangesRequested reports whether a reviewer requested changes
+	ChangesRequested bool
+}
+
+// Server is a fake GitLab instance serving the merge requests of a single project, with their approvals and head
+// pipelines, and pagination. Merge requests can be created too. It accepts the token Token in the PRIVATE-TOKEN
+// header or as a bearer token. Paths may start with "/api/v4", so that both the instance URL and the API URL can
+// point to the server. Requests are counted by endpoint, with ":iid" in place of merge request IIDs, e.g.
+// "GET /merge_requests/:iid/approvals".
+type Server struct {
+	*apitest.Server
+
+	// Project is the path of the project, e.g. "group/subgroup/project"
+	Project string
+	// MergeRequests are the merge requests served
+	MergeRequests []MergeRequest
+	// PageSize caps the number of items in a page of results
+	PageSize int
+	// Token is the accepted token
+	Token string
+	// Username is the username of the account the token belongs to
+	Username string
+}
+
+// NewServer starts a server serving the given merge requests. Call Close when done.
+func NewServer(mrs ...MergeRequest) *Server {
+	s := &Server{Project: Project, MergeRequests: mrs, PageSize: 100, Token: Token, Username: Username}
+	s.Server = apitest.NewServer(s.handle)
+
+	return s
+}
+
+// NewMergeRequest returns an open merge request of a branch of the server's project into main, with a head
+// commit named after the IID.
+func NewMergeRequest(iid int, branch string) MergeRequest {
+	return MergeRequest{MergeRequest: gitlab.MergeRequest{
+		IID:             iid,
+		Title:           fmt.Sprintf("Merge request %d", iid),
+		State:           gitlab.StateOpened,
+		SourceBranch:    branch,
+		TargetBranch:    "main",
+		ProjectID:       ProjectID,
+		SourceProjectID: ProjectID,
+		SHA:             fmt.Sprintf("%040d", iid),
+		Updated:         time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC).Add(time.Duration(iid) * time.Minute),
+	}}
+}
+
+// Client returns a client for the server, with caching disabled unless an option enables it.
+func (s *Server) Client(options ...gitlab.Option) *gitlab.Client {
+	return gitlab.NewClient(s.URL, s.Token, append([]gitlab.Option{gitlab.WithCacheDir("")}, options...)...)
+}
+
+// CachedClient returns a client for the server caching in a temporary directory of the test, with a clock the
+// test advances to expire cached data.
+func (s *Server) CachedClient(tb testing.TB, options ...gitlab.Option) (*gitlab.Client, *cachetest.Clock) {
+	cached, clock := apitest.CachedOptions(tb, gitlab.WithCacheDir, gitlab.WithClock)
+
+	return s.Client(append(cached, options...)...), clock
+}
+
+// Update changes a merge request while the server is running.
+func (s *Server) Update(iid int, update func(mr *MergeRequest)) {
+	s.Lock()
+	defer s.Unlock()
+
+	if mr := s.findMergeRequest(iid); mr != nil {
+		update(mr)
+	}
+}
+
+func (s *Server) findMergeRequest(iid int) *MergeRequest {
+	for i := range s.MergeRequests {
+		if s.MergeRequests[i].IID == iid {
+			return &s.MergeRequests[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	// projects are identified by their URL-encoded path, so that its slashes don't separate path segments
+	rawPath := strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4")
+	prefix := "/projects/" + strings.ReplaceAll(s.Project, "/", "%2F")
+	path, ok := strings.CutPrefix(rawPath, prefix)
+	if !ok {
+		path, ok = strings.CutPrefix(rawPath, "/projects/"+strconv.Itoa(ProjectID))
+	}
+	if rawPath == "/user" {
+		path, ok = rawPath, true
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, "404 Project Not Found")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	// count requests by endpoint, without the merge request IID
+	endpoint := "/" + parts[0]
+	if len(parts) > 1 {
+		endpoint += "/:iid"
+	}
+	if len(parts) > 2 {
+		endpoint += "/" + parts[2]
+	}
+	if status := s.Receive(r.Method + " " + endpoint); status != 0 {
+		writeError(w, status, "simulated failure")
+		return
+	}
+
+	if r.Header.Get("PRIVATE-TOKEN") != s.Token && r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeError(w, http.StatusUnauthorized, "401 Unauthorized")
+		return
+	}
+
+	if rawPath == "/user" && r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, map[string]any{"id": 1, "username": s.Username})
+		return
+	}
+
+	if parts[0] == "merge_requests" && len(parts) == 1 && r.Method == http.MethodPost {
+		s.createMergeRequest(w, r)
+		return
+	}
+
+	if parts[0] != "merge_requests" || r.Method != http.MethodGet {
+		writeError(w, http.StatusNotFound, "404 Not Found")
+		return
+	}
+
+	if len(parts) == 1 {
+		s.listMergeRequests(w, r)
+		return
+	}
+
+	iid, _ := strconv.Atoi(parts[1])
+	mr := s.findMergeRequest(iid)
+	if mr == nil || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, "404 Not found")
+		return
+	}
+
+	switch {
+	case len(parts) == 2:
+		response := s.mergeRequestResponse(*mr)
+
+		response["head_pipeline"] = nil
+		if mr.Pipeline != "" {
+			response["head_pipeline"] = map[string]any{"id": mr.IID, "sha": mr.SHA, "status": mr.Pipeline}
+		}
+
+		response["detailed_merge_status"] = "mergeable"
+		switch {
+		case mr.ChangesRequested:
+			response["detailed_merge_status"] = "requested_changes"
+		case len(mr.ApprovedBy) < mr.ApprovalsRequired:
+			response["detailed_merge_status"] = "not_approved"
+		}
+
+		writeJSON(w, http.StatusOK, response)
+	case parts[2] == "approvals":
+		approvedBy := make([]any, 0, len(mr.ApprovedBy))
+		for _, username := range mr.ApprovedBy {
+			approvedBy = append(approvedBy, map[string]any{"user": map[string]any{"username": username}})
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"iid":                mr.IID,
+			"approved":           len(mr.ApprovedBy) >= mr.ApprovalsRequired,
+			"approvals_required": mr.ApprovalsRequired,
+			"approvals_left":     max(mr.ApprovalsRequired-len(mr.ApprovedBy), 0),
+			"approved_by":        approvedBy,
+		})
+	default:
+		writeError(w, http.StatusNotFound, "404 Not Found")
+	}
+}
+
+func (s *Server) listMergeRequests(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	state, branch := query.Get("state"), query.Get("source_branch")
+
+	mrs := make([]MergeRequest, 0, len(s.MergeRequests))
+	for _, mr := range s.MergeRequests {
+		if (state == "" || state == "all" || state == mr.State) && (branch == "" || branch == mr.SourceBranch) {
+			mrs = append(mrs, mr)
+		}
+	}
+
+	sort.SliceStable(mrs, func(i, j int) bool {
+		return mrs[i].Updated.After(mrs[j].Updated)
+	})
+
+	items := make([]any, 0, len(mrs))
+	for _, mr := range mrs {
+		items = append(items, s.mergeRequestResponse(mr))
+	}
+
+	s.writePage(w, r, items)
+}
+
+// draftPrefix matches the title prefixes that make a merge request a draft
+var draftPrefix = regexp.MustCompile(`(?i)^\s*(\[draft\]|\(draft\)|draft:|draft\s-)\s*`)
+
+// createMergeRequest opens a merge request, rejecting it like GitLab when a field is missing or the source
+// branch already has an open merge request.
+func (s *Server) createMergeRequest(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Title        string `json:"title"`
+		Description  string `json:"description"`
+		SourceBranch string `json:"source_branch"`
+		TargetBranch string `json:"target_branch"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "400 Bad request - invalid JSON")
+		return
+	}
+
+	if request.Title == "" || request.SourceBranch == "" || request.TargetBranch == "" {
+		writeError(w, http.StatusBadRequest, "400 Bad request - title, source_branch and target_branch are required")
+		return
+	}
+
+	iid := 1
+	for _, mr := range s.MergeRequests {
+		if mr.SourceBranch == request.SourceBranch && mr.IsOpen() {
+			writeJSON(w, http.StatusConflict, map[string]any{"message": []string{
+				fmt.Sprintf("Another open merge request already exists for this source branch: !%d", mr.IID),
+			}})
+			return
+		}
+
+		iid = max(iid, mr.IID+1)
+	}
+
+	mr := NewMergeRequest(iid, request.SourceBranch)
+	mr.Title, mr.Description, mr.TargetBranch = request.Title, request.Description, request.TargetBranch
+	mr.Draft = draftPrefix.MatchString(request.Title)
+	s.MergeRequests = append(s.MergeRequests, mr)
+
+	writeJSON(w, http.StatusCreated, s.mergeRequestResponse(mr))
+}
+
+// writePage writes the page of items selected by the page and per_page parameters, with the number of the next
+// page in the X-Next-Page header when there is one.
+func (s *Server) writePage(w http.ResponseWriter, r *http.Request, items []any) {
+	page, next := s.Page(w, r, items, "per_page", s.PageSize)
+	if next != 0 {
+		w.Header().Set("X-Next-Page", strconv.Itoa(next))
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// mergeRequestResponse returns a merge request in the structure used by GitLab's API responses.
+func (s *Server) mergeRequestResponse(mr MergeRequest) map[string]any {
+	return map[string]any{
+		"iid":               mr.IID,
+		"title":             mr.Title,
+		"web_url":           fmt.Sprintf("%s/%s/-/merge_requests/%d", s.URL, s.Project, mr.IID),
+		"state":             mr.State,
+		"draft":             mr.Draft,
+		"work_in_progress":  mr.Draft,
+		"source_branch":     mr.SourceBranch,
+		"target_branch":     mr.TargetBranch,
+		"project_id":        mr.ProjectID,
+		"source_project_id": mr.SourceProjectID,
+		"sha":               mr.SHA,
+		"updated_at":        mr.Updated.Format(time.RFC3339),
+	}
+}
+
+// writeJSON and writeError write the responses of the fake API
+var (
+	writeJSON  = apitest.WriteJSON
+	writeError = apitest.WriteError
+)
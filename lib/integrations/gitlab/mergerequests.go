@@ -0,0 +1,292 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// States of merge requests
+const (
+	StateOpened = "opened"
+	StateMerged = "merged"
+	StateClosed = "closed"
+	// StateLocked is the state of an open merge request while it is being merged
+	StateLocked = "locked"
+)
+
+// MergeRequest is a merge request of a project
+type MergeRequest struct {
+	// IID is the number of the merge request within its project
+	IID   int    `json:"iid"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	// State is StateOpened, StateMerged, StateClosed or StateLocked
+	State        string `json:"state"`
+	Draft        bool   `json:"draft"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+	// ProjectID is the ID of the project the merge request belongs to, SourceProjectID that of the project of
+	// the source branch, which differs for merge requests from forks
+	ProjectID       int       `json:"project_id"`
+	SourceProjectID int       `json:"source_project_id"`
+	SHA             string    `json:"sha"`
+	Updated         time.Time `json:"updated"`
+}
+
+// IsOpen reports whether the merge request is open.
+func (m MergeRequest) IsOpen() bool {
+	return m.State == StateOpened || m.State == StateLocked
+}
+
+// gitlabMergeRequestResponse represents a merge request in GitLab's API responses
+type gitlabMergeRequestResponse struct {
+	IID             int    `json:"iid"`
+	Title           string `json:"title"`
+	WebURL          string `json:"web_url"`
+	State           string `json:"state"`
+	Draft           bool   `json:"draft"`
+	WorkInProgress  bool   `json:"work_in_progress"`
+	SourceBranch    string `json:"source_branch"`
+	TargetBranch    string `json:"target_branch"`
+	ProjectID       int    `json:"project_id"`
+	SourceProjectID int    `json:"source_project_id"`
+	SHA             string `json:"sha"`
+	UpdatedAt       string `json:"updated_at"`
+}
+
+func (r gitlabMergeRequestResponse) toMergeRequest() MergeRequest {
+	mr := MergeRequest{
+		IID:             r.IID,
+		Title:           r.Title,
+		URL:             r.WebURL,
+		State:           r.State,
+		Draft:           r.Draft || r.WorkInProgress,
+		SourceBranch:    r.SourceBranch,
+		TargetBranch:    r.TargetBranch,
+		ProjectID:       r.ProjectID,
+		SourceProjectID: r.SourceProjectID,
+		SHA:             r.SHA,
+	}
+
+	if updated, err := time.Parse(time.RFC3339, r.UpdatedAt); err == nil {
+		mr.Updated = updated
+	}
+
+	return mr
+}
+
+// projectURL returns the API URL of a project's endpoint, e.g. projectURL("group/project", "/merge_requests").
+// Projects are identified by their URL-encoded path, which may contain subgroups.
+func (c *Client) projectURL(project string, path string) string {
+	return c.APIURL + "/projects/" + url.PathEscape(project) + path
+}
+
+// CachedMergeRequest is the merge request of a branch. MergeRequest is nil when the branch has none.
+type CachedMergeRequest struct {
+	Timestamp    time.Time     `json:"timestamp"`
+	MergeRequest *MergeRequest `json:"merge_request"`
+}
+
+// branchCacheName is the name of the cache file holding the merge requests of branches, keyed by project and branch
+const branchCacheName = "branches"
+
+// BranchMergeRequests returns the merge request of each of the branches of a project, e.g. "group/project", that
+// has one, keyed by branch name. Merge requests are cached for the cache's TTL, and those of the remaining
+// branches are fetched by source branch. When fetching fails, expired cached merge requests are returned along
+// with the error.
+func (c *Client) BranchMergeRequests(project string, branches []string) (map[string]*MergeRequest, error) {
+	result := make(map[string]*MergeRequest)
+
+	if c.Cache == nil {
+		for _, branch := range branches {
+			mr, err := c.fetchBranchMergeRequest(project, branch)
+			if err != nil {
+				return result, err
+			}
+
+			if mr != nil {
+				result[branch] = mr
+			}
+		}
+
+		return result, nil
+	}
+
+	cached := make(map[string]*CachedMergeRequest)
+	c.Cache.Read(c.CacheName(branchCacheName), &cached)
+
+	found := make(map[string]*MergeRequest)
+	missing := make([]string, 0)
+	now := c.Time()
+
+	for _, branch := range branches {
+		entry, ok := cached[project+"\x00"+branch]
+		if ok && (c.Cache.TTL == 0 || now.Sub(entry.Timestamp) < c.Cache.TTL) {
+			if entry.MergeRequest != nil {
+				result[branch] = entry.MergeRequest
+			}
+			continue
+		}
+
+		missing = append(missing, branch)
+	}
+
+	var fetchErr error
+	fetched := make([]string, 0, len(missing))
+
+	for _, branch := range missing {
+		mr, err := c.fetchBranchMergeRequest(project, branch)
+		if err != nil {
+			fetchErr = err
+			break
+		}
+
+		found[branch] = mr
+		fetched = append(fetched, branch)
+	}
+
+	for _, branch := range missing {
+		if mr, ok := found[branch]; ok {
+			if mr != nil {
+				result[branch] = mr
+			}
+			continue
+		}
+
+		// fall back to stale cached data rather than failing
+		if entry, ok := cached[project+"\x00"+branch]; ok && entry.MergeRequest != nil && now.Sub(entry.Timestamp) < c.Cache.TTL+c.Cache.MaxStale {
+			result[branch] = entry.MergeRequest
+		}
+	}
+
+	if len(fetched) == 0 {
+		return result, fetchErr
+	}
+
+	// merge with merge requests cached by other processes in the meantime, dropping entries that can no longer be
+	// used; proceed without failing if the cache can't be written, as we have the merge requests
+	c.Cache.Update(c.CacheName(branchCacheName), branchCacheName, &cached, func() error {
+		for key, entry := range cached {
+			if c.Cache.TTL > 0 && now.Sub(entry.Timestamp) >= c.Cache.TTL+c.Cache.MaxStale {
+				delete(cached, key)
+			}
+		}
+
+		for _, branch := range fetched {
+			cached[project+"\x00"+branch] = &CachedMergeRequest{Timestamp: now, MergeRequest: found[branch]}
+		}
+
+		return nil
+	})
+
+	return result, fetchErr
+}
+
+// fetchBranchMergeRequest fetches the merge requests whose source is a branch of the project, and returns the
+// one chosen by BranchMergeRequest.
+func (c *Client) fetchBranchMergeRequest(project string, branch string) (*MergeRequest, error) {
+	query := url.Values{}
+	query.Set("source_branch", branch)
+	query.Set("state", "all")
+	query.Set("order_by", "updated_at")
+	query.Set("sort", "desc")
+	query.Set("per_page", "100")
+
+	mrs := make([]MergeRequest, 0)
+	next := c.projectURL(project, "/merge_requests?"+query.Encode())
+
+	for next != "" {
+		body, nextPage, err := c.Get(next)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []gitlabMergeRequestResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+		}
+
+		for _, mr := range page {
+			mrs = append(mrs, mr.toMergeRequest())
+		}
+
+		next = nextPage
+	}
+
+	return BranchMergeRequest(mrs, branch), nil
+}
+
+// BranchMergeRequest returns the merge request of a branch among mrs, or nil: the open one, or else the most
+// recently updated one. Merge requests from branches of forks with the same name are ignored.
+func BranchMergeRequest(mrs []MergeRequest, branch string) *MergeRequest {
+	var result *MergeRequest
+
+	for i := range mrs {
+		mr := &mrs[i]
+		if mr.SourceBranch != branch || (mr.SourceProjectID != 0 && mr.SourceProjectID != mr.ProjectID) {
+			continue
+		}
+
+		if mr.IsOpen() {
+			return mr
+		}
+
+		if result == nil || mr.Updated.After(result.Updated) {
+			result = mr
+		}
+	}
+
+	return result
+}
+
+// NewMergeRequest describes a merge request to open
+type NewMergeRequest struct {
+	Title       string
+	Description string
+	// SourceBranch is the branch to merge, TargetBranch the branch it merges into
+	SourceBranch string
+	TargetBranch string
+	// Draft marks the merge request as a draft, which GitLab does by prefixing its title with "Draft:"
+	Draft bool
+}
+
+// CreateMergeRequest opens a merge request in project, e.g. "group/project", and caches it as the merge request
+// of its source branch.
+func (c *Client) CreateMergeRequest(project string, mr NewMergeRequest) (*MergeRequest, error) {
+	title := mr.Title
+	if mr.Draft {
+		title = "Draft: " + title
+	}
+
+	body, err := c.Post(c.projectURL(project, "/merge_requests"), map[string]any{
+		"title":         title,
+		"description":   mr.Description,
+		"source_branch": mr.SourceBranch,
+		"target_branch": mr.TargetBranch,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response gitlabMergeRequestResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+
+	created := response.toMergeRequest()
+
+	if c.Cache != nil {
+		now := c.Time()
+		cached := make(map[string]*CachedMergeRequest)
+
+		// proceed without failing if the cache can't be written, as the merge request was created
+		c.Cache.Update(c.CacheName(branchCacheName), branchCacheName, &cached, func() error {
+			cached[project+"\x00"+created.SourceBranch] = &CachedMergeRequest{Timestamp: now, MergeRequest: &created}
+			return nil
+		})
+	}
+
+	return &created, nil
+}
@@ -0,0 +1,153 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Review states of merge requests, based on their approvals
+const (
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes_requested"
+	ReviewRequired         = "review_required"
+)
+
+// Pipeline states of merge requests, based on the status of their head pipeline
+const (
+	PipelinePassing = "passing"
+	PipelineFailing = "failing"
+	PipelinePending = "pending"
+)
+
+// Status is the approval and pipeline state of a merge request
+type Status struct {
+	// Review is ReviewApproved, ReviewChangesRequested, ReviewRequired, or empty when no approval is required
+	Review string `json:"review"`
+	// Pipeline is PipelinePassing, PipelineFailing, PipelinePending, or empty when there is no pipeline
+	Pipeline string `json:"pipeline"`
+}
+
+// statusCacheEntry is the status of a merge request at a head commit
+type statusCacheEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	SHA       string    `json:"sha"`
+	Status    Status    `json:"status"`
+}
+
+// statusCacheName is the name of the cache file holding the statuses of merge requests, keyed by project and IID
+const statusCacheName = "statuses"
+
+// MergeRequestStatus returns the approval and pipeline state of a merge request of project, e.g. "group/project".
+// Statuses are cached for the cache's TTL, or until the merge request's head commit changes.
+func (c *Client) MergeRequestStatus(project string, mr MergeRequest) (*Status, error) {
+	key := project + "!" + strconv.Itoa(mr.IID)
+	now := c.Time()
+
+	cached := make(map[string]*statusCacheEntry)
+	if c.Cache != nil {
+		c.Cache.Read(c.CacheName(statusCacheName), &cached)
+
+		entry, ok := cached[key]
+		if ok && entry.SHA == mr.SHA && (c.Cache.TTL == 0 || now.Sub(entry.Timestamp) < c.Cache.TTL) {
+			return &entry.Status, nil
+		}
+	}
+
+	status, err := c.fetchStatus(project, mr)
+	if err != nil {
+		// fall back to a stale status of the same commit rather than failing
+		if entry, ok := cached[key]; ok && entry.SHA == mr.SHA && now.Sub(entry.Timestamp) < c.Cache.TTL+c.Cache.MaxStale {
+			return &entry.Status, err
+		}
+		return nil, err
+	}
+
+	if c.Cache != nil {
+		// proceed without failing if the cache can't be written, as we have the status
+		c.Cache.Update(c.CacheName(statusCacheName), statusCacheName, &cached, func() error {
+			for k, entry := range cached {
+				if c.Cache.TTL > 0 && now.Sub(entry.Timestamp) >= c.Cache.TTL+c.Cache.MaxStale {
+					delete(cached, k)
+				}
+			}
+
+			cached[key] = &statusCacheEntry{Timestamp: now, SHA: mr.SHA, Status: *status}
+
+			return nil
+		})
+	}
+
+	return status, nil
+}
+
+// fetchStatus fetches a merge request, which includes its head pipeline, and its approvals.
+func (c *Client) fetchStatus(project string, mr MergeRequest) (*Status, error) {
+	mrURL := c.projectURL(project, "/merge_requests/"+strconv.Itoa(mr.IID))
+
+	body, _, err := c.Get(mrURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var details struct {
+		DetailedMergeStatus string `json:"detailed_merge_status"`
+		HeadPipeline        *struct {
+			Status string `json:"status"`
+		} `json:"head_pipeline"`
+	}
+	if err := json.Unmarshal(body, &details); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+
+	status := &Status{}
+	if details.HeadPipeline != nil {
+		status.Pipeline = PipelineState(details.HeadPipeline.Status)
+	}
+
+	body, _, err = c.Get(mrURL + "/approvals")
+	if err != nil {
+		return nil, err
+	}
+
+	var approvals struct {
+		Approved      bool `json:"approved"`
+		ApprovalsLeft int  `json:"approvals_left"`
+		ApprovedBy    []struct {
+			User struct {
+				Username string `json:"username"`
+			} `json:"user"`
+		} `json:"approved_by"`
+	}
+	if err := json.Unmarshal(body, &approvals); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+
+	// requested changes block merging, whatever the approvals
+	switch {
+	case details.DetailedMergeStatus == "requested_changes":
+		status.Review = ReviewChangesRequested
+	case approvals.ApprovalsLeft > 0:
+		status.Review = ReviewRequired
+	case approvals.Approved && len(approvals.ApprovedBy) > 0:
+		status.Review = ReviewApproved
+	}
+
+	return status, nil
+}
+
+// PipelineState returns the pipeline state for the status of a GitLab pipeline, e.g. "success" or "running", or
+// an empty string for pipelines that won't run by themselves, such as skipped or manual ones.
+func PipelineState(status string) string {
+	switch status {
+	case "success":
+		return PipelinePassing
+	case "failed", "canceled":
+		return PipelineFailing
+	case "created", "waiting_for_resource", "preparing", "pending", "running", "scheduled":
+		return PipelinePending
+	}
+
+	return ""
+}
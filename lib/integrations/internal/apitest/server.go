@@ -0,0 +1,126 @@
+// Package apitest is the scaffolding of the fake API servers provided by the test packages of the integrations:
+// it counts requests, simulates outages, and writes JSON responses. The fake servers only implement their API.
+package apitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/cache/cachetest"
+)
+
+// Server is a test HTTP server whose handler runs while holding the server's lock, which also guards the state of
+// the fake API built on it
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// Requests counts the requests received, keyed by endpoint, e.g. "GET /pulls"
+	Requests map[string]int
+
+	failStatus int
+}
+
+// NewServer starts a server serving requests with handler. Call Close when done.
+func NewServer(handler http.HandlerFunc) *Server {
+	s := &Server{Requests: make(map[string]int)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		handler(w, r)
+	}))
+
+	return s
+}
+
+// Lock locks the server's state, e.g. to change it while the server is running.
+func (s *Server) Lock() {
+	s.mu.Lock()
+}
+
+// Unlock unlocks the server's state.
+func (s *Server) Unlock() {
+	s.mu.Unlock()
+}
+
+// Fail makes the server respond to every request with the given status code, e.g. to simulate an outage.
+// A status of 0 restores normal responses.
+func (s *Server) Fail(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failStatus = status
+}
+
+// RequestCount returns the number of requests received for an endpoint, e.g. "GET /pulls".
+func (s *Server) RequestCount(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.Requests[endpoint]
+}
+
+// Receive counts a request for an endpoint and returns the status code to fail it with, or 0 unless the server
+// simulates an outage. It is called by handlers.
+func (s *Server) Receive(endpoint string) int {
+	s.Requests[endpoint]++
+
+	return s.failStatus
+}
+
+// Page returns the page of items selected by the page parameter and the page size parameter sizeParam, e.g.
+// "per_page", which is capped at maxSize, and the number of the next page, or 0 when it is the last one. A Link
+// header pointing to the next page is set too.
+func (s *Server) Page(w http.ResponseWriter, r *http.Request, items []any, sizeParam string, maxSize int) ([]any, int) {
+	query := r.URL.Query()
+
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	size, err := strconv.Atoi(query.Get(sizeParam))
+	if err != nil || size > maxSize || size < 1 {
+		size = maxSize
+	}
+
+	start := min((page-1)*size, len(items))
+	end := min(start+size, len(items))
+
+	if end == len(items) {
+		return items[start:end], 0
+	}
+
+	query.Set("page", strconv.Itoa(page+1))
+	w.Header().Set("Link", fmt.Sprintf(`<%s%s?%s>; rel="next"`, s.URL, r.URL.EscapedPath(), query.Encode()))
+
+	return items[start:end], page + 1
+}
+
+// CachedOptions returns the options of a client caching in a temporary directory of the test, with a clock the
+// test advances to expire cached data. withCacheDir and withClock are the options of the client's package, e.g.
+// github.WithCacheDir and github.WithClock.
+func CachedOptions[O any](tb testing.TB, withCacheDir func(dir string) O, withClock func(now func() time.Time) O) ([]O, *cachetest.Clock) {
+	clock := cachetest.NewClock()
+
+	return []O{withCacheDir(tb.TempDir()), withClock(clock.Now)}, clock
+}
+
+// WriteJSON writes value as a JSON response with the given status code.
+func WriteJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// WriteError writes an error response with the given status code, in the {"message": ...} form of REST APIs.
+func WriteError(w http.ResponseWriter, status int, message string) {
+	WriteJSON(w, status, map[string]any{"message": message})
+}
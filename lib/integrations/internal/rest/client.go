@@ -0,0 +1,295 @@
+// Package rest is the core of the clients of forges' REST APIs: it makes authenticated requests, follows pages of
+// results, and caches data per account. The forge packages only map the endpoints and responses of their API.
+package rest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/cache"
+)
+
+// Provider describes the REST API of a forge
+type Provider struct {
+	// Name is the name of the forge used in messages, e.g. "GitHub"
+	Name string
+	// CacheDir is the name of the client's directory in the default cache directory, e.g. "github"
+	CacheDir string
+	// Header returns the headers sent with every request, including the token
+	Header func(token string) http.Header
+	// AccountField is the field of the "/user" response identifying the account the token belongs to, e.g. "login"
+	AccountField string
+}
+
+// Client makes requests to the REST API of a forge
+type Client struct {
+	// APIURL is the URL of the REST API, e.g. "https://api.github.com"
+	APIURL string
+	Token  string
+	// HTTPClient is used to make requests; http.DefaultClient is used when it is nil
+	HTTPClient *http.Client
+	// Cache stores the data of the account the token belongs to; nothing is cached when it is nil
+	Cache *cache.Store
+	// Now returns the current time; time.Now is used when it is nil
+	Now func() time.Time
+
+	provider Provider
+
+	// accountOnce looks up account, the identity of the token's account, once
+	accountOnce sync.Once
+	account     string
+}
+
+// Option configures a Client created by NewClient
+type Option func(c *Client)
+
+// WithAPIURL replaces the API URL passed to NewClient, e.g. with the URL of a test server.
+func WithAPIURL(apiURL string) Option {
+	return func(c *Client) {
+		c.APIURL = strings.TrimRight(apiURL, "/")
+	}
+}
+
+// WithHTTPClient makes requests with the given HTTP client.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithTransport makes requests with an HTTP client using the given transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.HTTPClient = &http.Client{Transport: transport}
+	}
+}
+
+// WithCacheDir caches data in dir instead of the default cache directory. An empty dir disables caching.
+func WithCacheDir(dir string) Option {
+	return func(c *Client) {
+		if dir == "" {
+			c.Cache = nil
+			return
+		}
+
+		c.Cache = cache.NewStore(dir)
+	}
+}
+
+// WithClock uses now instead of time.Now to get the current time, including for cache expiry.
+func WithClock(now func() time.Time) Option {
+	return func(c *Client) {
+		c.Now = now
+	}
+}
+
+// NewClient returns a client for the API of provider at apiURL, authenticating with token.
+func NewClient(provider Provider, apiURL, token string, options ...Option) *Client {
+	client := &Client{
+		APIURL:   apiURL,
+		Token:    token,
+		Cache:    cache.NewStore(filepath.Join(cache.DefaultDir(), provider.CacheDir)),
+		provider: provider,
+	}
+
+	for _, option := range options {
+		option(client)
+	}
+
+	if client.Cache != nil && client.Now != nil {
+		client.Cache.Now = client.Now
+	}
+
+	return client
+}
+
+// Time returns the current time according to the client's clock.
+func (c *Client) Time() time.Time {
+	if c.Now == nil {
+		return time.Now()
+	}
+
+	return c.Now()
+}
+
+// Validate reports whether the client has everything it needs to authenticate.
+func (c *Client) Validate() error {
+	if c.APIURL == "" {
+		return fmt.Errorf("the %s base URL is not set", c.provider.Name)
+	}
+
+	if _, err := url.ParseRequestURI(c.APIURL); err != nil {
+		return fmt.Errorf("invalid %s API URL: %v", c.provider.Name, err)
+	}
+
+	if c.Token == "" {
+		return fmt.Errorf("the %s token is not set", c.provider.Name)
+	}
+
+	return nil
+}
+
+// ShortHash returns an abbreviated SHA-256 hash of s, for use in file names.
+func ShortHash(s string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(s)))[:16]
+}
+
+// accountCacheName is the name of the cache file holding the identity of the account the token belongs to
+const accountCacheName = "account"
+
+// Account returns the identity of the account the token belongs to, e.g. its login, or an empty string when it
+// is unknown. Identities are cached per API URL and looked up again once they expire, so that a replaced token of
+// the same account keeps using the same cached data. It is looked up once per client, and is safe for concurrent use.
+func (c *Client) Account() string {
+	c.accountOnce.Do(func() {
+		if c.Cache == nil {
+			return
+		}
+
+		c.Cache.Fetch(ShortHash(c.APIURL)+"/"+accountCacheName, c.APIURL, &c.account, func() (any, error) {
+			body, _, err := c.Get(c.APIURL + "/user")
+			if err != nil {
+				return nil, err
+			}
+
+			var user map[string]any
+			if err := json.Unmarshal(body, &user); err != nil {
+				return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+			}
+
+			account, _ := user[c.provider.AccountField].(string)
+
+			return account, nil
+		})
+	})
+
+	return c.account
+}
+
+// CacheName returns the name of a cache file belonging to the client's account, which is identified by the API URL
+// and the account rather than the token, so that replacing the token keeps the cached data.
+func (c *Client) CacheName(name string) string {
+	return ShortHash(c.APIURL+"\x00"+c.Account()) + "/" + name
+}
+
+// Fetch decodes the data cached for the account under name into v, fetching it like cache.Store.Fetch when it is
+// missing, expired, or was stored with another key. Without a cache, the data is always fetched.
+func (c *Client) Fetch(name string, key string, v any, fetch func() (any, error)) (*cache.Metadata, error) {
+	if c.Cache != nil {
+		return c.Cache.Fetch(c.CacheName(name), key, v, fetch)
+	}
+
+	data, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cache.Metadata{Key: key, FetchedAt: c.Time()}, json.Unmarshal(encoded, v)
+}
+
+// nextLink matches the URL of the next page in a Link header
+var nextLink = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// Get performs an authenticated GET request against the API and returns the response body, and the URL of the
+// next page of results when there is one.
+func (c *Client) Get(requestURL string) ([]byte, string, error) {
+	body, header, err := c.do(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if match := nextLink.FindStringSubmatch(header.Get("Link")); match != nil {
+		next = match[1]
+	}
+
+	return body, next, nil
+}
+
+// Post performs an authenticated POST request against the API, sending payload as JSON, and returns the response body.
+func (c *Client) Post(requestURL string, payload any) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %v", err)
+	}
+
+	body, _, err := c.do(http.MethodPost, requestURL, data)
+
+	return body, err
+}
+
+// do performs an authenticated request against the API and returns the response body and headers.
+func (c *Client) do(method string, requestURL string, payload []byte) ([]byte, http.Header, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var requestBody io.Reader
+	if payload != nil {
+		requestBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, requestBody)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create HTTP request: %v", err)
+	}
+
+	if c.provider.Header != nil {
+		for name, values := range c.provider.Header(c.Token) {
+			req.Header[name] = values
+		}
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute HTTP request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, nil, &APIError{Forge: c.provider.Name, StatusCode: resp.StatusCode, Body: string(bodyBytes)}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	return body, resp.Header, nil
+}
+
+// APIError is returned when the API responds with an unsuccessful status code
+type APIError struct {
+	// Forge is the name of the forge, e.g. "GitHub"
+	Forge      string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API returned status %d: %s", e.Forge, e.StatusCode, e.Body)
+}
@@ -0,0 +1,215 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/cache/cachetest"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/internal/apitest"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/internal/rest"
+)
+
+// provider is a forge authenticating with tokens in the "token" scheme
+var provider = rest.Provider{
+	Name:     "Forge",
+	CacheDir: "forge",
+	Header: func(token string) http.Header {
+		return http.Header{"Authorization": {"token " + token}}
+	},
+	AccountField: "login",
+}
+
+// server is a fake forge serving the items of the account "octocat", two per page
+type server struct {
+	*apitest.Server
+
+	Token string
+	Items []any
+}
+
+func newServer(t *testing.T, items ...any) *server {
+	s := &server{Token: "forge-test", Items: items}
+	s.Server = apitest.NewServer(func(w http.ResponseWriter, r *http.Request) {
+		if status := s.Receive(r.Method + " " + r.URL.Path); status != 0 {
+			apitest.WriteError(w, status, "simulated failure")
+			return
+		}
+
+		if r.Header.Get("Authorization") != "token "+s.Token {
+			apitest.WriteError(w, http.StatusUnauthorized, "Bad credentials")
+			return
+		}
+
+		switch r.URL.Path {
+		case "/user":
+			apitest.WriteJSON(w, http.StatusOK, map[string]any{"login": "octocat"})
+		case "/items":
+			page, _ := s.Page(w, r, s.Items, "per_page", 2)
+			apitest.WriteJSON(w, http.StatusOK, page)
+		default:
+			apitest.WriteError(w, http.StatusNotFound, "Not Found")
+		}
+	})
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// client returns a client for the server, with caching disabled unless an option enables it.
+func (s *server) client(options ...rest.Option) *rest.Client {
+	return rest.NewClient(provider, s.URL, s.Token, append([]rest.Option{rest.WithCacheDir("")}, options...)...)
+}
+
+// cachedClient returns a client for the server caching in a temporary directory of the test, with a clock the
+// test advances.
+func (s *server) cachedClient(t *testing.T) (*rest.Client, *cachetest.Clock) {
+	cached, clock := apitest.CachedOptions(t, rest.WithCacheDir, rest.WithClock)
+
+	return s.client(cached...), clock
+}
+
+// fetchItems fetches the items on every page.
+func fetchItems(c *rest.Client) ([]string, error) {
+	result := make([]string, 0)
+	next := c.APIURL + "/items"
+
+	for next != "" {
+		body, nextPage, err := c.Get(next)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []string
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+
+		result = append(result, page...)
+		next = nextPage
+	}
+
+	return result, nil
+}
+
+// fetchCachedItems fetches the items like fetchItems, through the client's cache.
+func fetchCachedItems(c *rest.Client) ([]string, error) {
+	var items []string
+
+	meta, err := c.Fetch("items", "all", &items, func() (any, error) {
+		return fetchItems(c)
+	})
+	if err == nil && meta.LastError != "" {
+		err = errors.New(meta.LastError)
+	}
+
+	return items, err
+}
+
+func TestClientRejectsInvalidTokens(t *testing.T) {
+	server := newServer(t, "a")
+
+	_, err := fetchItems(rest.NewClient(provider, server.URL, "wrong", rest.WithCacheDir("")))
+
+	var apiErr *rest.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Forge != "Forge" {
+		t.Errorf("expected a 401 APIError of the forge, got %v", err)
+	}
+
+	invalid := map[string]*rest.Client{
+		"without a base URL":  rest.NewClient(provider, "", "forge-test"),
+		"with an invalid URL": rest.NewClient(provider, "example.com", "forge-test"),
+		"without a token":     rest.NewClient(provider, server.URL, ""),
+	}
+	for name, client := range invalid {
+		if client.Validate() == nil {
+			t.Errorf("expected a client %s to be invalid", name)
+		}
+	}
+
+	if err := server.client().Validate(); err != nil {
+		t.Errorf("expected the client to be valid, got %v", err)
+	}
+}
+
+func TestClientFollowsPages(t *testing.T) {
+	server := newServer(t, "a", "b", "c", "d", "e")
+
+	items, err := fetchItems(server.client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 5 || server.RequestCount("GET /items") != 3 {
+		t.Errorf("expected 5 items in 3 pages, got %v in %d", items, server.RequestCount("GET /items"))
+	}
+}
+
+func TestClientFallsBackToStaleDataDuringAnOutage(t *testing.T) {
+	server := newServer(t, "a", "b", "c")
+	client, clock := server.cachedClient(t)
+
+	if _, err := fetchCachedItems(client); err != nil {
+		t.Fatal(err)
+	}
+
+	server.Fail(http.StatusBadGateway)
+	clock.Advance(client.Cache.TTL + time.Minute)
+
+	items, err := fetchCachedItems(client)
+	if len(items) != 3 || err == nil {
+		t.Errorf("expected 3 stale items with the refresh error, got %v, %v", items, err)
+	}
+
+	clock.Advance(client.Cache.MaxStale)
+
+	if _, err := fetchCachedItems(client); err == nil {
+		t.Error("expected an error once the items are too old to use")
+	}
+}
+
+func TestClientKeepsTheCacheWhenTheTokenIsReplaced(t *testing.T) {
+	server := newServer(t, "a")
+	client, clock := server.cachedClient(t)
+
+	if _, err := fetchCachedItems(client); err != nil {
+		t.Fatal(err)
+	}
+
+	server.Token = "replaced"
+	replaced := server.client(rest.WithCacheDir(client.Cache.Dir), rest.WithClock(clock.Now))
+
+	if _, err := fetchCachedItems(replaced); err != nil {
+		t.Fatal(err)
+	}
+
+	if count := server.RequestCount("GET /items"); count != 1 {
+		t.Errorf("expected the cached items to be used with a replaced token, got %d requests", count)
+	}
+}
+
+func TestClientLooksUpTheAccountOnce(t *testing.T) {
+	server := newServer(t)
+	client, _ := server.cachedClient(t)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.CacheName("items")
+		}()
+	}
+	wg.Wait()
+
+	if account := client.Account(); account != "octocat" {
+		t.Errorf("expected the account octocat, got %q", account)
+	}
+
+	if count := server.RequestCount("GET /user"); count != 1 {
+		t.Errorf("expected the account to be looked up once, got %d requests", count)
+	}
+}
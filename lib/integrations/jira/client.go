@@ -0,0 +1,325 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/cache"
+)
+
+// AuthMethod is the way a Client authenticates with Jira
+type AuthMethod string
+
+const (
+	// AuthBasic authenticates with an email address and API token, as used by Jira Cloud
+	AuthBasic AuthMethod = "basic"
+	// AuthBearer authenticates with a Personal Access Token, as used by Jira Server and Data Center
+	AuthBearer AuthMethod = "bearer"
+)
+
+// Client makes requests to the REST API of a Jira Cloud, Server or Data Center instance
+type Client struct {
+	// BaseURL is the URL of the Jira instance, e.g. "https://example.atlassian.net" or "https://jira.example.com"
+	BaseURL string
+	Email   string
+	Token   string
+	Auth    AuthMethod
+	// APIVersion is "3" for Jira Cloud or "2" for Jira Server and Data Center
+	APIVersion string
+	// HTTPClient is used to make requests; http.DefaultClient is used when it is nil
+	HTTPClient *http.Client
+	// Cache stores search results, looked up issues and transition IDs; nothing is cached when it is nil
+	Cache *cache.Store
+	// Now returns the current time; time.Now is used when it is nil
+	Now func() time.Time
+
+	// user identifies the user a Personal Access Token belongs to, once it has been looked up
+	user *string
+}
+
+// Option configures a Client created by NewClient
+type Option func(c *Client)
+
+// WithBaseURL replaces the base URL passed to NewClient, e.g. with the URL of a test server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.BaseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient makes requests with the given HTTP client.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithTransport makes requests with an HTTP client using the given transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.HTTPClient = &http.Client{Transport: transport}
+	}
+}
+
+// WithCacheDir caches data in dir instead of the default cache directory. An empty dir disables caching.
+func WithCacheDir(dir string) Option {
+	return func(c *Client) {
+		if dir == "" {
+			c.Cache = nil
+			return
+		}
+
+		c.Cache = cache.NewStore(dir)
+	}
+}
+
+// WithClock uses now instead of time.Now to get the current time, including for cache expiry.
+func WithClock(now func() time.Time) Option {
+	return func(c *Client) {
+		c.Now = now
+	}
+}
+
+// CloudURL returns the base URL of a Jira Cloud site.
+func CloudURL(subdomain string) string {
+	return "https://" + subdomain + ".atlassian.net"
+}
+
+// IsCloudURL reports whether baseURL belongs to a Jira Cloud site.
+func IsCloudURL(baseURL string) bool {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+
+	return strings.HasSuffix(strings.ToLower(parsed.Hostname()), ".atlassian.net")
+}
+
+// NewClient returns a client for the Jira instance at baseURL. Basic auth is used when an email address
+// is given, bearer auth with a Personal Access Token otherwise. Jira Cloud sites use version 3 of the
+// REST API, while other instances are assumed to be Server or Data Center and use version 2.
+func NewClient(baseURL, email, token string, options ...Option) *Client {
+	client := &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Email:      email,
+		Token:      token,
+		Auth:       AuthBearer,
+		APIVersion: "2",
+		Cache:      cache.NewStore(filepath.Join(cache.DefaultDir(), "jira")),
+	}
+
+	for _, option := range options {
+		option(client)
+	}
+
+	if email != "" {
+		client.Auth = AuthBasic
+	}
+
+	if IsCloudURL(client.BaseURL) {
+		client.APIVersion = "3"
+	}
+
+	if client.Cache != nil && client.Now != nil {
+		client.Cache.Now = client.Now
+	}
+
+	return client
+}
+
+// now returns the current time according to the client's clock.
+func (c *Client) now() time.Time {
+	if c.Now == nil {
+		return time.Now()
+	}
+
+	return c.Now()
+}
+
+// Validate reports whether the client has everything it needs to authenticate.
+func (c *Client) Validate() error {
+	if c.BaseURL == "" {
+		return errors.New("the Jira base URL is not set")
+	}
+
+	if _, err := url.ParseRequestURI(c.BaseURL); err != nil {
+		return fmt.Errorf("invalid Jira base URL: %v", err)
+	}
+
+	if c.Token == "" {
+		return errors.New("the Jira API token is not set")
+	}
+
+	switch c.Auth {
+	case AuthBasic:
+		if c.Email == "" {
+			return errors.New("basic auth requires an email address")
+		}
+	case AuthBearer:
+	default:
+		return fmt.Errorf("unknown Jira auth method '%s', expected basic or bearer", c.Auth)
+	}
+
+	if c.APIVersion != "2" && c.APIVersion != "3" {
+		return fmt.Errorf("unsupported Jira API version '%s', expected 2 or 3", c.APIVersion)
+	}
+
+	return nil
+}
+
+// apiURL returns the URL of a REST API endpoint, e.g. apiURL("/search").
+func (c *Client) apiURL(path string) string {
+	return fmt.Sprintf("%s/rest/api/%s%s", c.BaseURL, c.APIVersion, path)
+}
+
+// IssueURL returns the URL of an issue's page in the Jira web interface.
+func (c *Client) IssueURL(issueKey string) string {
+	return c.BaseURL + "/browse/" + issueKey
+}
+
+// shortHash returns an abbreviated SHA-256 hash of s, for use in file names.
+func shortHash(s string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(s)))[:16]
+}
+
+// accountCacheName is the name of the cache file holding the user a Personal Access Token belongs to
+const accountCacheName = "account"
+
+// account identifies the user the client authenticates as: the email address used with basic auth, or the user a
+// Personal Access Token belongs to, which is cached per instance and looked up again once it expires. An empty
+// string is returned when the user is unknown.
+func (c *Client) account() string {
+	if c.Auth == AuthBasic {
+		return c.Email
+	}
+
+	if c.user != nil {
+		return *c.user
+	}
+
+	user := ""
+	if c.Cache != nil {
+		c.Cache.Fetch(shortHash(c.BaseURL)+"/"+accountCacheName, c.BaseURL, &user, func() (any, error) {
+			body, err := c.get(c.apiURL("/myself"))
+			if err != nil {
+				return nil, err
+			}
+
+			// Jira Cloud identifies users by account ID, Jira Server and Data Center by key
+			var myself struct {
+				AccountID string `json:"accountId"`
+				Key       string `json:"key"`
+			}
+			if err := json.Unmarshal(body, &myself); err != nil {
+				return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+			}
+
+			if myself.AccountID != "" {
+				return myself.AccountID, nil
+			}
+
+			return myself.Key, nil
+		})
+	}
+
+	c.user = &user
+
+	return user
+}
+
+// cacheName returns the name of a cache file belonging to the client's account, which is identified by the
+// instance and the user rather than the token, so that replacing the token keeps the cached data.
+func (c *Client) cacheName(name string) string {
+	return shortHash(strings.Join([]string{c.BaseURL, string(c.Auth), c.account()}, "\x00")) + "/" + name
+}
+
+// get performs an authenticated GET request against the Jira API and returns the response body.
+func (c *Client) get(requestURL string) ([]byte, error) {
+	return c.do("GET", requestURL, nil)
+}
+
+// post performs an authenticated POST request against the Jira API, sending payload as JSON, and returns the response body.
+func (c *Client) post(requestURL string, payload any) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %v", err)
+	}
+
+	return c.do("POST", requestURL, data)
+}
+
+// do performs an authenticated request against the Jira API and returns the response body.
+func (c *Client) do(method string, requestURL string, payload []byte) ([]byte, error) {
+	// Create a new HTTP request with context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var requestBody io.Reader
+	if payload != nil {
+		requestBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %v", err)
+	}
+
+	if c.Auth == AuthBearer {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	} else {
+		// Basic Auth uses the email address and API token
+		auth := c.Email + ":" + c.Token
+		req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(auth)))
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	// Execute the request
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute HTTP request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Check for non-2xx status codes
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(bodyBytes)}
+	}
+
+	// Read the response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	return body, nil
+}
+
+// APIError is returned when the Jira API responds with an unsuccessful status code
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Jira API returned status %d: %s", e.StatusCode, e.Body)
+}
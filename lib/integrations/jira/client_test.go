@@ -0,0 +1,102 @@
+package jira_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/cache/cachetest"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/jira"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/jira/jiratest"
+)
+
+func TestClientAuthenticatesWithBasicOrBearerAuth(t *testing.T) {
+	server := jiratest.NewServer(jiratest.NewIssues("ABC", 3)...)
+	defer server.Close()
+
+	for _, client := range []*jira.Client{
+		jira.NewClient(server.URL, jiratest.Email, "wrong-token", jira.WithCacheDir("")),
+		jira.NewClient(server.URL, "", "wrong-token", jira.WithCacheDir("")),
+	} {
+		_, err := client.SearchIssues("project = ABC")
+
+		var apiErr *jira.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+			t.Errorf("expected a 401 APIError with %s auth, got %v", client.Auth, err)
+		}
+	}
+
+	if _, err := jira.NewClient(server.URL, "", jiratest.Token, jira.WithCacheDir("")).SearchIssues("project = ABC"); err != nil {
+		t.Errorf("expected bearer auth to succeed, got %v", err)
+	}
+}
+
+func TestClientCachesResultsUntilTheTTLExpires(t *testing.T) {
+	server := jiratest.NewServer(jiratest.NewIssues("ABC", 3)...)
+	defer server.Close()
+
+	client, clock := server.CachedClient(t)
+
+	client.Issues("project = ABC")
+	clock.Advance(client.Cache.TTL / 2)
+	client.Issues("project = ABC")
+
+	if count := server.RequestCount("GET /search"); count != 1 {
+		t.Errorf("expected 1 search request before the TTL, got %d", count)
+	}
+
+	clock.Advance(client.Cache.TTL)
+
+	_, meta, err := client.IssuesWithMetadata("project = ABC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count := server.RequestCount("GET /search"); count != 2 {
+		t.Errorf("expected 2 search requests after the TTL, got %d", count)
+	}
+
+	if !meta.FetchedAt.Equal(clock.Now()) {
+		t.Errorf("expected the results to be fetched at %s, got %s", clock.Now(), meta.FetchedAt)
+	}
+}
+
+func TestClientKeepsTheCacheWhenTheTokenIsReplaced(t *testing.T) {
+	server := jiratest.NewServer(jiratest.NewIssues("ABC", 3)...)
+	defer server.Close()
+
+	// basic auth identifies the account by email address, bearer auth by the token's user
+	for _, email := range []string{jiratest.Email, ""} {
+		dir, clock := t.TempDir(), cachetest.NewClock()
+		newClient := func(email string) *jira.Client {
+			return jira.NewClient(server.URL, email, server.Token, jira.WithCacheDir(dir), jira.WithClock(clock.Now))
+		}
+
+		server.Token = jiratest.Token
+		searches := server.RequestCount("GET /search")
+
+		if _, err := newClient(email).Issues("project = ABC"); err != nil {
+			t.Fatal(err)
+		}
+
+		server.Token = "replaced-token"
+		replaced := newClient(email)
+
+		if _, err := replaced.Issues("project = ABC"); err != nil {
+			t.Fatal(err)
+		}
+
+		if count := server.RequestCount("GET /search") - searches; count != 1 {
+			t.Errorf("expected the cached results to be used with a replaced %s token, got %d searches", replaced.Auth, count)
+		}
+
+		if email == "" {
+			continue
+		}
+
+		// the server doesn't know the other account, so it can only get results from the cache
+		if _, err := newClient("someone@example.com").Issues("project = ABC"); err == nil {
+			t.Error("expected the cached results of another account not to be used")
+		}
+	}
+}
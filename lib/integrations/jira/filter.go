@@ -0,0 +1,130 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IssueFilter selects issues by status category, project and priority. Empty lists match every issue.
+type IssueFilter struct {
+	// StatusCategories are status category keys: "new", "indeterminate" or "done"
+	StatusCategories []string
+	Projects         []string
+	Priorities       []string
+}
+
+// ParseStatusCategory returns the key of a status category given its key or a common name, such as
+// "todo", "in-progress" or "done".
+func ParseStatusCategory(name string) (string, error) {
+	switch strings.ToLower(strings.NewReplacer(" ", "", "-", "", "_", "").Replace(name)) {
+	case "new", "todo", "open":
+		return StatusCategoryToDo, nil
+	case "indeterminate", "inprogress":
+		return StatusCategoryInProgress, nil
+	case "done", "closed":
+		return StatusCategoryDone, nil
+	}
+
+	return "", fmt.Errorf("unknown status category '%s', expected todo, in-progress or done", name)
+}
+
+// statusCategoryNames maps the keys of status categories to their names in JQL
+var statusCategoryNames = map[string]string{
+	StatusCategoryToDo:       "To Do",
+	StatusCategoryInProgress: "In Progress",
+	StatusCategoryDone:       "Done",
+}
+
+// JQL returns jql restricted to the issues selected by the filter, so that Jira applies the filter to every page of
+// results. The status categories replace those of a statusCategory clause of jql, so that they can select issues the
+// query excludes, e.g. done ones; this requires the clauses of jql to be joined with AND only.
+func (f IssueFilter) JQL(jql string) string {
+	clauses, orderBy, onlyAnd := splitJQL(jql)
+
+	if !onlyAnd {
+		clauses = []string{"(" + strings.Join(clauses, " AND ") + ")"}
+	}
+
+	result := make([]string, 0, len(clauses)+3)
+	for _, clause := range clauses {
+		field, _, _ := strings.Cut(strings.TrimLeft(clause, "("), " ")
+		if onlyAnd && len(f.StatusCategories) > 0 && strings.EqualFold(field, "statusCategory") {
+			continue
+		}
+
+		result = append(result, clause)
+	}
+
+	categories := make([]string, 0, len(f.StatusCategories))
+	for _, key := range f.StatusCategories {
+		if name, ok := statusCategoryNames[key]; ok {
+			key = name
+		}
+		categories = append(categories, key)
+	}
+
+	result = appendInClause(result, "statusCategory", categories)
+	result = appendInClause(result, "project", f.Projects)
+	result = appendInClause(result, "priority", f.Priorities)
+
+	return strings.TrimSpace(strings.Join(result, " AND ") + " " + orderBy)
+}
+
+// appendInClause appends a clause selecting issues whose field has one of values, unless values is empty.
+func appendInClause(clauses []string, field string, values []string) []string {
+	if len(values) == 0 {
+		return clauses
+	}
+
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, `"`+strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)+`"`)
+	}
+
+	return append(clauses, field+" in ("+strings.Join(quoted, ", ")+")")
+}
+
+// splitJQL splits a query into the clauses joined by AND outside of parentheses and quotes, and its ORDER BY
+// part. When OR joins clauses too, onlyAnd is false, since the clauses are not independent of each other.
+func splitJQL(jql string) (clauses []string, orderBy string, onlyAnd bool) {
+	onlyAnd = true
+	depth, quote, start := 0, byte(0), 0
+
+	keywordAt := func(i int, keyword string) bool {
+		end := i + len(keyword)
+		return (i == 0 || jql[i-1] == ' ' || jql[i-1] == ')') && end <= len(jql) &&
+			strings.EqualFold(jql[i:end], keyword) && (end == len(jql) || jql[end] == ' ' || jql[end] == '(')
+	}
+
+	for i := 0; i < len(jql); i++ {
+		switch c := jql[i]; {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth != 0:
+		case keywordAt(i, "order by"):
+			orderBy = strings.TrimSpace(jql[i:])
+			jql = jql[:i]
+		case keywordAt(i, "and"):
+			clauses = append(clauses, strings.TrimSpace(jql[start:i]))
+			start = i + len("and")
+		case keywordAt(i, "or"):
+			onlyAnd = false
+		}
+	}
+
+	if clause := strings.TrimSpace(jql[start:]); clause != "" {
+		clauses = append(clauses, clause)
+	}
+
+	return clauses, orderBy, onlyAnd
+}
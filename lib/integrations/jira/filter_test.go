@@ -0,0 +1,47 @@
+package jira_test
+
+import (
+	"testing"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/jira"
+)
+
+func TestIssueFilterJQL(t *testing.T) {
+	tests := []struct {
+		filter    jira.IssueFilter
+		jql, want string
+	}{
+		{
+			jira.IssueFilter{},
+			jira.DefaultJQL,
+			jira.DefaultJQL,
+		},
+		{
+			jira.IssueFilter{StatusCategories: []string{jira.StatusCategoryDone}},
+			jira.DefaultJQL,
+			`assignee = currentUser() AND sprint in openSprints() AND statusCategory in ("Done") ORDER BY updated DESC`,
+		},
+		{
+			jira.IssueFilter{Projects: []string{"ABC", "DEF"}, Priorities: []string{`Say "high"`}},
+			`project = ABC order by rank`,
+			`project = ABC AND project in ("ABC", "DEF") AND priority in ("Say \"high\"") order by rank`,
+		},
+		{
+			// clauses joined with OR, or inside parentheses and quotes, are kept as they are
+			jira.IssueFilter{StatusCategories: []string{jira.StatusCategoryToDo}},
+			`statusCategory = Done OR summary ~ "a AND b"`,
+			`(statusCategory = Done OR summary ~ "a AND b") AND statusCategory in ("To Do")`,
+		},
+		{
+			jira.IssueFilter{StatusCategories: []string{jira.StatusCategoryInProgress}},
+			`(assignee = currentUser() AND statusCategory != Done) AND status not in (Blocked)`,
+			`(assignee = currentUser() AND statusCategory != Done) AND status not in (Blocked) AND statusCategory in ("In Progress")`,
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.filter.JQL(test.jql); got != test.want {
+			t.Errorf("%+v on %q:\n got %s\nwant %s", test.filter, test.jql, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// issueFields lists the fields requested for each issue
+const issueFields = "summary,status,issuetype,priority,assignee,updated"
+
+// searchPageSize is the number of issues requested per page; Jira may return fewer
+const searchPageSize = 100
+
+// maxSearchResults limits the number of issues fetched by a single search
+const maxSearchResults = 1000
+
+// ErrSearchTruncated is returned with the issues of a search that matched more than maxSearchResults issues, of
+// which only the first are included
+var ErrSearchTruncated = fmt.Errorf("the search matched more than %d issues; only the first %d are included", maxSearchResults, maxSearchResults)
+
+// Status categories reported by Jira, independent of the names of a project's workflow statuses
+const (
+	StatusCategoryToDo       = "new"
+	StatusCategoryInProgress = "indeterminate"
+	StatusCategoryDone       = "done"
+)
+
+// Issue is a single Jira issue
+type Issue struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
+	Status  string `json:"status"`
+	// StatusCategory is the key of the status's category: "new", "indeterminate" or "done"
+	StatusCategory string `json:"status_category"`
+	// Type is the name of the issue type, e.g. "Bug"
+	Type     string    `json:"type"`
+	Priority string    `json:"priority"`
+	Assignee string    `json:"assignee"`
+	Updated  time.Time `json:"updated"`
+}
+
+// IsDone reports whether the issue's status belongs to the "Done" category.
+func (i Issue) IsDone() bool {
+	return i.StatusCategory == StatusCategoryDone
+}
+
+// IsInProgress reports whether the issue's status belongs to the "In Progress" category.
+func (i Issue) IsInProgress() bool {
+	return i.StatusCategory == StatusCategoryInProgress
+}
+
+// SearchResult is the result of a search for issues
+type SearchResult struct {
+	Issues []Issue `json:"issues"`
+	// Truncated reports that the search matched more issues than a single search fetches, and only the first
+	// ones are included
+	Truncated bool `json:"truncated"`
+}
+
+// jiraTimeLayout is the format of timestamps returned by the Jira API
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// jiraIssueResponse represents the structure of an issue in Jira's issue and search API responses
+type jiraIssueResponse struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name           string `json:"name"`
+			StatusCategory struct {
+				Key string `json:"key"`
+			} `json:"statusCategory"`
+		} `json:"status"`
+		IssueType *struct {
+			Name string `json:"name"`
+		} `json:"issuetype"`
+		Priority *struct {
+			Name string `json:"name"`
+		} `json:"priority"`
+		Assignee *struct {
+			DisplayName string `json:"displayName"`
+		} `json:"assignee"`
+		Updated string `json:"updated"`
+	} `json:"fields"`
+}
+
+// jiraSearchResponse represents the structure of a page of Jira's search API responses: /search pages with
+// startAt and total, while /search/jql, which replaces it in version 3, pages with nextPageToken
+type jiraSearchResponse struct {
+	StartAt       int                 `json:"startAt"`
+	MaxResults    int                 `json:"maxResults"`
+	Total         int                 `json:"total"`
+	NextPageToken string              `json:"nextPageToken"`
+	Issues        []jiraIssueResponse `json:"issues"`
+}
+
+func (r jiraIssueResponse) toIssue() Issue {
+	issue := Issue{
+		Key:            r.Key,
+		Summary:        r.Fields.Summary,
+		Status:         r.Fields.Status.Name,
+		StatusCategory: r.Fields.Status.StatusCategory.Key,
+	}
+
+	if r.Fields.IssueType != nil {
+		issue.Type = r.Fields.IssueType.Name
+	}
+
+	if r.Fields.Priority != nil {
+		issue.Priority = r.Fields.Priority.Name
+	}
+
+	if r.Fields.Assignee != nil {
+		issue.Assignee = r.Fields.Assignee.DisplayName
+	}
+
+	if updated, err := time.Parse(jiraTimeLayout, r.Fields.Updated); err == nil {
+		issue.Updated = updated
+	}
+
+	return issue
+}
+
+// GetIssue fetches a single issue.
+func (c *Client) GetIssue(issueKey string) (*Issue, error) {
+	body, err := c.get(c.apiURL("/issue/" + url.PathEscape(issueKey) + "?fields=" + issueFields))
+	if err != nil {
+		return nil, err
+	}
+
+	var result jiraIssueResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+
+	issue := result.toIssue()
+
+	return &issue, nil
+}
+
+// SearchIssues returns the issues matched by a JQL query, following the pages of the search results up to
+// maxSearchResults issues. Version 3 of the API uses /search/jql, since Jira Cloud removed /search, and version 2
+// uses /search.
+func (c *Client) SearchIssues(jql string) (*SearchResult, error) {
+	if c.APIVersion == "2" {
+		return c.searchIssuesFrom(jql)
+	}
+
+	return c.searchIssuesByToken(jql)
+}
+
+// searchIssuesByToken follows the pages of /search/jql, each pointing to the next one with a token.
+func (c *Client) searchIssuesByToken(jql string) (*SearchResult, error) {
+	searchURL, err := url.Parse(c.apiURL("/search/jql"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Jira base URL: %v", err)
+	}
+
+	result := &SearchResult{Issues: make([]Issue, 0), Truncated: true}
+	pageToken := ""
+
+	for len(result.Issues) < maxSearchResults {
+		query := searchURL.Query()
+		query.Set("jql", jql)
+		query.Set("fields", issueFields)
+		query.Set("maxResults", strconv.Itoa(searchPageSize))
+		query.Del("nextPageToken")
+		if pageToken != "" {
+			query.Set("nextPageToken", pageToken)
+		}
+		searchURL.RawQuery = query.Encode()
+
+		body, err := c.get(searchURL.String())
+		if err != nil {
+			return nil, err
+		}
+
+		var page jiraSearchResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+		}
+
+		for _, issue := range page.Issues {
+			result.Issues = append(result.Issues, issue.toIssue())
+		}
+
+		// the last page has no token
+		if len(page.Issues) == 0 || page.NextPageToken == "" {
+			result.Truncated = false
+			break
+		}
+
+		pageToken = page.NextPageToken
+	}
+
+	return result, nil
+}
+
+// searchIssuesFrom follows the pages of /search by their offsets, until the total number of issues is reached.
+func (c *Client) searchIssuesFrom(jql string) (*SearchResult, error) {
+	searchURL, err := url.Parse(c.apiURL("/search"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Jira base URL: %v", err)
+	}
+
+	result := &SearchResult{Issues: make([]Issue, 0), Truncated: true}
+
+	for startAt := 0; startAt < maxSearchResults; {
+		query := searchURL.Query()
+		query.Set("jql", jql)
+		query.Set("fields", issueFields)
+		query.Set("startAt", strconv.Itoa(startAt))
+		query.Set("maxResults", strconv.Itoa(searchPageSize))
+		searchURL.RawQuery = query.Encode()
+
+		body, err := c.get(searchURL.String())
+		if err != nil {
+			return nil, err
+		}
+
+		var page jiraSearchResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+		}
+
+		for _, issue := range page.Issues {
+			result.Issues = append(result.Issues, issue.toIssue())
+		}
+
+		startAt += len(page.Issues)
+
+		// stop at the last page, or if the server returns an empty page before reaching the total
+		if len(page.Issues) == 0 || startAt >= page.Total {
+			result.Truncated = false
+			break
+		}
+	}
+
+	return result, nil
+}
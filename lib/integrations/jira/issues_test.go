@@ -0,0 +1,67 @@
+package jira_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/jira"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/jira/jiratest"
+)
+
+func TestSearchIssuesFollowsPages(t *testing.T) {
+	// version 2 pages with startAt, version 3 with nextPageToken, since Jira Cloud removed /search
+	for _, version := range []string{"2", "3"} {
+		server := jiratest.NewServer(jiratest.NewIssues("ABC", 120)...)
+		defer server.Close()
+		server.PageSize = 25
+
+		client := server.Client()
+		client.APIVersion = version
+
+		result, err := client.SearchIssues("project = ABC")
+		if err != nil {
+			t.Fatalf("version %s: %v", version, err)
+		}
+
+		issues := result.Issues
+		if result.Truncated {
+			t.Errorf("version %s: expected the complete results", version)
+		}
+
+		if len(issues) != 120 {
+			t.Fatalf("version %s: expected 120 issues, got %d", version, len(issues))
+		}
+
+		if issues[119].Key != "ABC-120" {
+			t.Errorf("version %s: expected the last issue to be ABC-120, got %s", version, issues[119].Key)
+		}
+
+		if count := server.RequestCount("GET /search"); count != 5 {
+			t.Errorf("version %s: expected 5 search requests, got %d", version, count)
+		}
+	}
+}
+
+func TestSearchIssuesReportsTruncatedResults(t *testing.T) {
+	for _, version := range []string{"2", "3"} {
+		server := jiratest.NewServer(jiratest.NewIssues("ABC", 1050)...)
+		defer server.Close()
+
+		client := server.Client()
+		client.APIVersion = version
+
+		result, err := client.SearchIssues("project = ABC")
+		if err != nil {
+			t.Fatalf("version %s: %v", version, err)
+		}
+
+		if len(result.Issues) != 1000 || !result.Truncated {
+			t.Errorf("version %s: expected the first 1000 issues of truncated results, got %d, truncated: %v", version, len(result.Issues), result.Truncated)
+		}
+
+		issues, err := client.Issues("project = ABC")
+		if len(issues) != 1000 || !errors.Is(err, jira.ErrSearchTruncated) {
+			t.Errorf("version %s: expected 1000 issues with ErrSearchTruncated, got %d, %v", version, len(issues), err)
+		}
+	}
+}
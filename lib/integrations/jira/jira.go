@@ -1,236 +1,76 @@
 package jira
 
 import (
-	"context"
-	"crypto/sha256"
-	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
 	"os"
-	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
-	"sync"
-	"time"
-)
-
-// JiraSearchResponse represents the structure of Jira's search API response
-type JiraSearchResponse struct {
-	Issues []struct {
-		ID    string `json:"id"`
-		Key   string `json:"key"`
-		Title string `json:"fields"` // Assuming 'fields' contains 'summary' or similar for title
-	} `json:"issues"`
-}
-
-// IssueCache represents the structure of the cache file
-type IssueCache struct {
-	Timestamp time.Time `json:"timestamp"`
-	JiraHash  string    `json:"jira_hash"`
-	IssueIDs  []string  `json:"issue_ids"`
-}
 
-var (
-	// Mutex to ensure thread-safe access to the cache file
-	cacheMutex sync.Mutex
+	"github.com/permafrost-dev/git-ninja/lib/integrations/cache"
 )
 
-func GetJiraCacheFileName() string {
-	cwd, err := os.UserHomeDir()
-
-	if err != nil {
-		cwd = os.Getenv("HOME")
-	}
-
-	cacheFileName := cwd + "/.gitninja.jira-cache.json"
-
-	return cacheFileName
-}
+// DefaultJQL fetches the current user's unresolved issues in open sprints
+const DefaultJQL = `assignee = currentUser() AND sprint in openSprints() AND statusCategory IN ("To Do", "In Progress") ORDER BY updated DESC`
 
 // GetCurrentUserActiveIssueIDs queries Jira for the current user's active issues and returns their IDs.
-// It caches the response in cache.json and only fetches new data if the cache is older than 5 minutes.
 func GetCurrentUserActiveIssueIDs(jiraBaseURL, email, apiToken string) ([]string, error) {
-
-	const cacheDuration = 5 * time.Minute
-	cacheFileName := GetJiraCacheFileName()
-
-	cacheMutex.Lock()
-	defer cacheMutex.Unlock()
-
-	// Attempt to read from cache
-	cachedData, err := readCache(cacheFileName)
-	if err == nil {
-		if getCurrentJiraHash() != cachedData.JiraHash {
-			// If the Jira hash has changed, skip returning the cached data
-		} else if time.Since(cachedData.Timestamp) < cacheDuration {
-			return cachedData.IssueIDs, nil
-		}
-	}
-
-	// If cache is invalid or reading failed, fetch new data
-	issueIDs, err := fetchJiraIssues(jiraBaseURL, email, apiToken)
-	if err != nil {
-		// If fetching fails but cache exists, return cached data
-		if cachedData != nil {
-			// fmt.Printf("Warning: Failed to fetch new data: %v. Returning cached data.\n", err)
-			return cachedData.IssueIDs, nil
-		}
-		return nil, err
-	}
-
-	// Update cache with new data
-	err = writeCache(cacheFileName, issueIDs)
-	if err != nil {
-		// fmt.Printf("Warning: Failed to write cache: %v\n", err)
-		// Proceed without failing, as we have the issue IDs
-	}
-
-	return issueIDs, nil
+	return NewClient(jiraBaseURL, email, apiToken).IssueIDs(DefaultJQL)
 }
 
-// fetchJiraIssues performs the HTTP request to Jira and retrieves the active issue IDs.
-func fetchJiraIssues(jiraBaseURL, email, apiToken string) ([]string, error) {
-	// Define the JQL query
-	// This JQL fetches issues assigned to the current user that are not resolved or closed.
-	jql := `assignee = currentUser()  AND sprint in openSprints() AND
-	statusCategory IN ("To Do", "In Progress")
-	ORDER BY updated DESC`
-
-	// Prepare the request URL
-	// Jira's search API endpoint
-	searchURL, err := url.Parse(fmt.Sprintf("%s/rest/api/3/search", jiraBaseURL))
-	if err != nil {
-		return nil, fmt.Errorf("invalid Jira base URL: %v", err)
-	}
-
-	// Set query parameters
-	query := searchURL.Query()
-	query.Set("jql", jql)
-	query.Set("fields", "id,key")  // We need the issue ID and Key
-	query.Set("maxResults", "100") // Adjust as needed
-	searchURL.RawQuery = query.Encode()
-
-	// Create a new HTTP request with context
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(ctx, "GET", searchURL.String(), nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %v", err)
-	}
-
-	// Set authentication headers using Basic Auth (email and API token)
-	auth := email + ":" + apiToken
-	encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
-	req.Header.Set("Authorization", "Basic "+encodedAuth)
-	req.Header.Set("Accept", "application/json")
-
-	// Initialize HTTP client
-	client := &http.Client{}
-
-	// Execute the request
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute HTTP request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	// Check for non-2xx status codes
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Jira API returned status %d: %s", resp.StatusCode, string(bodyBytes))
-	}
-
-	// Read the response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
-	}
-
-	// Parse the JSON response
-	var searchResult JiraSearchResponse
-	if err := json.Unmarshal(body, &searchResult); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON response: %v", err)
-	}
-
-	// Extract issue IDs
-	var issueIDs []string
-	for _, issue := range searchResult.Issues {
-		issueIDs = append(issueIDs, issue.Key) // Using Key instead of ID for readability
+// IssueIDs queries Jira for the issues matched by a JQL query and returns their IDs.
+func (c *Client) IssueIDs(jql string) ([]string, error) {
+	issues, err := c.Issues(jql)
+	if err != nil && !errors.Is(err, ErrSearchTruncated) {
+		return nil, err
 	}
 
-	return issueIDs, nil
-}
-
-func getCurrentJiraHash() string {
-	jiraVars := []string{
-		os.Getenv("JIRA_SUBDOMAIN"),
-		os.Getenv("JIRA_EMAIL_ADDRESS"),
-		os.Getenv("JIRA_API_TOKEN"),
+	issueIDs := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		issueIDs = append(issueIDs, issue.Key)
 	}
 
-	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(jiraVars, ""))))
+	return issueIDs, err
 }
 
-// readCache reads the cache file and returns the cached data.
-// Returns an error if the file doesn't exist or is invalid.
-func readCache(cacheFileName string) (*IssueCache, error) {
-	filePath, err := filepath.Abs(cacheFileName)
+// Issues queries Jira for the issues matched by a JQL query.
+// The results are cached per query until the cache's TTL expires. When the query matches more issues than a
+// search fetches, the first ones are returned with ErrSearchTruncated.
+func (c *Client) Issues(jql string) ([]Issue, error) {
+	result, _, err := c.IssuesWithMetadata(jql)
 	if err != nil {
-		return nil, fmt.Errorf("failed to determine absolute path for cache file: %v", err)
-	}
-
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err // File doesn't exist or cannot be opened
+		return nil, err
 	}
-	defer file.Close()
 
-	var cache IssueCache
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&cache); err != nil {
-		return nil, fmt.Errorf("failed to decode cache file: %v", err)
+	if result.Truncated {
+		return result.Issues, ErrSearchTruncated
 	}
 
-	return &cache, nil
+	return result.Issues, nil
 }
 
-// writeCache writes the issue IDs and current timestamp to the cache file.
-func writeCache(cacheFileName string, issueIDs []string) error {
-	filePath, err := filepath.Abs(cacheFileName)
-	if err != nil {
-		return fmt.Errorf("failed to determine absolute path for cache file: %v", err)
-	}
-
-	cache := IssueCache{
-		Timestamp: time.Now(),
-		JiraHash:  getCurrentJiraHash(),
-		IssueIDs:  issueIDs,
+// IssuesWithMetadata queries Jira for the issues matched by a JQL query, and returns the metadata of the
+// cached results, which describes their age and whether they are stale because fetching them again failed.
+func (c *Client) IssuesWithMetadata(jql string) (*SearchResult, *cache.Metadata, error) {
+	if c.Cache == nil {
+		result, err := c.SearchIssues(jql)
+		return result, &cache.Metadata{Key: jql, FetchedAt: c.now()}, err
 	}
 
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create cache file: %v", err)
-	}
-	defer file.Close()
+	var result *SearchResult
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ") // For readability
-	if err := encoder.Encode(&cache); err != nil {
-		return fmt.Errorf("failed to encode cache data: %v", err)
-	}
+	meta, err := c.Cache.Fetch(c.cacheName("search-"+shortHash(jql)), jql, &result, func() (any, error) {
+		return c.SearchIssues(jql)
+	})
 
-	return nil
+	return result, meta, err
 }
 
 // GetJiraTicketIDs is an example usage of GetCurrentUserActiveIssueIDs.
 // It fetches and prints the active Jira issue IDs assigned to the current user.
 func GetJiraTicketIDs(subdomain string, email string) []string {
-	jiraBaseURL := "https://" + subdomain + ".atlassian.net"
+	jiraBaseURL := CloudURL(subdomain)
 	apiToken := os.Getenv("JIRA_API_TOKEN")
 
 	if apiToken == "" {
@@ -272,3 +112,35 @@ func HashJiraIssueKey(issueKey string) (int64, error) {
 
 	return num, nil
 }
+
+// DefaultIssueKeyPattern matches JIRA issue keys such as "ABC-123"
+const DefaultIssueKeyPattern = `[A-Z][A-Z0-9]+-[0-9]+`
+
+// IssueKeyPattern matches JIRA issue keys in branch names and commit messages
+var IssueKeyPattern = regexp.MustCompile(DefaultIssueKeyPattern)
+
+// SetIssueKeyPattern replaces the pattern used to find issue keys. An empty pattern restores the default.
+func SetIssueKeyPattern(pattern string) error {
+	if pattern == "" {
+		pattern = DefaultIssueKeyPattern
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid issue key pattern: %v", err)
+	}
+
+	IssueKeyPattern = compiled
+
+	return nil
+}
+
+// ExtractIssueKey returns the first JIRA issue key found in s, such as a branch name, or an empty string.
+func ExtractIssueKey(s string) string {
+	return IssueKeyPattern.FindString(s)
+}
+
+// ExtractIssueKeys returns the distinct JIRA issue keys found in s, such as a commit message, in order of appearance.
+func ExtractIssueKeys(s string) []string {
+	return uniqueStrings(IssueKeyPattern.FindAllString(s, -1))
+}
@@ -0,0 +1,399 @@
+// Package jiratest provides a fake Jira REST API server for testing code that uses the jira package
+// without contacting a real Jira instance.
+package jiratest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/cache/cachetest"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/internal/apitest"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/jira"
+)
+
+// Credentials accepted by a Server unless they are changed
+const (
+	Email = "dev@example.com"
+	Token = "test-token"
+)
+
+// Step is a status of a workflow, with its status category
+type Step struct {
+	Status, Category string
+}
+
+// Workflow lists the statuses issues move through, unless their type has its own workflow in the server's
+// Workflows. Each status has a single transition to the next one, whose ID is ten times the index of the next
+// status, so that the IDs of different workflows overlap like they do in Jira.
+var Workflow = []Step{
+	{"To Do", jira.StatusCategoryToDo},
+	{"In Progress", jira.StatusCategoryInProgress},
+	{"In Review", jira.StatusCategoryInProgress},
+	{"Done", jira.StatusCategoryDone},
+}
+
+// Worklog is a worklog posted to a Server
+type Worklog struct {
+	ID               string
+	Issue            string
+	Started          string
+	TimeSpentSeconds int
+	Comment          any
+}
+
+// Server is a fake Jira instance serving versions 2 and 3 of the REST API: the current user, issue search with
+// pagination, single issues, transitions and worklogs. Like Jira Cloud, version 3 searches with /search/jql and
+// fetches issues by key with /issue/bulkfetch, and no longer serves /search. It accepts basic auth with Email and
+// Token, or bearer auth with Token. Searches return every issue, except for "key in (...)" queries, which return
+// the listed issues.
+type Server struct {
+	*apitest.Server
+
+	// Issues are the issues served, in search result order
+	Issues []jira.Issue
+	// PageSize caps the number of issues in a page of search results, like Jira's own limit
+	PageSize int
+	// Email and Token are the accepted credentials
+	Email string
+	Token string
+	// Workflows maps issue types to their own workflows
+	Workflows map[string][]Step
+	// Worklogs are the worklogs posted so far
+	Worklogs []Worklog
+}
+
+var apiPath = regexp.MustCompile(`^/rest/api/([23])(/.*)$`)
+
+// NewServer starts a server serving the given issues. Call Close when done.
+func NewServer(issues ...jira.Issue) *Server {
+	s := &Server{Issues: issues, PageSize: 50, Email: Email, Token: Token}
+	s.Server = apitest.NewServer(s.handle)
+
+	return s
+}
+
+// NewIssues returns count issues in project, numbered from 1, in the first status of the workflow.
+func NewIssues(project string, count int) []jira.Issue {
+	result := make([]jira.Issue, 0, count)
+
+	for i := 1; i <= count; i++ {
+		result = append(result, jira.Issue{
+			Key:            fmt.Sprintf("%s-%d", project, i),
+			Summary:        fmt.Sprintf("Issue %d", i),
+			Status:         Workflow[0].Status,
+			StatusCategory: Workflow[0].Category,
+			Type:           "Task",
+			Priority:       "Medium",
+			Assignee:       "Dev",
+			Updated:        time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		})
+	}
+
+	return result
+}
+
+// Client returns a client for the server using basic auth, with caching disabled unless an option enables it.
+func (s *Server) Client(options ...jira.Option) *jira.Client {
+	return jira.NewClient(s.URL, s.Email, s.Token, append([]jira.Option{jira.WithCacheDir("")}, options...)...)
+}
+
+// CachedClient returns a client for the server caching in a temporary directory of the test, with a clock the
+// test advances to expire cached data.
+func (s *Server) CachedClient(tb testing.TB, options ...jira.Option) (*jira.Client, *cachetest.Clock) {
+	cached, clock := apitest.CachedOptions(tb, jira.WithCacheDir, jira.WithClock)
+
+	return s.Client(append(cached, options...)...), clock
+}
+
+// Issue returns the current state of an issue, or nil if it doesn't exist.
+func (s *Server) Issue(key string) *jira.Issue {
+	s.Lock()
+	defer s.Unlock()
+
+	if issue := s.findIssue(key); issue != nil {
+		copied := *issue
+		return &copied
+	}
+
+	return nil
+}
+
+func (s *Server) findIssue(key string) *jira.Issue {
+	for i := range s.Issues {
+		if s.Issues[i].Key == key {
+			return &s.Issues[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+		return token == s.Token
+	}
+
+	if encoded, ok := strings.CutPrefix(header, "Basic "); ok {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		return err == nil && string(decoded) == s.Email+":"+s.Token
+	}
+
+	return false
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	match := apiPath.FindStringSubmatch(r.URL.Path)
+	if match == nil {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	version, path := match[1], match[2]
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	endpoint := "/" + parts[0]
+	if len(parts) > 2 {
+		endpoint += "/" + parts[2]
+	}
+	if status := s.Receive(r.Method + " " + endpoint); status != 0 {
+		writeError(w, status, "simulated failure")
+		return
+	}
+
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "authentication failed")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && path == "/myself":
+		name, _, _ := strings.Cut(s.Email, "@")
+		writeJSON(w, http.StatusOK, map[string]any{"key": name, "name": name, "emailAddress": s.Email})
+	case r.Method == http.MethodGet && path == "/search" && version == "3":
+		writeError(w, http.StatusGone, "The requested API has been removed. Please migrate to the /rest/api/3/search/jql API.")
+	case r.Method == http.MethodGet && path == "/search":
+		s.search(w, r, false)
+	case r.Method == http.MethodGet && path == "/search/jql" && version == "3":
+		s.search(w, r, true)
+	case r.Method == http.MethodPost && path == "/issue/bulkfetch" && version == "3":
+		s.bulkFetch(w, r)
+	case parts[0] == "issue" && len(parts) >= 2:
+		issue := s.findIssue(parts[1])
+		if issue == nil {
+			writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+			return
+		}
+
+		switch {
+		case len(parts) == 2 && r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, issueResponse(*issue))
+		case len(parts) == 3 && parts[2] == "transitions":
+			s.transitions(w, r, issue)
+		case len(parts) == 3 && parts[2] == "worklog" && r.Method == http.MethodPost:
+			s.addWorklog(w, r, issue)
+		default:
+			writeError(w, http.StatusNotFound, "not found")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+var keyInQuery = regexp.MustCompile(`(?i)^key in \(([^)]*)\)$`)
+
+// search writes a page of search results. Pages of /search/jql are selected with nextPageToken, those of /search
+// with startAt.
+func (s *Server) search(w http.ResponseWriter, r *http.Request, tokens bool) {
+	query := r.URL.Query()
+
+	issues := s.Issues
+	if match := keyInQuery.FindStringSubmatch(strings.TrimSpace(query.Get("jql"))); match != nil {
+		issues = s.findIssues(strings.Split(match[1], ","))
+	}
+
+	startAt, _ := strconv.Atoi(query.Get("startAt"))
+	if tokens {
+		startAt = 0
+		if token := query.Get("nextPageToken"); token != "" {
+			offset, err := base64.RawURLEncoding.DecodeString(token)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "Invalid nextPageToken")
+				return
+			}
+			startAt, _ = strconv.Atoi(string(offset))
+		}
+	}
+
+	maxResults, err := strconv.Atoi(query.Get("maxResults"))
+	if err != nil || maxResults > s.PageSize {
+		maxResults = s.PageSize
+	}
+
+	page := make([]any, 0)
+	for i := startAt; i < len(issues) && i < startAt+maxResults; i++ {
+		page = append(page, issueResponse(issues[i]))
+	}
+
+	if !tokens {
+		writeJSON(w, http.StatusOK, map[string]any{"startAt": startAt, "maxResults": maxResults, "total": len(issues), "issues": page})
+		return
+	}
+
+	response := map[string]any{"issues": page, "isLast": true}
+	if next := startAt + len(page); next < len(issues) {
+		response["nextPageToken"] = base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(next)))
+		response["isLast"] = false
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// bulkFetch writes the issues with the requested keys, and an error for each unknown key.
+func (s *Server) bulkFetch(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		IssueIdsOrKeys []string `json:"issueIdsOrKeys"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || len(request.IssueIdsOrKeys) > 100 {
+		writeError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	issues := make([]any, 0)
+	issueErrors := make([]any, 0)
+
+	for _, key := range request.IssueIdsOrKeys {
+		if issue := s.findIssue(key); issue != nil {
+			issues = append(issues, issueResponse(*issue))
+		} else {
+			issueErrors = append(issueErrors, map[string]any{"issueIdsOrKeys": []string{key}, "type": "NOT_FOUND"})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"issues": issues, "issueErrors": issueErrors})
+}
+
+// findIssues returns the issues with the given keys, ignoring unknown keys.
+func (s *Server) findIssues(keys []string) []jira.Issue {
+	result := make([]jira.Issue, 0)
+	for _, key := range keys {
+		if issue := s.findIssue(strings.TrimSpace(key)); issue != nil {
+			result = append(result, *issue)
+		}
+	}
+
+	return result
+}
+
+func (s *Server) workflow(issue *jira.Issue) []Step {
+	if workflow, ok := s.Workflows[issue.Type]; ok {
+		return workflow
+	}
+
+	return Workflow
+}
+
+func (s *Server) transitions(w http.ResponseWriter, r *http.Request, issue *jira.Issue) {
+	workflow := s.workflow(issue)
+
+	next := -1
+	for i, step := range workflow {
+		if step.Status == issue.Status && i+1 < len(workflow) {
+			next = i + 1
+		}
+	}
+
+	if r.Method == http.MethodGet {
+		transitions := make([]any, 0)
+		if next >= 0 {
+			transitions = append(transitions, map[string]any{
+				"id":   strconv.Itoa(next * 10),
+				"name": "Move to " + workflow[next].Status,
+				"to":   map[string]any{"name": workflow[next].Status},
+			})
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{"transitions": transitions})
+		return
+	}
+
+	var payload struct {
+		Transition struct {
+			ID string `json:"id"`
+		} `json:"transition"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if next < 0 || payload.Transition.ID != strconv.Itoa(next*10) {
+		writeError(w, http.StatusBadRequest, "It is not possible to perform the transition.")
+		return
+	}
+
+	issue.Status, issue.StatusCategory = workflow[next].Status, workflow[next].Category
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) addWorklog(w http.ResponseWriter, r *http.Request, issue *jira.Issue) {
+	var payload struct {
+		Started          string `json:"started"`
+		TimeSpentSeconds int    `json:"timeSpentSeconds"`
+		Comment          any    `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.TimeSpentSeconds < 60 {
+		writeError(w, http.StatusBadRequest, "invalid worklog")
+		return
+	}
+
+	worklog := Worklog{
+		ID:               strconv.Itoa(10000 + len(s.Worklogs)),
+		Issue:            issue.Key,
+		Started:          payload.Started,
+		TimeSpentSeconds: payload.TimeSpentSeconds,
+		Comment:          payload.Comment,
+	}
+	s.Worklogs = append(s.Worklogs, worklog)
+
+	writeJSON(w, http.StatusCreated, map[string]any{"id": worklog.ID})
+}
+
+// issueResponse returns an issue in the structure used by Jira's issue and search API responses.
+func issueResponse(issue jira.Issue) map[string]any {
+	fields := map[string]any{
+		"summary": issue.Summary,
+		"status":  map[string]any{"name": issue.Status, "statusCategory": map[string]any{"key": issue.StatusCategory}},
+		"updated": issue.Updated.Format("2006-01-02T15:04:05.000-0700"),
+	}
+
+	if issue.Type != "" {
+		fields["issuetype"] = map[string]any{"name": issue.Type}
+	}
+
+	if issue.Priority != "" {
+		fields["priority"] = map[string]any{"name": issue.Priority}
+	}
+
+	if issue.Assignee != "" {
+		fields["assignee"] = map[string]any{"displayName": issue.Assignee}
+	}
+
+	return map[string]any{"key": issue.Key, "fields": fields}
+}
+
+// writeJSON writes the responses of the fake API
+var writeJSON = apitest.WriteJSON
+
+// writeError writes an error response in the form used by Jira.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{"errorMessages": []string{message}})
+}
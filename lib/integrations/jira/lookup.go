@@ -0,0 +1,174 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CachedIssue is an issue looked up by key. Issue is nil when no issue exists with that key.
+type CachedIssue struct {
+	Timestamp time.Time `json:"timestamp"`
+	Issue     *Issue    `json:"issue"`
+}
+
+// issueLookupCacheName is the name of the cache file holding issues looked up by key
+const issueLookupCacheName = "issues"
+
+// LookupIssues returns the issues with the given keys, keyed by issue key. Keys without an issue are
+// omitted. Issues are cached for the cache's TTL, and the remaining keys are fetched with a single search.
+// When the search fails, expired cached issues are returned along with the error.
+func (c *Client) LookupIssues(keys []string) (map[string]*Issue, error) {
+	if c.Cache == nil {
+		return c.fetchIssuesByKey(uniqueStrings(keys))
+	}
+
+	cached := make(map[string]*CachedIssue)
+	c.Cache.Read(c.cacheName(issueLookupCacheName), &cached)
+
+	result := make(map[string]*Issue)
+	missing := make([]string, 0)
+	now := c.now()
+
+	for _, key := range uniqueStrings(keys) {
+		entry, ok := cached[key]
+		if ok && (c.Cache.TTL == 0 || now.Sub(entry.Timestamp) < c.Cache.TTL) {
+			if entry.Issue != nil {
+				result[key] = entry.Issue
+			}
+			continue
+		}
+
+		missing = append(missing, key)
+	}
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	found, err := c.fetchIssuesByKey(missing)
+	if err != nil {
+		// fall back to stale cached data rather than failing
+		for _, key := range missing {
+			if entry, ok := cached[key]; ok && entry.Issue != nil && now.Sub(entry.Timestamp) < c.Cache.TTL+c.Cache.MaxStale {
+				result[key] = entry.Issue
+			}
+		}
+		return result, err
+	}
+
+	for _, key := range missing {
+		if found[key] != nil {
+			result[key] = found[key]
+		}
+	}
+
+	// merge with issues cached by other processes in the meantime, dropping entries that can no longer be used;
+	// proceed without failing if the cache can't be written, as we have the issues
+	c.Cache.Update(c.cacheName(issueLookupCacheName), issueLookupCacheName, &cached, func() error {
+		for key, entry := range cached {
+			if c.Cache.TTL > 0 && now.Sub(entry.Timestamp) >= c.Cache.TTL+c.Cache.MaxStale {
+				delete(cached, key)
+			}
+		}
+
+		for _, key := range missing {
+			cached[key] = &CachedIssue{Timestamp: now, Issue: found[key]}
+		}
+
+		return nil
+	})
+
+	return result, nil
+}
+
+// fetchIssuesByKey fetches issues by key, in batches. Unknown keys are ignored rather than failing the request.
+func (c *Client) fetchIssuesByKey(keys []string) (map[string]*Issue, error) {
+	result := make(map[string]*Issue)
+
+	for start := 0; start < len(keys); start += searchPageSize {
+		batch := keys[start:min(start+searchPageSize, len(keys))]
+
+		fetch := c.bulkFetchIssues
+		if c.APIVersion == "2" {
+			fetch = c.searchIssuesByKey
+		}
+
+		responses, err := fetch(batch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, response := range responses {
+			issue := response.toIssue()
+			result[issue.Key] = &issue
+		}
+	}
+
+	return result, nil
+}
+
+// searchIssuesByKey searches for issues by key with /search, where validateQuery=warn ignores unknown keys.
+func (c *Client) searchIssuesByKey(keys []string) ([]jiraIssueResponse, error) {
+	searchURL, err := url.Parse(c.apiURL("/search"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Jira base URL: %v", err)
+	}
+
+	query := searchURL.Query()
+	query.Set("jql", "key in ("+strings.Join(keys, ",")+")")
+	query.Set("fields", issueFields)
+	query.Set("maxResults", fmt.Sprint(searchPageSize))
+	query.Set("validateQuery", "warn")
+	searchURL.RawQuery = query.Encode()
+
+	body, err := c.get(searchURL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var page jiraSearchResponse
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+
+	return page.Issues, nil
+}
+
+// bulkFetchIssues fetches issues by key with /issue/bulkfetch of version 3, which reports unknown keys as errors
+// alongside the issues it finds.
+func (c *Client) bulkFetchIssues(keys []string) ([]jiraIssueResponse, error) {
+	body, err := c.post(c.apiURL("/issue/bulkfetch"), map[string]any{
+		"issueIdsOrKeys": keys,
+		"fields":         strings.Split(issueFields, ","),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Issues []jiraIssueResponse `json:"issues"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+
+	return response.Issues, nil
+}
+
+// uniqueStrings returns items without duplicates, in their original order.
+func uniqueStrings(items []string) []string {
+	result := make([]string, 0, len(items))
+	seen := make(map[string]bool)
+
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
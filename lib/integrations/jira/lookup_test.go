@@ -0,0 +1,36 @@
+package jira_test
+
+import (
+	"testing"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/jira/jiratest"
+)
+
+func TestLookupIssuesInOneBatch(t *testing.T) {
+	// version 2 searches for the keys, version 3 fetches them with /issue/bulkfetch
+	endpoints := map[string]string{"2": "GET /search", "3": "POST /issue"}
+
+	for version, endpoint := range endpoints {
+		server := jiratest.NewServer(jiratest.NewIssues("ABC", 10)...)
+		defer server.Close()
+
+		client, _ := server.CachedClient(t)
+		client.APIVersion = version
+
+		issues, err := client.LookupIssues([]string{"ABC-2", "ABC-7", "ABC-404", "ABC-2"})
+		if err != nil {
+			t.Fatalf("version %s: %v", version, err)
+		}
+
+		if len(issues) != 2 || issues["ABC-2"] == nil || issues["ABC-7"] == nil {
+			t.Errorf("version %s: expected ABC-2 and ABC-7, got %v", version, issues)
+		}
+
+		// unknown keys are cached too
+		client.LookupIssues([]string{"ABC-7", "ABC-404"})
+
+		if count := server.RequestCount(endpoint); count != 1 {
+			t.Errorf("version %s: expected 1 request, got %d", version, count)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package jira
+
+import (
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+)
+
+// Tracker makes a Client usable as an integrations.IssueTracker. Active issues are those matched by a JQL query.
+type Tracker struct {
+	Client *Client
+	// JQL selects the current user's active issues
+	JQL string
+}
+
+// NewTracker returns an issue tracker using client, whose active issues are those matched by jql.
+func NewTracker(client *Client, jql string) *Tracker {
+	return &Tracker{Client: client, JQL: jql}
+}
+
+func (t *Tracker) Name() string {
+	return "jira"
+}
+
+func (t *Tracker) Validate() error {
+	return t.Client.Validate()
+}
+
+func (t *Tracker) ActiveIssues() ([]integrations.Issue, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	issues, err := t.Client.Issues(t.JQL)
+
+	result := make([]integrations.Issue, 0, len(issues))
+	for _, issue := range issues {
+		result = append(result, t.trackerIssue(issue))
+	}
+
+	return result, err
+}
+
+func (t *Tracker) GetIssue(key string) (*integrations.Issue, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	issue, err := t.Client.GetIssue(key)
+	if err != nil {
+		return nil, err
+	}
+
+	result := t.trackerIssue(*issue)
+
+	return &result, nil
+}
+
+func (t *Tracker) LookupIssues(keys []string) (map[string]*integrations.Issue, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	issues, err := t.Client.LookupIssues(keys)
+
+	result := make(map[string]*integrations.Issue, len(issues))
+	for key, issue := range issues {
+		converted := t.trackerIssue(*issue)
+		result[key] = &converted
+	}
+
+	return result, err
+}
+
+func (t *Tracker) TransitionIssue(key string, status string) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	return t.Client.TransitionIssue(key, status)
+}
+
+func (t *Tracker) MatchBranch(branchName string) string {
+	return ExtractIssueKey(branchName)
+}
+
+// statusCategories maps Jira's status category keys to those of the integrations package
+var statusCategories = map[string]string{
+	StatusCategoryToDo:       integrations.StatusToDo,
+	StatusCategoryInProgress: integrations.StatusInProgress,
+	StatusCategoryDone:       integrations.StatusDone,
+}
+
+func (t *Tracker) trackerIssue(issue Issue) integrations.Issue {
+	return integrations.Issue{
+		Key:            issue.Key,
+		Summary:        issue.Summary,
+		Status:         issue.Status,
+		StatusCategory: statusCategories[issue.StatusCategory],
+		Priority:       issue.Priority,
+		Assignee:       issue.Assignee,
+		Updated:        issue.Updated,
+		URL:            t.Client.IssueURL(issue.Key),
+	}
+}
@@ -0,0 +1,148 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+)
+
+// Transition is a workflow transition available for an issue
+type Transition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// To is the name of the status the transition moves the issue to
+	To string `json:"to"`
+}
+
+// Matches reports whether the transition's name or target status is name, ignoring case.
+func (t Transition) Matches(name string) bool {
+	return strings.EqualFold(t.Name, name) || strings.EqualFold(t.To, name)
+}
+
+// transitionCacheName is the name of the cache file holding transition IDs. Transition IDs are defined by
+// workflows, which Jira assigns per project and issue type, so they are cached per project, issue type and
+// transition name.
+const transitionCacheName = "transitions"
+
+// ErrTransitionNotFound is returned when an issue has no transition with the requested name
+var ErrTransitionNotFound = integrations.ErrTransitionNotFound
+
+// jiraTransitionsResponse represents the structure of Jira's transitions API response
+type jiraTransitionsResponse struct {
+	Transitions []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		To   struct {
+			Name string `json:"name"`
+		} `json:"to"`
+	} `json:"transitions"`
+}
+
+// GetTransitions returns the transitions currently available for an issue.
+func (c *Client) GetTransitions(issueKey string) ([]Transition, error) {
+	body, err := c.get(c.apiURL("/issue/" + url.PathEscape(issueKey) + "/transitions"))
+	if err != nil {
+		return nil, err
+	}
+
+	var response jiraTransitionsResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+
+	result := make([]Transition, 0, len(response.Transitions))
+	for _, t := range response.Transitions {
+		result = append(result, Transition{ID: t.ID, Name: t.Name, To: t.To.Name})
+	}
+
+	return result, nil
+}
+
+// DoTransition performs the transition with the given ID on an issue.
+func (c *Client) DoTransition(issueKey string, transitionID string) error {
+	payload := map[string]any{"transition": map[string]string{"id": transitionID}}
+
+	_, err := c.post(c.apiURL("/issue/"+url.PathEscape(issueKey)+"/transitions"), payload)
+
+	return err
+}
+
+// TransitionIssue moves an issue using the transition named name, which may also be the name of the target
+// status. A cached transition ID of the issue's workflow is tried first; when it is rejected, the issue's
+// transitions are fetched again. ErrTransitionNotFound is returned when the issue has no matching transition.
+func (c *Client) TransitionIssue(issueKey string, name string) error {
+	cacheKey := ""
+
+	ids := make(map[string]string)
+	if c.Cache != nil {
+		// the workflow is unknown without the issue type, in which case the cache isn't used
+		if issues, _ := c.LookupIssues([]string{issueKey}); issues[issueKey] != nil && issues[issueKey].Type != "" {
+			cacheKey = transitionCacheKey(issueKey, issues[issueKey].Type, name)
+			c.Cache.Read(c.cacheName(transitionCacheName), &ids)
+		}
+	}
+
+	if id, ok := ids[cacheKey]; ok {
+		err := c.DoTransition(issueKey, id)
+
+		var apiErr *APIError
+		if err == nil || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+			return err
+		}
+
+		// the transition isn't available from the issue's current status, or the workflow changed
+		c.updateTransitionCache(cacheKey, "")
+	}
+
+	transitions, err := c.GetTransitions(issueKey)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range transitions {
+		if !t.Matches(name) {
+			continue
+		}
+
+		if err := c.DoTransition(issueKey, t.ID); err != nil {
+			return err
+		}
+
+		c.updateTransitionCache(cacheKey, t.ID)
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s has no transition to '%s'", ErrTransitionNotFound, issueKey, name)
+}
+
+// transitionCacheKey returns the cache key of a transition of an issue type, e.g. "ABC/bug/in progress".
+func transitionCacheKey(issueKey string, issueType string, name string) string {
+	project, _, _ := strings.Cut(issueKey, "-")
+
+	return project + "/" + strings.ToLower(issueType) + "/" + strings.ToLower(name)
+}
+
+// updateTransitionCache caches the ID of a transition, or removes it from the cache when id is empty.
+func (c *Client) updateTransitionCache(cacheKey string, id string) {
+	if c.Cache == nil || cacheKey == "" {
+		return
+	}
+
+	ids := make(map[string]string)
+
+	c.Cache.Update(c.cacheName(transitionCacheName), transitionCacheName, &ids, func() error {
+		if id == "" {
+			delete(ids, cacheKey)
+		} else {
+			ids[cacheKey] = id
+		}
+
+		return nil
+	})
+}
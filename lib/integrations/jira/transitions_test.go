@@ -0,0 +1,55 @@
+package jira_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/jira"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/jira/jiratest"
+)
+
+func TestTransitionIssueByStatusName(t *testing.T) {
+	server := jiratest.NewServer(jiratest.NewIssues("ABC", 1)...)
+	defer server.Close()
+
+	client, _ := server.CachedClient(t)
+
+	if err := client.TransitionIssue("ABC-1", "In Progress"); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := server.Issue("ABC-1").Status; status != "In Progress" {
+		t.Errorf("expected ABC-1 to be In Progress, got %s", status)
+	}
+
+	if err := client.TransitionIssue("ABC-1", "To Do"); !errors.Is(err, jira.ErrTransitionNotFound) {
+		t.Errorf("expected ErrTransitionNotFound, got %v", err)
+	}
+}
+
+func TestTransitionIssueDoesNotReuseTransitionsOfOtherWorkflows(t *testing.T) {
+	issues := jiratest.NewIssues("ABC", 2)
+	issues[1].Type = "Bug"
+
+	server := jiratest.NewServer(issues...)
+	defer server.Close()
+
+	// the first transition of bugs has the same ID as the first transition of tasks
+	server.Workflows = map[string][]jiratest.Step{
+		"Bug": {{Status: "To Do", Category: jira.StatusCategoryToDo}, {Status: "Triaged", Category: jira.StatusCategoryToDo}},
+	}
+
+	client, _ := server.CachedClient(t)
+
+	if err := client.TransitionIssue("ABC-1", "In Progress"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.TransitionIssue("ABC-2", "In Progress"); !errors.Is(err, jira.ErrTransitionNotFound) {
+		t.Errorf("expected ErrTransitionNotFound for the bug, got %v", err)
+	}
+
+	if status := server.Issue("ABC-2").Status; status != "To Do" {
+		t.Errorf("expected ABC-2 to stay To Do, got %s", status)
+	}
+}
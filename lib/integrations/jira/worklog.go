@@ -0,0 +1,62 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// jiraWorklogResponse represents the structure of Jira's response to adding a worklog
+type jiraWorklogResponse struct {
+	ID string `json:"id"`
+}
+
+// AddWorklog logs time spent on an issue, starting at started, and returns the ID of the new worklog. Jira
+// records time in whole minutes, so timeSpent must be at least a minute.
+func (c *Client) AddWorklog(issueKey string, started time.Time, timeSpent time.Duration, comment string) (string, error) {
+	if timeSpent < time.Minute {
+		return "", errors.New("time spent must be at least one minute")
+	}
+
+	payload := map[string]any{
+		"started":          started.Format(jiraTimeLayout),
+		"timeSpentSeconds": int(timeSpent.Truncate(time.Minute).Seconds()),
+	}
+
+	if comment != "" {
+		payload["comment"] = c.textBody(comment)
+	}
+
+	body, err := c.post(c.apiURL("/issue/"+url.PathEscape(issueKey)+"/worklog"), payload)
+	if err != nil {
+		return "", err
+	}
+
+	var response jiraWorklogResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+
+	return response.ID, nil
+}
+
+// textBody returns a plain text value for a rich text field, such as a comment. Version 3 of the REST API
+// expects Atlassian Document Format, while version 2 accepts the text itself.
+func (c *Client) textBody(text string) any {
+	if c.APIVersion != "3" {
+		return text
+	}
+
+	return map[string]any{
+		"type":    "doc",
+		"version": 1,
+		"content": []any{
+			map[string]any{
+				"type":    "paragraph",
+				"content": []any{map[string]any{"type": "text", "text": text}},
+			},
+		},
+	}
+}
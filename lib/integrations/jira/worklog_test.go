@@ -0,0 +1,24 @@
+package jira_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/jira/jiratest"
+)
+
+func TestAddWorklog(t *testing.T) {
+	server := jiratest.NewServer(jiratest.NewIssues("ABC", 1)...)
+	defer server.Close()
+
+	started := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+
+	id, err := server.Client().AddWorklog("ABC-1", started, 90*time.Minute+20*time.Second, "Work on ABC-1-login")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id == "" || len(server.Worklogs) != 1 || server.Worklogs[0].TimeSpentSeconds != 5400 {
+		t.Errorf("expected one worklog of 5400 seconds, got %+v", server.Worklogs)
+	}
+}
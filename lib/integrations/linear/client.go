@@ -0,0 +1,281 @@
+// Package linear is a client for the GraphQL API of Linear, the issue tracker.
+package linear
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/cache"
+)
+
+// DefaultAPIURL is the endpoint of Linear's GraphQL API
+const DefaultAPIURL = "https://api.linear.app/graphql"
+
+// Client makes requests to Linear's GraphQL API, authenticating with a personal API key
+type Client struct {
+	// APIURL is the GraphQL endpoint, DefaultAPIURL unless it is replaced, e.g. by a test server
+	APIURL string
+	APIKey string
+	// HTTPClient is used to make requests; http.DefaultClient is used when it is nil
+	HTTPClient *http.Client
+	// Cache stores active issues, looked up issues and workflow states; nothing is cached when it is nil
+	Cache *cache.Store
+	// Now returns the current time; time.Now is used when it is nil
+	Now func() time.Time
+
+	// viewerID is the ID of the API key's user, once it has been looked up
+	viewerID *string
+}
+
+// Option configures a Client created by NewClient
+type Option func(c *Client)
+
+// WithAPIURL replaces the GraphQL endpoint, e.g. with the URL of a test server.
+func WithAPIURL(apiURL string) Option {
+	return func(c *Client) {
+		c.APIURL = apiURL
+	}
+}
+
+// WithHTTPClient makes requests with the given HTTP client.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithTransport makes requests with an HTTP client using the given transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.HTTPClient = &http.Client{Transport: transport}
+	}
+}
+
+// WithCacheDir caches data in dir instead of the default cache directory. An empty dir disables caching.
+func WithCacheDir(dir string) Option {
+	return func(c *Client) {
+		if dir == "" {
+			c.Cache = nil
+			return
+		}
+
+		c.Cache = cache.NewStore(dir)
+	}
+}
+
+// WithClock uses now instead of time.Now to get the current time, including for cache expiry.
+func WithClock(now func() time.Time) Option {
+	return func(c *Client) {
+		c.Now = now
+	}
+}
+
+// NewClient returns a client authenticating with apiKey. An empty apiURL selects DefaultAPIURL.
+func NewClient(apiURL, apiKey string, options ...Option) *Client {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+
+	client := &Client{
+		APIURL: apiURL,
+		APIKey: apiKey,
+		Cache:  cache.NewStore(filepath.Join(cache.DefaultDir(), "linear")),
+	}
+
+	for _, option := range options {
+		option(client)
+	}
+
+	if client.Cache != nil && client.Now != nil {
+		client.Cache.Now = client.Now
+	}
+
+	return client
+}
+
+// now returns the current time according to the client's clock.
+func (c *Client) now() time.Time {
+	if c.Now == nil {
+		return time.Now()
+	}
+
+	return c.Now()
+}
+
+// Validate reports whether the client has everything it needs to authenticate.
+func (c *Client) Validate() error {
+	if _, err := url.ParseRequestURI(c.APIURL); err != nil {
+		return fmt.Errorf("invalid Linear API URL: %v", err)
+	}
+
+	if c.APIKey == "" {
+		return errors.New("the Linear API key is not set")
+	}
+
+	return nil
+}
+
+// shortHash returns an abbreviated SHA-256 hash of s, for use in file names.
+func shortHash(s string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(s)))[:16]
+}
+
+// viewerCacheName is the name of the cache file holding the ID of the user the API key belongs to
+const viewerCacheName = "viewer"
+
+// viewer returns the ID of the user the API key belongs to, or an empty string when it is unknown. IDs are
+// cached per API URL and looked up again once they expire, so that a replaced API key of the same user keeps
+// using the same cached data.
+func (c *Client) viewer() string {
+	if c.viewerID != nil {
+		return *c.viewerID
+	}
+
+	id := ""
+	if c.Cache != nil {
+		c.Cache.Fetch(shortHash(c.APIURL)+"/"+viewerCacheName, c.APIURL, &id, func() (any, error) {
+			var result struct {
+				Viewer struct {
+					ID string `json:"id"`
+				} `json:"viewer"`
+			}
+			if err := c.query("Viewer", "query Viewer { viewer { id } }", nil, &result); err != nil {
+				return nil, err
+			}
+
+			return result.Viewer.ID, nil
+		})
+	}
+
+	c.viewerID = &id
+
+	return id
+}
+
+// cacheName returns the name of a cache file belonging to the client's user, which is identified by the API URL
+// and the user's ID rather than the API key, so that replacing the key keeps the cached data. Each workspace has
+// its own users, so the data of different workspaces is kept apart too.
+func (c *Client) cacheName(name string) string {
+	return shortHash(c.APIURL+"\x00"+c.viewer()) + "/" + name
+}
+
+// graphQLRequest is the body of a request to a GraphQL API
+type graphQLRequest struct {
+	OperationName string         `json:"operationName"`
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// graphQLResponse is the body of a response from a GraphQL API
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []GraphQLError  `json:"errors"`
+}
+
+// GraphQLError is an error reported in the body of a GraphQL response
+type GraphQLError struct {
+	Message    string `json:"message"`
+	Extensions struct {
+		Code string `json:"code"`
+	} `json:"extensions"`
+}
+
+func (e GraphQLError) Error() string {
+	return "Linear API error: " + e.Message
+}
+
+// query runs a named GraphQL operation and decodes its data into result.
+func (c *Client) query(operationName string, query string, variables map[string]any, result any) error {
+	payload, err := json.Marshal(graphQLRequest{OperationName: operationName, Query: query, Variables: variables})
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %v", err)
+	}
+
+	body, err := c.do(payload)
+	if err != nil {
+		return err
+	}
+
+	var response graphQLResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+
+	if len(response.Errors) > 0 {
+		return response.Errors[0]
+	}
+
+	if err := json.Unmarshal(response.Data, result); err != nil {
+		return fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+
+	return nil
+}
+
+// do posts a GraphQL request to the API and returns the response body.
+func (c *Client) do(payload []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.APIURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %v", err)
+	}
+
+	// personal API keys are sent as they are, OAuth access tokens as bearer tokens
+	if strings.HasPrefix(c.APIKey, "lin_oauth_") {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	} else {
+		req.Header.Set("Authorization", c.APIKey)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute HTTP request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	// Linear reports GraphQL errors with a 400 status, so prefer the message in the body when there is one
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var response graphQLResponse
+		if json.Unmarshal(body, &response) == nil && len(response.Errors) > 0 {
+			return nil, &APIError{StatusCode: resp.StatusCode, Body: response.Errors[0].Message}
+		}
+
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	return body, nil
+}
+
+// APIError is returned when the Linear API responds with an unsuccessful status code
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Linear API returned status %d: %s", e.StatusCode, e.Body)
+}
@@ -0,0 +1,26 @@
+package linear_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/linear"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/linear/lineartest"
+)
+
+func TestClientReportsTheGraphQLErrorOfFailedRequests(t *testing.T) {
+	server := lineartest.NewServer(lineartest.NewIssues("ENG", 3)...)
+	defer server.Close()
+
+	_, err := linear.NewClient(server.URL, "lin_api_wrong", linear.WithCacheDir("")).ActiveIssues(false)
+
+	var apiErr *linear.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected a 401 APIError, got %v", err)
+	}
+
+	if apiErr.Body != "Authentication required, not authenticated" {
+		t.Errorf("expected the message of the GraphQL error, got %q", apiErr.Body)
+	}
+}
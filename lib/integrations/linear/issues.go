@@ -0,0 +1,401 @@
+package linear
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/cache"
+)
+
+// State types reported by Linear, independent of the names of a team's workflow states
+const (
+	StateTriage    = "triage"
+	StateBacklog   = "backlog"
+	StateUnstarted = "unstarted"
+	StateStarted   = "started"
+	StateCompleted = "completed"
+	StateCanceled  = "canceled"
+)
+
+// pageSize is the number of issues requested per page
+const pageSize = 100
+
+// maxResults limits the number of issues fetched by a single query
+const maxResults = 1000
+
+// Issue is a single Linear issue
+type Issue struct {
+	// Identifier is the issue's human-readable key, e.g. "ENG-123"
+	Identifier string `json:"identifier"`
+	Title      string `json:"title"`
+	State      string `json:"state"`
+	// StateType is the type of the issue's workflow state, e.g. "started"
+	StateType string    `json:"state_type"`
+	Priority  string    `json:"priority"`
+	Assignee  string    `json:"assignee"`
+	Updated   time.Time `json:"updated"`
+	URL       string    `json:"url"`
+	// BranchName is the git branch name Linear suggests for the issue
+	BranchName string `json:"branch_name"`
+}
+
+// IsDone reports whether the issue is completed or canceled.
+func (i Issue) IsDone() bool {
+	return i.StateType == StateCompleted || i.StateType == StateCanceled
+}
+
+// IsInProgress reports whether work on the issue has started.
+func (i Issue) IsInProgress() bool {
+	return i.StateType == StateStarted
+}
+
+// issueFields is the GraphQL fragment selecting the fields of an issue
+const issueFields = `
+fragment IssueFields on Issue {
+  identifier
+  title
+  url
+  branchName
+  priorityLabel
+  updatedAt
+  state { name type }
+  assignee { displayName }
+}`
+
+// linearIssueResponse represents an issue in Linear's API responses
+type linearIssueResponse struct {
+	Identifier    string `json:"identifier"`
+	Title         string `json:"title"`
+	URL           string `json:"url"`
+	BranchName    string `json:"branchName"`
+	PriorityLabel string `json:"priorityLabel"`
+	UpdatedAt     string `json:"updatedAt"`
+	State         struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"state"`
+	Assignee *struct {
+		DisplayName string `json:"displayName"`
+	} `json:"assignee"`
+}
+
+// linearIssueConnection represents a page of issues in Linear's API responses
+type linearIssueConnection struct {
+	Nodes    []linearIssueResponse `json:"nodes"`
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+}
+
+func (r linearIssueResponse) toIssue() Issue {
+	issue := Issue{
+		Identifier: r.Identifier,
+		Title:      r.Title,
+		State:      r.State.Name,
+		StateType:  r.State.Type,
+		URL:        r.URL,
+		BranchName: r.BranchName,
+	}
+
+	// issues without a priority are labelled "No priority"
+	if r.PriorityLabel != "" && !strings.EqualFold(r.PriorityLabel, "No priority") {
+		issue.Priority = r.PriorityLabel
+	}
+
+	if r.Assignee != nil {
+		issue.Assignee = r.Assignee.DisplayName
+	}
+
+	if updated, err := time.Parse(time.RFC3339, r.UpdatedAt); err == nil {
+		issue.Updated = updated
+	}
+
+	return issue
+}
+
+const activeIssuesQuery = `
+query ActiveIssues($filter: IssueFilter, $first: Int, $after: String) {
+  viewer {
+    assignedIssues(filter: $filter, first: $first, after: $after, orderBy: updatedAt) {
+      nodes { ...IssueFields }
+      pageInfo { hasNextPage endCursor }
+    }
+  }
+}` + issueFields
+
+// activeIssuesFilter selects the unstarted and started issues, in an active cycle when currentCycle is set.
+func activeIssuesFilter(currentCycle bool) map[string]any {
+	filter := map[string]any{
+		"state": map[string]any{"type": map[string]any{"in": []string{StateUnstarted, StateStarted}}},
+	}
+
+	if currentCycle {
+		filter["cycle"] = map[string]any{"isActive": map[string]any{"eq": true}}
+	}
+
+	return filter
+}
+
+// ActiveIssues returns the unstarted and started issues assigned to the current user, most recently updated
+// first. When currentCycle is set, only issues in their team's active cycle are returned. The results are
+// cached until the cache's TTL expires.
+func (c *Client) ActiveIssues(currentCycle bool) ([]Issue, error) {
+	issues, _, err := c.ActiveIssuesWithMetadata(currentCycle)
+
+	return issues, err
+}
+
+// ActiveIssuesWithMetadata returns the current user's active issues like ActiveIssues, and the metadata of the
+// cached results, which describes their age and whether they are stale because fetching them again failed.
+func (c *Client) ActiveIssuesWithMetadata(currentCycle bool) ([]Issue, *cache.Metadata, error) {
+	key := fmt.Sprintf("active issues (current cycle: %t)", currentCycle)
+
+	if c.Cache == nil {
+		issues, err := c.fetchActiveIssues(currentCycle)
+		return issues, &cache.Metadata{Key: key, FetchedAt: c.now()}, err
+	}
+
+	var issues []Issue
+
+	meta, err := c.Cache.Fetch(c.cacheName("active-"+strconv.FormatBool(currentCycle)), key, &issues, func() (any, error) {
+		return c.fetchActiveIssues(currentCycle)
+	})
+
+	return issues, meta, err
+}
+
+// fetchActiveIssues queries Linear for the current user's active issues, following the pages of results.
+func (c *Client) fetchActiveIssues(currentCycle bool) ([]Issue, error) {
+	result := make([]Issue, 0)
+	variables := map[string]any{"filter": activeIssuesFilter(currentCycle), "first": pageSize}
+
+	for len(result) < maxResults {
+		var data struct {
+			Viewer struct {
+				AssignedIssues linearIssueConnection `json:"assignedIssues"`
+			} `json:"viewer"`
+		}
+
+		if err := c.query("ActiveIssues", activeIssuesQuery, variables, &data); err != nil {
+			return nil, err
+		}
+
+		page := data.Viewer.AssignedIssues
+		for _, node := range page.Nodes {
+			result = append(result, node.toIssue())
+		}
+
+		if !page.PageInfo.HasNextPage || page.PageInfo.EndCursor == "" {
+			break
+		}
+
+		variables["after"] = page.PageInfo.EndCursor
+	}
+
+	return result, nil
+}
+
+const issueQuery = `
+query Issue($id: String!) {
+  issue(id: $id) { ...IssueFields }
+}` + issueFields
+
+// GetIssue fetches a single issue by its identifier, e.g. "ENG-123".
+func (c *Client) GetIssue(identifier string) (*Issue, error) {
+	var data struct {
+		Issue *linearIssueResponse `json:"issue"`
+	}
+
+	if err := c.query("Issue", issueQuery, map[string]any{"id": identifier}, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Issue == nil {
+		return nil, fmt.Errorf("issue %s not found", identifier)
+	}
+
+	issue := data.Issue.toIssue()
+
+	return &issue, nil
+}
+
+// DefaultIdentifierPattern matches Linear issue identifiers such as "ENG-123" in any case, since the branch
+// names Linear suggests are lowercase, e.g. "jane/eng-123-fix-login". The identifier is the first group.
+const DefaultIdentifierPattern = `(?i)(?:^|[^a-z0-9])([a-z][a-z0-9]{0,6}-[0-9]+)`
+
+// IdentifierPattern matches Linear issue identifiers in branch names
+var IdentifierPattern = regexp.MustCompile(DefaultIdentifierPattern)
+
+// ExtractIdentifier returns the first issue identifier found in s, such as a branch name, in uppercase, or an
+// empty string. When teams are given, only identifiers of those teams' issues are returned.
+func ExtractIdentifier(s string, teams []string) string {
+	for _, match := range IdentifierPattern.FindAllStringSubmatch(s, -1) {
+		identifier := strings.ToUpper(match[1])
+		team, _, _ := ParseIdentifier(identifier)
+
+		if len(teams) == 0 || containsFold(teams, team) {
+			return identifier
+		}
+	}
+
+	return ""
+}
+
+// ParseIdentifier splits an issue identifier such as "ENG-123" into its team key and issue number.
+func ParseIdentifier(identifier string) (team string, number int, ok bool) {
+	team, numberText, found := strings.Cut(identifier, "-")
+	if !found || team == "" {
+		return "", 0, false
+	}
+
+	number, err := strconv.Atoi(numberText)
+	if err != nil {
+		return "", 0, false
+	}
+
+	return strings.ToUpper(team), number, true
+}
+
+// containsFold reports whether items contains s, ignoring case.
+func containsFold(items []string, s string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// issueCacheEntry is an issue looked up by identifier. Issue is nil when no issue exists with that identifier.
+type issueCacheEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Issue     *Issue    `json:"issue"`
+}
+
+// issueLookupCacheName is the name of the cache file holding issues looked up by identifier
+const issueLookupCacheName = "issues"
+
+const lookupIssuesQuery = `
+query LookupIssues($filter: IssueFilter, $first: Int) {
+  issues(filter: $filter, first: $first) {
+    nodes { ...IssueFields }
+  }
+}` + issueFields
+
+// LookupIssues returns the issues with the given identifiers, keyed by identifier. Identifiers without an issue
+// are omitted. Issues are cached for the cache's TTL, and the remaining ones are fetched with a single query per
+// hundred identifiers. When the query fails, expired cached issues are returned along with the error.
+func (c *Client) LookupIssues(identifiers []string) (map[string]*Issue, error) {
+	cached := make(map[string]*issueCacheEntry)
+	if c.Cache != nil {
+		c.Cache.Read(c.cacheName(issueLookupCacheName), &cached)
+	}
+
+	result := make(map[string]*Issue)
+	missing := make([]string, 0)
+	seen := make(map[string]bool)
+	now := c.now()
+
+	for _, identifier := range identifiers {
+		if seen[identifier] {
+			continue
+		}
+		seen[identifier] = true
+
+		entry, ok := cached[identifier]
+		if ok && c.Cache != nil && (c.Cache.TTL == 0 || now.Sub(entry.Timestamp) < c.Cache.TTL) {
+			if entry.Issue != nil {
+				result[identifier] = entry.Issue
+			}
+			continue
+		}
+
+		missing = append(missing, identifier)
+	}
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	found, err := c.fetchIssuesByIdentifier(missing)
+	if err != nil {
+		// fall back to stale cached data rather than failing
+		for _, identifier := range missing {
+			if entry, ok := cached[identifier]; ok && entry.Issue != nil && now.Sub(entry.Timestamp) < c.Cache.TTL+c.Cache.MaxStale {
+				result[identifier] = entry.Issue
+			}
+		}
+		return result, err
+	}
+
+	for _, identifier := range missing {
+		if found[identifier] != nil {
+			result[identifier] = found[identifier]
+		}
+	}
+
+	if c.Cache == nil {
+		return result, nil
+	}
+
+	// merge with issues cached by other processes in the meantime, dropping entries that can no longer be used;
+	// proceed without failing if the cache can't be written, as we have the issues
+	c.Cache.Update(c.cacheName(issueLookupCacheName), issueLookupCacheName, &cached, func() error {
+		for identifier, entry := range cached {
+			if c.Cache.TTL > 0 && now.Sub(entry.Timestamp) >= c.Cache.TTL+c.Cache.MaxStale {
+				delete(cached, identifier)
+			}
+		}
+
+		for _, identifier := range missing {
+			cached[identifier] = &issueCacheEntry{Timestamp: now, Issue: found[identifier]}
+		}
+
+		return nil
+	})
+
+	return result, nil
+}
+
+// fetchIssuesByIdentifier queries Linear for issues by team key and number. Invalid identifiers are ignored.
+func (c *Client) fetchIssuesByIdentifier(identifiers []string) (map[string]*Issue, error) {
+	result := make(map[string]*Issue)
+
+	conditions := make([]map[string]any, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		team, number, ok := ParseIdentifier(identifier)
+		if !ok {
+			continue
+		}
+
+		conditions = append(conditions, map[string]any{
+			"team":   map[string]any{"key": map[string]any{"eq": team}},
+			"number": map[string]any{"eq": number},
+		})
+	}
+
+	for start := 0; start < len(conditions); start += pageSize {
+		batch := conditions[start:min(start+pageSize, len(conditions))]
+
+		var data struct {
+			Issues linearIssueConnection `json:"issues"`
+		}
+
+		variables := map[string]any{"filter": map[string]any{"or": batch}, "first": pageSize}
+		if err := c.query("LookupIssues", lookupIssuesQuery, variables, &data); err != nil {
+			return nil, err
+		}
+
+		for _, node := range data.Issues.Nodes {
+			issue := node.toIssue()
+			result[issue.Identifier] = &issue
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package linear_test
+
+import (
+	"testing"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/linear"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/linear/lineartest"
+)
+
+func TestActiveIssuesInTheCurrentCycle(t *testing.T) {
+	issues := lineartest.NewIssues("ENG", 5)
+	issues[1].InCycle = false
+	issues[2].State, issues[2].StateType = "Done", linear.StateCompleted
+	issues[3].State, issues[3].StateType = "In Progress", linear.StateStarted
+
+	server := lineartest.NewServer(issues...)
+	defer server.Close()
+
+	active, err := server.Client().ActiveIssues(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(active) != 3 || active[1].Identifier != "ENG-4" || !active[1].IsInProgress() {
+		t.Errorf("expected ENG-1, ENG-4 and ENG-5, got %+v", active)
+	}
+
+	all, err := server.Client().ActiveIssues(false)
+	if err != nil || len(all) != 4 {
+		t.Errorf("expected 4 active issues outside the cycle filter, got %d (%v)", len(all), err)
+	}
+}
+
+func TestActiveIssuesFollowsPages(t *testing.T) {
+	server := lineartest.NewServer(lineartest.NewIssues("ENG", 120)...)
+	defer server.Close()
+	server.PageSize = 25
+
+	issues, err := server.Client().ActiveIssues(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(issues) != 120 || issues[119].Identifier != "ENG-120" {
+		t.Errorf("expected 120 issues ending with ENG-120, got %d", len(issues))
+	}
+
+	if count := server.RequestCount("ActiveIssues"); count != 5 {
+		t.Errorf("expected 5 requests, got %d", count)
+	}
+}
+
+func TestLookupIssuesInOneBatch(t *testing.T) {
+	server := lineartest.NewServer(append(lineartest.NewIssues("ENG", 10), lineartest.NewIssues("OPS", 2)...)...)
+	defer server.Close()
+
+	client, _ := server.CachedClient(t)
+
+	issues, err := client.LookupIssues([]string{"ENG-2", "OPS-2", "ENG-404", "ENG-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(issues) != 2 || issues["ENG-2"] == nil || issues["OPS-2"] == nil {
+		t.Errorf("expected ENG-2 and OPS-2, got %v", issues)
+	}
+
+	// unknown identifiers are cached too
+	client.LookupIssues([]string{"OPS-2", "ENG-404"})
+
+	if count := server.RequestCount("LookupIssues"); count != 1 {
+		t.Errorf("expected 1 lookup request, got %d", count)
+	}
+}
+
+func TestExtractIdentifier(t *testing.T) {
+	tests := []struct {
+		branch string
+		teams  []string
+		want   string
+	}{
+		{"dev/eng-123-fix-login", nil, "ENG-123"},
+		{"ENG-7", nil, "ENG-7"},
+		{"feature/OPS-42-deploy", []string{"eng"}, ""},
+		{"release-2024/eng-9-notes", []string{"ENG"}, "ENG-9"},
+		{"main", nil, ""},
+	}
+
+	for _, test := range tests {
+		if got := linear.ExtractIdentifier(test.branch, test.teams); got != test.want {
+			t.Errorf("expected %q for %s with teams %v, got %q", test.want, test.branch, test.teams, got)
+		}
+	}
+}
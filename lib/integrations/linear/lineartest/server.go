@@ -0,0 +1,361 @@
+// Package lineartest provides a fake Linear GraphQL API server for testing code that uses the linear package
+// without contacting Linear.
+package lineartest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/cache/cachetest"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/internal/apitest"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/linear"
+)
+
+// APIKey is the API key accepted by a Server unless it is changed
+const APIKey = "lin_api_test"
+
+// ViewerID is the ID of the user of the API key accepted by a Server unless it is changed
+const ViewerID = "user-1"
+
+// Workflow lists the workflow states of every team, with their types
+var Workflow = []linear.State{
+	{ID: "state-backlog", Name: "Backlog", Type: linear.StateBacklog},
+	{ID: "state-todo", Name: "Todo", Type: linear.StateUnstarted},
+	{ID: "state-in-progress", Name: "In Progress", Type: linear.StateStarted},
+	{ID: "state-in-review", Name: "In Review", Type: linear.StateStarted},
+	{ID: "state-done", Name: "Done", Type: linear.StateCompleted},
+	{ID: "state-canceled", Name: "Canceled", Type: linear.StateCanceled},
+}
+
+// Issue is an issue served by a Server, assigned to the current user
+type Issue struct {
+	linear.Issue
+	// InCycle reports whether the issue belongs to its team's active cycle
+	InCycle bool
+}
+
+// Server is a fake Linear instance serving the GraphQL operations used by the linear package: Viewer, ActiveIssues,
+// Issue, LookupIssues, TeamStates and UpdateIssueState. Operations are recognized by their operation name
+// rather than by parsing the query, and requests are counted by operation name, e.g. "ActiveIssues". It accepts the
+// API key APIKey, sent as is or as a bearer token.
+type Server struct {
+	*apitest.Server
+
+	// Issues are the issues served, most recently updated first
+	Issues []Issue
+	// PageSize caps the number of issues in a page of results
+	PageSize int
+	// APIKey is the accepted API key
+	APIKey string
+	// ViewerID is the ID of the user the API key belongs to
+	ViewerID string
+}
+
+// NewServer starts a server serving the given issues. Call Close when done.
+func NewServer(issues ...Issue) *Server {
+	s := &Server{Issues: issues, PageSize: 50, APIKey: APIKey, ViewerID: ViewerID}
+	s.Server = apitest.NewServer(s.handle)
+
+	return s
+}
+
+// NewIssues returns count issues of team, numbered from 1, in the "Todo" state and in the active cycle.
+func NewIssues(team string, count int) []Issue {
+	result := make([]Issue, 0, count)
+
+	for i := 1; i <= count; i++ {
+		identifier := fmt.Sprintf("%s-%d", team, i)
+
+		result = append(result, Issue{
+			Issue: linear.Issue{
+				Identifier: identifier,
+				Title:      fmt.Sprintf("Issue %d", i),
+				State:      Workflow[1].Name,
+				StateType:  Workflow[1].Type,
+				Priority:   "Medium",
+				Assignee:   "Dev",
+				Updated:    time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+				BranchName: fmt.Sprintf("dev/%s-issue-%d", strings.ToLower(identifier), i),
+			},
+			InCycle: true,
+		})
+	}
+
+	return result
+}
+
+// Client returns a client for the server, with caching disabled unless an option enables it.
+func (s *Server) Client(options ...linear.Option) *linear.Client {
+	return linear.NewClient(s.URL, s.APIKey, append([]linear.Option{linear.WithCacheDir("")}, options...)...)
+}
+
+// CachedClient returns a client for the server caching in a temporary directory of the test, with a clock the
+// test advances to expire cached data.
+func (s *Server) CachedClient(tb testing.TB, options ...linear.Option) (*linear.Client, *cachetest.Clock) {
+	cached, clock := apitest.CachedOptions(tb, linear.WithCacheDir, linear.WithClock)
+
+	return s.Client(append(cached, options...)...), clock
+}
+
+// Issue returns the current state of an issue, or nil if it doesn't exist.
+func (s *Server) Issue(identifier string) *Issue {
+	s.Lock()
+	defer s.Unlock()
+
+	if issue := s.findIssue(identifier); issue != nil {
+		copied := *issue
+		return &copied
+	}
+
+	return nil
+}
+
+func (s *Server) findIssue(identifier string) *Issue {
+	for i := range s.Issues {
+		if strings.EqualFold(s.Issues[i].Identifier, identifier) {
+			return &s.Issues[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+
+	return header == s.APIKey || header == "Bearer "+s.APIKey
+}
+
+// request is the body of a GraphQL request
+type request struct {
+	OperationName string          `json:"operationName"`
+	Variables     json.RawMessage `json:"variables"`
+}
+
+// filter is the subset of Linear's IssueFilter understood by the server
+type filter struct {
+	State *struct {
+		Type struct {
+			In []string `json:"in"`
+		} `json:"type"`
+	} `json:"state"`
+	Cycle *struct {
+		IsActive struct {
+			Eq bool `json:"eq"`
+		} `json:"isActive"`
+	} `json:"cycle"`
+	Team *struct {
+		Key struct {
+			Eq string `json:"eq"`
+		} `json:"key"`
+	} `json:"team"`
+	Number *struct {
+		Eq int `json:"eq"`
+	} `json:"number"`
+	Or []filter `json:"or"`
+}
+
+// matches reports whether an issue satisfies every condition of the filter.
+func (f *filter) matches(issue Issue) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.State != nil && !containsString(f.State.Type.In, issue.StateType) {
+		return false
+	}
+
+	if f.Cycle != nil && issue.InCycle != f.Cycle.IsActive.Eq {
+		return false
+	}
+
+	team, number, _ := linear.ParseIdentifier(issue.Identifier)
+
+	if f.Team != nil && !strings.EqualFold(f.Team.Key.Eq, team) {
+		return false
+	}
+
+	if f.Number != nil && f.Number.Eq != number {
+		return false
+	}
+
+	if len(f.Or) == 0 {
+		return true
+	}
+
+	for i := range f.Or {
+		if f.Or[i].matches(issue) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErrors(w, http.StatusMethodNotAllowed, "only POST requests are supported")
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrors(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if status := s.Receive(req.OperationName); status != 0 {
+		writeErrors(w, status, "simulated failure")
+		return
+	}
+
+	if !s.authorized(r) {
+		writeErrors(w, http.StatusUnauthorized, "Authentication required, not authenticated")
+		return
+	}
+
+	var variables struct {
+		Filter  *filter `json:"filter"`
+		First   int     `json:"first"`
+		After   string  `json:"after"`
+		ID      string  `json:"id"`
+		Key     string  `json:"key"`
+		StateID string  `json:"stateId"`
+	}
+	if len(req.Variables) > 0 {
+		if err := json.Unmarshal(req.Variables, &variables); err != nil {
+			writeErrors(w, http.StatusBadRequest, "invalid variables")
+			return
+		}
+	}
+
+	switch req.OperationName {
+	case "Viewer":
+		writeData(w, map[string]any{"viewer": map[string]any{"id": s.ViewerID}})
+	case "ActiveIssues":
+		page := s.page(variables.Filter, variables.First, variables.After)
+		writeData(w, map[string]any{"viewer": map[string]any{"assignedIssues": page}})
+	case "LookupIssues":
+		page := s.page(variables.Filter, variables.First, "")
+		writeData(w, map[string]any{"issues": page})
+	case "Issue":
+		issue := s.findIssue(variables.ID)
+		if issue == nil {
+			writeErrors(w, http.StatusOK, "Entity not found: Issue")
+			return
+		}
+
+		writeData(w, map[string]any{"issue": issueResponse(*issue)})
+	case "TeamStates":
+		teams := make([]any, 0)
+		for _, issue := range s.Issues {
+			if team, _, _ := linear.ParseIdentifier(issue.Identifier); strings.EqualFold(team, variables.Key) {
+				teams = append(teams, map[string]any{"key": team, "states": map[string]any{"nodes": Workflow}})
+				break
+			}
+		}
+
+		writeData(w, map[string]any{"teams": map[string]any{"nodes": teams}})
+	case "UpdateIssueState":
+		s.updateIssueState(w, variables.ID, variables.StateID)
+	default:
+		writeErrors(w, http.StatusBadRequest, fmt.Sprintf("unknown operation '%s'", req.OperationName))
+	}
+}
+
+// page returns a page of the issues matched by a filter, in the structure of an IssueConnection.
+func (s *Server) page(f *filter, first int, after string) map[string]any {
+	if first <= 0 || first > s.PageSize {
+		first = s.PageSize
+	}
+
+	start, _ := strconv.Atoi(after)
+
+	matched := make([]Issue, 0)
+	for _, issue := range s.Issues {
+		if f.matches(issue) {
+			matched = append(matched, issue)
+		}
+	}
+
+	nodes := make([]any, 0)
+	for i := start; i < len(matched) && i < start+first; i++ {
+		nodes = append(nodes, issueResponse(matched[i]))
+	}
+
+	end := min(start+first, len(matched))
+
+	return map[string]any{
+		"nodes":    nodes,
+		"pageInfo": map[string]any{"hasNextPage": end < len(matched), "endCursor": strconv.Itoa(end)},
+	}
+}
+
+func (s *Server) updateIssueState(w http.ResponseWriter, identifier string, stateID string) {
+	issue := s.findIssue(identifier)
+	if issue == nil {
+		writeErrors(w, http.StatusOK, "Entity not found: Issue")
+		return
+	}
+
+	for _, state := range Workflow {
+		if state.ID == stateID {
+			issue.State, issue.StateType = state.Name, state.Type
+			writeData(w, map[string]any{"issueUpdate": map[string]any{"success": true}})
+			return
+		}
+	}
+
+	writeErrors(w, http.StatusBadRequest, "Argument Validation Error: stateId is not a valid workflow state")
+}
+
+// issueResponse returns an issue in the structure used by Linear's API responses.
+func issueResponse(issue Issue) map[string]any {
+	response := map[string]any{
+		"identifier":    issue.Identifier,
+		"title":         issue.Title,
+		"url":           "https://linear.app/example/issue/" + issue.Identifier,
+		"branchName":    issue.BranchName,
+		"priorityLabel": "No priority",
+		"updatedAt":     issue.Updated.Format(time.RFC3339),
+		"state":         map[string]any{"name": issue.State, "type": issue.StateType},
+		"assignee":      nil,
+	}
+
+	if issue.Priority != "" {
+		response["priorityLabel"] = issue.Priority
+	}
+
+	if issue.Assignee != "" {
+		response["assignee"] = map[string]any{"displayName": issue.Assignee}
+	}
+
+	return response
+}
+
+func containsString(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeData(w http.ResponseWriter, data any) {
+	writeJSON(w, http.StatusOK, map[string]any{"data": data})
+}
+
+// writeJSON writes the responses of the fake API
+var writeJSON = apitest.WriteJSON
+
+// writeErrors writes a response reporting a GraphQL error.
+func writeErrors(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{"errors": []map[string]any{{"message": message}}})
+}
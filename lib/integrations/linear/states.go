@@ -0,0 +1,153 @@
+package linear
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+)
+
+// State is a workflow state of a team
+type State struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Type is the state's type, e.g. "started"
+	Type string `json:"type"`
+}
+
+// stateCacheName is the name of the cache file holding the workflow states of each team, keyed by team key.
+// States rarely change, so they are kept until an issue can't be moved to a cached state.
+const stateCacheName = "states"
+
+// ErrTransitionNotFound is returned when an issue's team has no workflow state with the requested name
+var ErrTransitionNotFound = integrations.ErrTransitionNotFound
+
+const teamStatesQuery = `
+query TeamStates($key: String!) {
+  teams(filter: { key: { eq: $key } }) {
+    nodes {
+      key
+      states { nodes { id name type } }
+    }
+  }
+}`
+
+// GetStates returns the workflow states of the team with the given key, e.g. "ENG".
+func (c *Client) GetStates(team string) ([]State, error) {
+	var data struct {
+		Teams struct {
+			Nodes []struct {
+				Key    string `json:"key"`
+				States struct {
+					Nodes []State `json:"nodes"`
+				} `json:"states"`
+			} `json:"nodes"`
+		} `json:"teams"`
+	}
+
+	if err := c.query("TeamStates", teamStatesQuery, map[string]any{"key": team}, &data); err != nil {
+		return nil, err
+	}
+
+	for _, node := range data.Teams.Nodes {
+		if strings.EqualFold(node.Key, team) {
+			return node.States.Nodes, nil
+		}
+	}
+
+	return nil, fmt.Errorf("team %s not found", team)
+}
+
+const updateIssueStateMutation = `
+mutation UpdateIssueState($id: String!, $stateId: String!) {
+  issueUpdate(id: $id, input: { stateId: $stateId }) {
+    success
+  }
+}`
+
+// SetState moves an issue to the workflow state with the given ID.
+func (c *Client) SetState(identifier string, stateID string) error {
+	var data struct {
+		IssueUpdate struct {
+			Success bool `json:"success"`
+		} `json:"issueUpdate"`
+	}
+
+	if err := c.query("UpdateIssueState", updateIssueStateMutation, map[string]any{"id": identifier, "stateId": stateID}, &data); err != nil {
+		return err
+	}
+
+	if !data.IssueUpdate.Success {
+		return fmt.Errorf("failed to update %s", identifier)
+	}
+
+	return nil
+}
+
+// TransitionIssue moves an issue to the workflow state of its team named name. Linear allows moving issues
+// between any of a team's states, so ErrTransitionNotFound is only returned when the team has no such state.
+// Cached states are tried first; when the update is rejected, the team's states are fetched again.
+func (c *Client) TransitionIssue(identifier string, name string) error {
+	team, _, ok := ParseIdentifier(identifier)
+	if !ok {
+		return fmt.Errorf("invalid issue identifier '%s'", identifier)
+	}
+
+	states := make(map[string][]State)
+	if c.Cache != nil {
+		c.Cache.Read(c.cacheName(stateCacheName), &states)
+	}
+
+	if state := findState(states[team], name); state != nil {
+		err := c.SetState(identifier, state.ID)
+
+		var graphQLErr GraphQLError
+		var apiErr *APIError
+		if err == nil || !(errors.As(err, &graphQLErr) || errors.As(err, &apiErr)) {
+			return err
+		}
+
+		// the state may have been removed from the team's workflow
+	}
+
+	teamStates, err := c.GetStates(team)
+	if err != nil {
+		return err
+	}
+
+	c.updateStateCache(team, teamStates)
+
+	state := findState(teamStates, name)
+	if state == nil {
+		return fmt.Errorf("%w: team %s has no state named '%s'", ErrTransitionNotFound, team, name)
+	}
+
+	return c.SetState(identifier, state.ID)
+}
+
+// findState returns the state named name, ignoring case, or nil.
+func findState(states []State, name string) *State {
+	for i := range states {
+		if strings.EqualFold(states[i].Name, name) {
+			return &states[i]
+		}
+	}
+
+	return nil
+}
+
+// updateStateCache stores the workflow states of a team. Failures to write the cache are ignored, as the
+// states are fetched again when needed.
+func (c *Client) updateStateCache(team string, teamStates []State) {
+	if c.Cache == nil {
+		return
+	}
+
+	states := make(map[string][]State)
+
+	c.Cache.Update(c.cacheName(stateCacheName), stateCacheName, &states, func() error {
+		states[team] = teamStates
+		return nil
+	})
+}
@@ -0,0 +1,36 @@
+package linear_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/permafrost-dev/git-ninja/lib/integrations/linear"
+	"github.com/permafrost-dev/git-ninja/lib/integrations/linear/lineartest"
+)
+
+func TestTransitionIssueToNamedStates(t *testing.T) {
+	server := lineartest.NewServer(lineartest.NewIssues("ENG", 1)...)
+	defer server.Close()
+
+	client, _ := server.CachedClient(t)
+
+	if err := client.TransitionIssue("ENG-1", "in progress"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.TransitionIssue("ENG-1", "In Review"); err != nil {
+		t.Fatal(err)
+	}
+
+	if state := server.Issue("ENG-1").State; state != "In Review" {
+		t.Errorf("expected ENG-1 to be In Review, got %s", state)
+	}
+
+	if count := server.RequestCount("TeamStates"); count != 1 {
+		t.Errorf("expected the team's states to be fetched once, got %d", count)
+	}
+
+	if err := client.TransitionIssue("ENG-1", "Shipped"); !errors.Is(err, linear.ErrTransitionNotFound) {
+		t.Errorf("expected ErrTransitionNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package linear
+
+import (
+	"github.com/permafrost-dev/git-ninja/lib/integrations"
+)
+
+// Tracker makes a Client usable as an integrations.IssueTracker. Active issues are the current user's unstarted
+// and started issues, optionally limited to the active cycle.
+type Tracker struct {
+	Client *Client
+	// Teams limits the issue identifiers matched in branch names to those of the given team keys; any
+	// identifier is matched when it is empty
+	Teams []string
+	// CurrentCycle limits the active issues to those in their team's active cycle
+	CurrentCycle bool
+}
+
+// NewTracker returns an issue tracker using client.
+func NewTracker(client *Client, teams []string, currentCycle bool) *Tracker {
+	return &Tracker{Client: client, Teams: teams, CurrentCycle: currentCycle}
+}
+
+func (t *Tracker) Name() string {
+	return "linear"
+}
+
+func (t *Tracker) Validate() error {
+	return t.Client.Validate()
+}
+
+func (t *Tracker) ActiveIssues() ([]integrations.Issue, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	issues, err := t.Client.ActiveIssues(t.CurrentCycle)
+
+	result := make([]integrations.Issue, 0, len(issues))
+	for _, issue := range issues {
+		result = append(result, trackerIssue(issue))
+	}
+
+	return result, err
+}
+
+func (t *Tracker) GetIssue(key string) (*integrations.Issue, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	issue, err := t.Client.GetIssue(key)
+	if err != nil {
+		return nil, err
+	}
+
+	result := trackerIssue(*issue)
+
+	return &result, nil
+}
+
+func (t *Tracker) LookupIssues(keys []string) (map[string]*integrations.Issue, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	issues, err := t.Client.LookupIssues(keys)
+
+	result := make(map[string]*integrations.Issue, len(issues))
+	for key, issue := range issues {
+		converted := trackerIssue(*issue)
+		result[key] = &converted
+	}
+
+	return result, err
+}
+
+func (t *Tracker) TransitionIssue(key string, status string) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	return t.Client.TransitionIssue(key, status)
+}
+
+func (t *Tracker) MatchBranch(branchName string) string {
+	return ExtractIdentifier(branchName, t.Teams)
+}
+
+// stateCategories maps Linear's state types to the status categories of the integrations package
+var stateCategories = map[string]string{
+	StateTriage:    integrations.StatusToDo,
+	StateBacklog:   integrations.StatusToDo,
+	StateUnstarted: integrations.StatusToDo,
+	StateStarted:   integrations.StatusInProgress,
+	StateCompleted: integrations.StatusDone,
+	StateCanceled:  integrations.StatusDone,
+}
+
+func trackerIssue(issue Issue) integrations.Issue {
+	return integrations.Issue{
+		Key:            issue.Identifier,
+		Summary:        issue.Title,
+		Status:         issue.State,
+		StatusCategory: stateCategories[issue.StateType],
+		Priority:       issue.Priority,
+		Assignee:       issue.Assignee,
+		Updated:        issue.Updated,
+		URL:            issue.URL,
+		BranchName:     issue.BranchName,
+	}
+}
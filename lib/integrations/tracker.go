@@ -0,0 +1,130 @@
+// Package integrations defines the interface implemented by issue trackers, such as Jira, and the registry
+// used to choose one by name.
+package integrations
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Status categories of issues, independent of the names of a tracker's workflow statuses
+const (
+	StatusToDo       = "todo"
+	StatusInProgress = "in_progress"
+	StatusDone       = "done"
+)
+
+// Issue is an issue in an issue tracker
+type Issue struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
+	Status  string `json:"status"`
+	// StatusCategory is StatusToDo, StatusInProgress or StatusDone
+	StatusCategory string    `json:"status_category"`
+	Priority       string    `json:"priority"`
+	Assignee       string    `json:"assignee"`
+	Updated        time.Time `json:"updated"`
+	// URL is the address of the issue's page in the tracker's web interface
+	URL string `json:"url"`
+	// BranchName is the branch name the tracker suggests for the issue, if it suggests one
+	BranchName string `json:"branch_name,omitempty"`
+}
+
+// IsDone reports whether the issue's status belongs to the "done" category.
+func (i Issue) IsDone() bool {
+	return i.StatusCategory == StatusDone
+}
+
+// IsInProgress reports whether the issue's status belongs to the "in progress" category.
+func (i Issue) IsInProgress() bool {
+	return i.StatusCategory == StatusInProgress
+}
+
+// ErrTransitionNotFound is returned when an issue cannot be moved to the requested status
+var ErrTransitionNotFound = errors.New("transition not found")
+
+// IssueTracker is a source of issues that branches can be linked to
+type IssueTracker interface {
+	// Name returns the name the tracker is registered with, e.g. "jira"
+	Name() string
+	// Validate reports why the tracker can't be used, e.g. because credentials are missing
+	Validate() error
+	// ActiveIssues returns the current user's active issues, most relevant first
+	ActiveIssues() ([]Issue, error)
+	// GetIssue fetches a single issue by key
+	GetIssue(key string) (*Issue, error)
+	// TransitionIssue moves an issue to the named status, returning an error wrapping ErrTransitionNotFound
+	// when the issue's workflow doesn't allow it
+	TransitionIssue(key string, status string) error
+	// MatchBranch returns the key of the issue a branch is linked to by its name, or an empty string
+	MatchBranch(branchName string) string
+}
+
+// IssueLookup is implemented by trackers that can fetch several issues with a single request
+type IssueLookup interface {
+	// LookupIssues returns the issues with the given keys, keyed by issue key; unknown keys are omitted
+	LookupIssues(keys []string) (map[string]*Issue, error)
+}
+
+// LookupIssues returns the issues with the given keys, keyed by issue key, using a single request when the
+// tracker supports it. Unknown keys are omitted.
+func LookupIssues(tracker IssueTracker, keys []string) (map[string]*Issue, error) {
+	if lookup, ok := tracker.(IssueLookup); ok {
+		return lookup.LookupIssues(keys)
+	}
+
+	result := make(map[string]*Issue)
+
+	var errs []error
+	for _, key := range keys {
+		if _, ok := result[key]; ok {
+			continue
+		}
+
+		issue, err := tracker.GetIssue(key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		result[key] = issue
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// Factory creates an issue tracker from the current configuration
+type Factory func() IssueTracker
+
+var factories = make(map[string]Factory)
+
+// Register makes an issue tracker available under a name, such as "jira".
+func Register(name string, factory Factory) {
+	factories[strings.ToLower(name)] = factory
+}
+
+// Names returns the names of the registered issue trackers, sorted.
+func Names() []string {
+	result := make([]string, 0, len(factories))
+	for name := range factories {
+		result = append(result, name)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// New creates the issue tracker registered under name. The tracker may still be unusable, e.g. without
+// credentials, which its Validate method reports.
+func New(name string) (IssueTracker, error) {
+	factory, ok := factories[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown issue tracker '%s', expected one of: %s", name, strings.Join(Names(), ", "))
+	}
+
+	return factory(), nil
+}